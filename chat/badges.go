@@ -0,0 +1,303 @@
+package chat
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/aidenwallis/go-twitch-client/helix"
+	"github.com/aidenwallis/go-twitch-client/irc"
+)
+
+const (
+	// defaultBadgeRefreshInterval is how long badges are cached before they are refreshed.
+	defaultBadgeRefreshInterval = time.Hour
+
+	// badgeLoadTimeout is how long a background badge refresh may take.
+	badgeLoadTimeout = 30 * time.Second
+
+	// badgeRetryInterval is how long lookups wait before retrying a failed refresh, at most the refresh interval.
+	badgeRetryInterval = time.Minute
+)
+
+// ErrMissingHelix is returned when a type that loads data from Helix is created without a Helix client
+var ErrMissingHelix = errors.New("chat: helix client is required")
+
+// ResolvedBadge is a badge from a message resolved to its image and metadata
+type ResolvedBadge struct {
+	// SetID is the badge set, such as subscriber.
+	SetID string
+
+	// Version is the badge version, such as 3012.
+	Version string
+
+	// Info is the badge metadata from the badge-info tag, such as the exact number of subscribed months.
+	Info string
+
+	// Title of the badge.
+	Title string
+
+	// Description of the badge.
+	Description string
+
+	// ImageURL1x is the small image URL.
+	ImageURL1x string
+
+	// ImageURL2x is the medium image URL.
+	ImageURL2x string
+
+	// ImageURL4x is the large image URL.
+	ImageURL4x string
+
+	// ClickAction is the action to take when clicking on the badge, empty if none.
+	ClickAction string
+
+	// ClickURL is the URL to navigate to when clicking on the badge, empty if none.
+	ClickURL string
+}
+
+// BadgeResolverOptions defines all options the badge resolver supports.
+type BadgeResolverOptions struct {
+	// Helix is used to load the global and channel badges.
+	Helix helix.Client
+
+	// RefreshInterval (optional) is how long badges are cached before they are refreshed, defaults to 1 hour.
+	RefreshInterval time.Duration
+}
+
+// BadgeResolver resolves the badges of chat messages, merging the global badges with the badges of the channel. Channel
+// badges, such as custom subscriber badges, override global badges with the same set and version.
+//
+// Lookups never block on Helix. Badges that are missing or older than the refresh interval are loaded in the
+// background, and returned by later lookups. Failed refreshes are retried after a minute, or the refresh interval when
+// it is shorter. Call Load to warm the cache up front.
+type BadgeResolver struct {
+	helix           helix.Client
+	refreshInterval time.Duration
+	retryInterval   time.Duration
+	now             func() time.Time
+
+	mu      sync.RWMutex
+	entries map[string]*badgeEntry
+
+	// wg tracks background refreshes
+	wg sync.WaitGroup
+
+	onError func(err error)
+}
+
+// badgeSets maps badge set IDs and versions to their version details
+type badgeSets map[string]map[string]*helix.ChatBadgeVersion
+
+// badgeEntry is the cached badges of the global scope, or of a channel
+type badgeEntry struct {
+	sets     badgeSets
+	loadedAt time.Time
+	failedAt time.Time // zero unless the last refresh failed
+	loading  bool
+}
+
+// globalBadges is the entries key of the global badges, broadcaster IDs are never empty
+const globalBadges = ""
+
+// NewBadgeResolver creates a new instance of BadgeResolver
+func NewBadgeResolver(options *BadgeResolverOptions) *BadgeResolver {
+	if options == nil {
+		options = &BadgeResolverOptions{}
+	}
+
+	r := &BadgeResolver{
+		helix:           options.Helix,
+		refreshInterval: defaultBadgeRefreshInterval,
+		now:             time.Now,
+		entries:         map[string]*badgeEntry{},
+	}
+	if options.RefreshInterval > 0 {
+		r.refreshInterval = options.RefreshInterval
+	}
+	r.retryInterval = badgeRetryInterval
+	if r.refreshInterval < r.retryInterval {
+		r.retryInterval = r.refreshInterval
+	}
+	return r
+}
+
+// OnError sets the handler for errors of background refreshes.
+func (r *BadgeResolver) OnError(handler func(err error)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.onError = handler
+}
+
+// Load loads the global badges, and the badges of the broadcasters, blocking until they are cached.
+func (r *BadgeResolver) Load(ctx context.Context, broadcasterIDs ...string) error {
+	if err := r.load(ctx, globalBadges); err != nil {
+		return err
+	}
+	for _, broadcasterID := range broadcasterIDs {
+		if err := r.load(ctx, broadcasterID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Resolve resolves the badges of a message in the broadcaster's channel, info is the badge-info tag. Badges that are
+// not known yet are left out.
+func (r *BadgeResolver) Resolve(broadcasterID string, badges, info []irc.Badge) []*ResolvedBadge {
+	r.refreshIfStale(globalBadges)
+	r.refreshIfStale(broadcasterID)
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	resolved := make([]*ResolvedBadge, 0, len(badges))
+	for _, badge := range badges {
+		version := r.lookup(broadcasterID, badge.Name, badge.Version)
+		if version == nil {
+			continue
+		}
+
+		b := &ResolvedBadge{
+			SetID:       badge.Name,
+			Version:     badge.Version,
+			Title:       version.Title,
+			Description: version.Description,
+			ImageURL1x:  version.ImageURL1x,
+			ImageURL2x:  version.ImageURL2x,
+			ImageURL4x:  version.ImageURL4x,
+		}
+		for _, i := range info {
+			if i.Name == badge.Name {
+				b.Info = i.Version
+				break
+			}
+		}
+		if version.ClickAction != nil {
+			b.ClickAction = *version.ClickAction
+		}
+		if version.ClickURL != nil {
+			b.ClickURL = *version.ClickURL
+		}
+		resolved = append(resolved, b)
+	}
+	return resolved
+}
+
+// ResolveTags resolves the raw badges and badge-info tag values, such as subscriber/3012,moderator/1.
+func (r *BadgeResolver) ResolveTags(broadcasterID, badges, info string) []*ResolvedBadge {
+	return r.Resolve(broadcasterID, irc.ParseBadges(badges), irc.ParseBadges(info))
+}
+
+// lookup returns the badge version from the channel, falling back to the global badges. r.mu must be held.
+func (r *BadgeResolver) lookup(broadcasterID, setID, version string) *helix.ChatBadgeVersion {
+	if broadcasterID != globalBadges {
+		if entry := r.entries[broadcasterID]; entry != nil {
+			if v := entry.sets[setID][version]; v != nil {
+				return v
+			}
+		}
+	}
+	if entry := r.entries[globalBadges]; entry != nil {
+		return entry.sets[setID][version]
+	}
+	return nil
+}
+
+// refreshIfStale starts a background refresh of the scope, when it is missing or older than the refresh interval, and
+// the last refresh did not fail within the retry interval.
+func (r *BadgeResolver) refreshIfStale(key string) {
+	if r.helix == nil {
+		return
+	}
+
+	r.mu.Lock()
+	entry := r.entries[key]
+	if entry == nil {
+		entry = &badgeEntry{}
+		r.entries[key] = entry
+	}
+	now := r.now()
+	if entry.loading ||
+		(entry.sets != nil && now.Sub(entry.loadedAt) < r.refreshInterval) ||
+		(!entry.failedAt.IsZero() && now.Sub(entry.failedAt) < r.retryInterval) {
+		r.mu.Unlock()
+		return
+	}
+	entry.loading = true
+	r.mu.Unlock()
+
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+
+		ctx, cancel := context.WithTimeout(context.Background(), badgeLoadTimeout)
+		defer cancel()
+
+		if err := r.load(ctx, key); err != nil {
+			r.mu.RLock()
+			onError := r.onError
+			r.mu.RUnlock()
+			if onError != nil {
+				onError(err)
+			}
+		}
+	}()
+}
+
+// load fetches the badges of the scope, and caches them.
+func (r *BadgeResolver) load(ctx context.Context, key string) error {
+	sets, err := r.fetch(ctx, key)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entry := r.entries[key]
+	if entry == nil {
+		entry = &badgeEntry{}
+		r.entries[key] = entry
+	}
+	entry.loading = false
+	if err != nil {
+		// retry on a lookup after the retry interval, without discarding the badges that are still cached
+		entry.failedAt = r.now()
+		return err
+	}
+	entry.sets = sets
+	entry.loadedAt = r.now()
+	entry.failedAt = time.Time{}
+	return nil
+}
+
+func (r *BadgeResolver) fetch(ctx context.Context, key string) (badgeSets, error) {
+	if r.helix == nil {
+		return nil, ErrMissingHelix
+	}
+
+	var badges []*helix.ChatBadge
+	if key == globalBadges {
+		resp, err := r.helix.GetGlobalChatBadges(ctx, &helix.GetGlobalChatBadgesRequest{})
+		if err != nil {
+			return nil, err
+		}
+		badges = resp.Data
+	} else {
+		resp, err := r.helix.GetChannelChatBadges(ctx, &helix.GetChannelChatBadgesRequest{BroadcasterID: key})
+		if err != nil {
+			return nil, err
+		}
+		badges = resp.Data
+	}
+
+	sets := make(badgeSets, len(badges))
+	for _, badge := range badges {
+		versions := make(map[string]*helix.ChatBadgeVersion, len(badge.Versions))
+		for _, version := range badge.Versions {
+			versions[version.ID] = version
+		}
+		sets[badge.SetID] = versions
+	}
+	return sets, nil
+}
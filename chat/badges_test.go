@@ -0,0 +1,185 @@
+package chat
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aidenwallis/go-twitch-client/helix"
+	"github.com/aidenwallis/go-twitch-client/internal/testutils"
+	"github.com/aidenwallis/go-twitch-client/internal/testutils/assert"
+)
+
+// badgeAPI is a fake Helix API serving global and channel badges
+type badgeAPI struct {
+	t *testing.T
+
+	mu       sync.Mutex
+	requests int
+	fail     bool
+}
+
+func (a *badgeAPI) client() helix.Client {
+	return helix.NewClient(&helix.ClientOptions{
+		ClientID: "clientID",
+		Transport: testutils.Middleware(func(req *http.Request) *testutils.Response {
+			a.mu.Lock()
+			a.requests++
+			fail := a.fail
+			a.mu.Unlock()
+
+			if fail {
+				return testutils.EmptyResponse(http.StatusInternalServerError)
+			}
+
+			if req.URL.Query().Get("broadcaster_id") == "1" {
+				return testutils.JSONResponse(a.t, http.StatusOK, &helix.GetChannelChatBadgesResponse{
+					Data: []*helix.ChatBadge{
+						{SetID: "subscriber", Versions: []*helix.ChatBadgeVersion{{ID: "3012", Title: "Custom Subscriber", ImageURL1x: "channel.png"}}},
+					},
+				})
+			}
+
+			clickURL := "https://twitch.tv/turbo"
+			return testutils.JSONResponse(a.t, http.StatusOK, &helix.GetGlobalChatBadgesResponse{
+				Data: []*helix.ChatBadge{
+					{SetID: "subscriber", Versions: []*helix.ChatBadgeVersion{
+						{ID: "3012", Title: "Subscriber", ImageURL1x: "global-3012.png"},
+						{ID: "0", Title: "Subscriber", ImageURL1x: "global-0.png"},
+					}},
+					{SetID: "moderator", Versions: []*helix.ChatBadgeVersion{{ID: "1", Title: "Moderator", ImageURL1x: "mod.png"}}},
+					{SetID: "turbo", Versions: []*helix.ChatBadgeVersion{{ID: "1", Title: "Turbo", ClickURL: &clickURL}}},
+				},
+			})
+		}),
+	})
+}
+
+func (a *badgeAPI) count() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.requests
+}
+
+func TestBadgeResolver(t *testing.T) {
+	api := &badgeAPI{t: t}
+	r := NewBadgeResolver(&BadgeResolverOptions{Helix: api.client()})
+	assert.NoError(t, r.Load(context.Background(), "1"))
+	assert.Equal(t, 2, api.count())
+
+	badges := r.ResolveTags("1", "subscriber/3012,moderator/1,turbo/1,unknown/1", "subscriber/40")
+	assert.Equal(t, 3, len(badges))
+
+	// channel badges override global ones
+	assert.Equal(t, "Custom Subscriber", badges[0].Title)
+	assert.Equal(t, "channel.png", badges[0].ImageURL1x)
+	assert.Equal(t, "40", badges[0].Info)
+	assert.Equal(t, "mod.png", badges[1].ImageURL1x)
+	assert.Equal(t, "https://twitch.tv/turbo", badges[2].ClickURL)
+
+	// versions missing from the channel fall back to the global badges
+	badges = r.ResolveTags("1", "subscriber/0", "")
+	assert.Equal(t, "global-0.png", badges[0].ImageURL1x)
+
+	// other channels only use the global badges, the channel is loaded in the background
+	badges = r.ResolveTags("2", "subscriber/3012", "")
+	assert.Equal(t, "global-3012.png", badges[0].ImageURL1x)
+	r.wg.Wait()
+	assert.Equal(t, 3, api.count())
+
+	// lookups within the refresh interval do not call Helix
+	r.ResolveTags("1", "subscriber/3012", "")
+	r.wg.Wait()
+	assert.Equal(t, 3, api.count())
+}
+
+func TestBadgeResolverRefresh(t *testing.T) {
+	api := &badgeAPI{t: t}
+	now := time.Unix(0, 0)
+	r := NewBadgeResolver(&BadgeResolverOptions{Helix: api.client(), RefreshInterval: time.Minute})
+	r.now = func() time.Time { return now }
+
+	errs := make(chan error, 10)
+	r.OnError(func(err error) {
+		errs <- err
+	})
+
+	// lookups before warm-up return nothing, and load in the background
+	assert.Equal(t, 0, len(r.ResolveTags("1", "moderator/1", "")))
+	r.wg.Wait()
+	assert.Equal(t, 1, len(r.ResolveTags("1", "moderator/1", "")))
+	assert.Equal(t, 2, api.count())
+
+	// stale badges are still returned while they refresh, failed refreshes keep them
+	now = now.Add(2 * time.Minute)
+	api.mu.Lock()
+	api.fail = true
+	api.mu.Unlock()
+
+	assert.Equal(t, 1, len(r.ResolveTags("1", "moderator/1", "")))
+	r.wg.Wait()
+	assert.Equal(t, 4, api.count())
+	assert.Equal(t, true, <-errs != nil)
+	assert.Equal(t, 1, len(r.ResolveTags("1", "moderator/1", "")))
+	r.wg.Wait()
+	assert.Equal(t, 4, api.count())
+
+	// failed refreshes are retried after the retry interval, and recover once Helix does
+	now = now.Add(time.Minute)
+	api.mu.Lock()
+	api.fail = false
+	api.mu.Unlock()
+	r.ResolveTags("1", "moderator/1", "")
+	r.wg.Wait()
+	assert.Equal(t, 6, api.count())
+	r.ResolveTags("1", "moderator/1", "")
+	r.wg.Wait()
+	assert.Equal(t, 6, api.count())
+
+	assert.ErrorIs(t, NewBadgeResolver(nil).Load(context.Background()), ErrMissingHelix)
+}
+
+func TestBadgeResolverFailing(t *testing.T) {
+	api := &badgeAPI{t: t, fail: true}
+	now := time.Unix(0, 0)
+	r := NewBadgeResolver(&BadgeResolverOptions{Helix: api.client()})
+	r.now = func() time.Time { return now }
+
+	// while Helix is down, lookups do not call it for every message
+	for i := 0; i < 20; i++ {
+		assert.Equal(t, 0, len(r.ResolveTags("1", "moderator/1", "")))
+		r.wg.Wait()
+	}
+	assert.Equal(t, 2, api.count())
+
+	now = now.Add(badgeRetryInterval - time.Second)
+	r.ResolveTags("1", "moderator/1", "")
+	r.wg.Wait()
+	assert.Equal(t, 2, api.count())
+
+	now = now.Add(time.Second)
+	r.ResolveTags("1", "moderator/1", "")
+	r.wg.Wait()
+	assert.Equal(t, 4, api.count())
+}
+
+func TestBadgeResolverConcurrent(t *testing.T) {
+	api := &badgeAPI{t: t}
+	r := NewBadgeResolver(&BadgeResolverOptions{Helix: api.client()})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r.ResolveTags("1", "subscriber/3012", "")
+		}()
+	}
+	wg.Wait()
+	r.wg.Wait()
+
+	// concurrent lookups share a single refresh
+	assert.Equal(t, 2, api.count())
+}
@@ -0,0 +1,684 @@
+// Package chat implements a client for Twitch chat, also known as TMI, over TCP, TLS or WebSockets.
+//
+// See: https://dev.twitch.tv/docs/irc
+package chat
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aidenwallis/go-twitch-client/irc"
+)
+
+// DefaultAddress is the Twitch chat server address, over TLS
+const DefaultAddress = "tls://irc.chat.twitch.tv:6697"
+
+// Capabilities are the capabilities requested after connecting
+var Capabilities = []string{"twitch.tv/tags", "twitch.tv/commands", "twitch.tv/membership"}
+
+const (
+	// defaultBackoff is the initial delay before reconnecting after an unexpected disconnect, doubled after every
+	// failed attempt.
+	defaultBackoff = time.Second
+
+	// defaultMaxBackoff is the maximum delay between reconnect attempts.
+	defaultMaxBackoff = 2 * time.Minute
+
+	// defaultPingInterval is how long the connection may be idle before the client sends a PING.
+	defaultPingInterval = time.Minute
+
+	// defaultPongTimeout is how long the client waits for any message after sending a PING.
+	defaultPongTimeout = 10 * time.Second
+
+	// loginTimeout is how long the client waits for the server to accept the login.
+	loginTimeout = 10 * time.Second
+
+	// maxLineLength is the maximum length of a line sent to the server, excluding the CRLF.
+	maxLineLength = 510
+
+	// joinBatchSize is how many channels are joined per JOIN line, so the line fits maxLineLength.
+	joinBatchSize = 10
+)
+
+var (
+	// ErrLoginFailed is returned by Client.Connect when the server rejects the token. The client does not
+	// reconnect, as retrying with the same token fails again.
+	ErrLoginFailed = errors.New("chat: login authentication failed")
+
+	// ErrMissingLogin is returned by Client.Connect when a token is configured without a login.
+	ErrMissingLogin = errors.New("chat: login is required when authenticating with a token")
+
+	// ErrNotConnected is returned when sending a message while the client is not connected.
+	ErrNotConnected = errors.New("chat: not connected")
+
+	// ErrInvalidLine is returned when sending a message that contains a line break, which would allow injecting
+	// extra commands.
+	ErrInvalidLine = errors.New("chat: message contains a line break")
+
+	// ErrPingTimeout is reported when the server did not respond to a PING, meaning the connection is dead.
+	ErrPingTimeout = errors.New("chat: no response to ping")
+
+	// errReconnect is returned when the server asked the client to reconnect.
+	errReconnect = errors.New("chat: server requested reconnect")
+)
+
+// TokenLoader returns the OAuth user access token the client logs in with, it is called on every connection attempt
+// so tokens can be refreshed in between.
+type TokenLoader func(ctx context.Context) (userAccessToken string, err error)
+
+// ClientOptions defines all options the chat client supports.
+type ClientOptions struct {
+	// Address (optional) is the chat server address, as a tcp://, tls://, ws:// or wss:// URL. Defaults to
+	// DefaultAddress.
+	Address string
+
+	// TLSConfig (optional) is used for tls:// and wss:// addresses.
+	TLSConfig *tls.Config
+
+	// Login (optional) is the login name of the user the token belongs to. When both Login and the token are empty,
+	// the client connects anonymously and can only read chat.
+	Login string
+
+	// Token (optional) is the OAuth user access token to log in with, the same tokens the helix client uses. The
+	// oauth: prefix is optional. Requires the chat:read scope to read chat, and chat:edit to send messages.
+	Token string
+
+	// TokenLoader (optional) loads the token on every connection attempt, it takes precedence over Token.
+	TokenLoader TokenLoader
+
+	// Channels (optional) are joined once connected.
+	Channels []string
+
+	// Backoff (optional) is the delay before the first reconnect attempt after an unexpected disconnect, doubled
+	// after every failed attempt. Defaults to 1 second.
+	Backoff time.Duration
+
+	// MaxBackoff (optional) is the maximum delay between reconnect attempts, defaults to 2 minutes.
+	MaxBackoff time.Duration
+
+	// PingInterval (optional) is how long the connection may be idle before the client sends a PING to check it is
+	// still alive. Defaults to 1 minute.
+	PingInterval time.Duration
+
+	// RateLimiter (optional) enforces the chat limits of the account on messages sent through Say and on joins.
+	// Clients logged in as the same account should share one. Defaults to a new RateLimiter with DefaultRateLimits.
+	RateLimiter *RateLimiter
+
+	// MessagePreparer (optional) prepares the text sent through Say and Reply, which may then send several messages.
+	// Clients logged in as the same account should share one.
+	MessagePreparer *MessagePreparer
+}
+
+// Client is a Twitch chat client.
+//
+// Connect keeps the client connected until its context is cancelled. The client answers PINGs, follows RECONNECT
+// messages, reconnects with backoff after unexpected disconnects, and rejoins every channel after reconnecting.
+type Client struct {
+	address      string
+	tlsConfig    *tls.Config
+	login        string
+	token        string
+	tokenLoader  TokenLoader
+	backoff      time.Duration
+	maxBackoff   time.Duration
+	pingInterval time.Duration
+	pongTimeout  time.Duration
+	limiter      *RateLimiter
+	preparer     *MessagePreparer
+
+	mu        sync.Mutex
+	transport transport
+	channels  map[string]struct{}
+	nick      string
+
+	onMessage func(ctx context.Context, msg *irc.Message)
+	onConnect func(ctx context.Context)
+	onError   func(err error)
+
+	// onDisconnect is called after a connection failed unexpectedly, before waiting to reconnect
+	onDisconnect func(err error)
+}
+
+// NewClient creates a new instance of Client
+func NewClient(options *ClientOptions) *Client {
+	if options == nil {
+		options = &ClientOptions{}
+	}
+
+	c := &Client{
+		address:      DefaultAddress,
+		tlsConfig:    options.TLSConfig,
+		login:        strings.ToLower(options.Login),
+		token:        options.Token,
+		tokenLoader:  options.TokenLoader,
+		backoff:      defaultBackoff,
+		maxBackoff:   defaultMaxBackoff,
+		pingInterval: defaultPingInterval,
+		pongTimeout:  defaultPongTimeout,
+		channels:     make(map[string]struct{}, len(options.Channels)),
+		limiter:      options.RateLimiter,
+		preparer:     options.MessagePreparer,
+	}
+	if options.Address != "" {
+		c.address = options.Address
+	}
+	if options.Backoff > 0 {
+		c.backoff = options.Backoff
+	}
+	if options.MaxBackoff > 0 {
+		c.maxBackoff = options.MaxBackoff
+	}
+	if options.PingInterval > 0 {
+		c.pingInterval = options.PingInterval
+	}
+	if c.limiter == nil {
+		c.limiter = NewRateLimiter(nil)
+	}
+	for _, channel := range options.Channels {
+		if channel = normalizeChannel(channel); channel != "" {
+			c.channels[channel] = struct{}{}
+		}
+	}
+	return c
+}
+
+// OnMessage sets the handler every message received from the server is passed to, including the messages the
+// client handles itself, such as PING.
+//
+// Handlers must be registered before calling Connect.
+func (c *Client) OnMessage(handler func(ctx context.Context, msg *irc.Message)) {
+	c.onMessage = handler
+}
+
+// OnConnect sets the handler called every time the client logged in, before channels are joined. Messages can
+// already be sent from the handler.
+//
+// Handlers must be registered before calling Connect.
+func (c *Client) OnConnect(handler func(ctx context.Context)) {
+	c.onConnect = handler
+}
+
+// OnError sets the handler for errors that do not stop the client, such as failed connection attempts.
+//
+// Handlers must be registered before calling Connect.
+func (c *Client) OnError(handler func(err error)) {
+	c.onError = handler
+}
+
+// Nick returns the nickname of the current connection, or an empty string when not connected.
+func (c *Client) Nick() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.transport == nil {
+		return ""
+	}
+	return c.nick
+}
+
+// connected returns whether the client is logged in, so messages can be sent.
+func (c *Client) connected() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.transport != nil
+}
+
+// Channels returns the sorted channels the client is joined to, or joins once connected.
+func (c *Client) Channels() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.sortedChannels()
+}
+
+// Join joins the channels, they are rejoined whenever the client reconnects. Channels are only recorded when the
+// client is not connected. Join blocks while the join rate limit is exceeded.
+func (c *Client) Join(ctx context.Context, channels ...string) error {
+	c.mu.Lock()
+	var joins []string
+	for _, channel := range channels {
+		channel = normalizeChannel(channel)
+		if _, ok := c.channels[channel]; ok || channel == "" {
+			continue
+		}
+		c.channels[channel] = struct{}{}
+		joins = append(joins, channel)
+	}
+	t := c.transport
+	c.mu.Unlock()
+
+	if t == nil {
+		return nil
+	}
+	return c.join(ctx, t, joins)
+}
+
+// addChannels records the channels without joining them, they are joined with the other channels when the client
+// connects. Called from the OnConnect handler, they are joined on the current connection.
+func (c *Client) addChannels(channels []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, channel := range channels {
+		c.channels[channel] = struct{}{}
+	}
+}
+
+// Part leaves the channels.
+func (c *Client) Part(channels ...string) error {
+	c.mu.Lock()
+	var parts []string
+	for _, channel := range channels {
+		channel = normalizeChannel(channel)
+		if _, ok := c.channels[channel]; !ok {
+			continue
+		}
+		delete(c.channels, channel)
+		parts = append(parts, channel)
+	}
+	t := c.transport
+	c.mu.Unlock()
+
+	if t == nil {
+		return nil
+	}
+	return writeLines(t, channelCommands(irc.CommandPart, parts))
+}
+
+// Say sends a chat message to the channel, blocking while the rate limit of the account or the slow mode of the
+// channel is exceeded. With a MessagePreparer, every prepared message is sent in order.
+//
+// Say returns ErrNotConnected while the client is not connected, without counting the message against the rate
+// limits. Messages still count when the connection drops while waiting for the rate limit.
+func (c *Client) Say(ctx context.Context, channel, text string) error {
+	return c.say(ctx, channel, nil, text)
+}
+
+// Reply sends a chat message to the channel as a reply to the message with parentID, threading it in the chat UI. It
+// is rate limited as Say.
+func (c *Client) Reply(ctx context.Context, channel, parentID, text string) error {
+	return c.say(ctx, channel, irc.Tags{"reply-parent-msg-id": parentID}, text)
+}
+
+// say sends the text with the tags, preparing it when the client has a MessagePreparer.
+func (c *Client) say(ctx context.Context, channel string, tags irc.Tags, text string) error {
+	if !c.connected() {
+		return ErrNotConnected
+	}
+
+	messages := []string{text}
+	if c.preparer != nil {
+		var err error
+		if messages, err = c.preparer.Prepare(channel, text); err != nil {
+			return err
+		}
+	}
+
+	for _, message := range messages {
+		if !c.connected() {
+			return ErrNotConnected
+		}
+		if err := c.limiter.Wait(ctx, channel); err != nil {
+			return err
+		}
+		if err := c.Send(&irc.Message{
+			Tags:    tags,
+			Command: irc.CommandPrivmsg,
+			Params:  []string{"#" + normalizeChannel(channel), message},
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RateLimiter returns the rate limiter used by the client.
+func (c *Client) RateLimiter() *RateLimiter {
+	return c.limiter
+}
+
+// Send sends a raw message to the server, bypassing the rate limiter.
+func (c *Client) Send(msg *irc.Message) error {
+	return c.SendRaw(msg.String())
+}
+
+// SendRaw sends a raw line to the server without the trailing CRLF, bypassing the rate limiter.
+func (c *Client) SendRaw(line string) error {
+	if strings.ContainsAny(line, "\r\n") {
+		return ErrInvalidLine
+	}
+
+	c.mu.Lock()
+	t := c.transport
+	c.mu.Unlock()
+
+	if t == nil {
+		return ErrNotConnected
+	}
+	return t.writeLine(line)
+}
+
+// Connect connects to the chat server, and keeps the client connected until ctx is cancelled, which is returned.
+// Unexpected disconnects are passed to the OnError handler and reconnected with backoff.
+//
+// Connect returns ErrLoginFailed when the server rejects the token.
+func (c *Client) Connect(ctx context.Context) error {
+	backoff := c.backoff
+	for {
+		loggedIn, err := c.connect(ctx)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if errors.Is(err, ErrLoginFailed) || errors.Is(err, ErrMissingLogin) {
+			return err
+		}
+		if loggedIn {
+			backoff = c.backoff
+		}
+		if errors.Is(err, errReconnect) {
+			continue
+		}
+		c.reportError(err)
+		if c.onDisconnect != nil {
+			c.onDisconnect(err)
+		}
+
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+
+		backoff *= 2
+		if backoff > c.maxBackoff {
+			backoff = c.maxBackoff
+		}
+	}
+}
+
+// connect runs a single connection, returning whether it logged in and the error that ended it.
+func (c *Client) connect(ctx context.Context) (bool, error) {
+	nick, pass, err := c.credentials(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	t, err := dial(ctx, c.address, c.tlsConfig)
+	if err != nil {
+		return false, err
+	}
+	defer t.close()
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			_ = t.close()
+		case <-done:
+		}
+	}()
+
+	if err := c.authenticate(ctx, t, nick, pass); err != nil {
+		return false, err
+	}
+
+	c.mu.Lock()
+	c.transport = t
+	c.nick = nick
+	c.mu.Unlock()
+
+	defer func() {
+		c.mu.Lock()
+		c.transport = nil
+		c.mu.Unlock()
+	}()
+
+	if c.onConnect != nil {
+		c.onConnect(ctx)
+	}
+
+	// channels are joined in the background, as the join rate limit may delay joining for a while
+	joinCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		if err := c.join(joinCtx, t, c.Channels()); err != nil && joinCtx.Err() == nil {
+			c.reportError(err)
+		}
+	}()
+
+	return true, c.read(ctx, t)
+}
+
+// join joins the channels in batches, waiting for the join rate limit.
+func (c *Client) join(ctx context.Context, t transport, channels []string) error {
+	for len(channels) > 0 {
+		batch := channels
+		if len(batch) > joinBatchSize {
+			batch = batch[:joinBatchSize]
+		}
+		channels = channels[len(batch):]
+
+		if err := c.limiter.WaitJoins(ctx, len(batch)); err != nil {
+			return err
+		}
+		if err := writeLines(t, channelCommands(irc.CommandJoin, batch)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// credentials returns the nick and PASS token to log in with, nick is a random justinfan user when anonymous.
+func (c *Client) credentials(ctx context.Context) (nick, pass string, err error) {
+	token := c.token
+	if c.tokenLoader != nil {
+		if token, err = c.tokenLoader(ctx); err != nil {
+			return "", "", err
+		}
+	}
+
+	if token == "" && c.login == "" {
+		return anonymousNick(), "", nil
+	}
+	if c.login == "" {
+		return "", "", ErrMissingLogin
+	}
+	if token == "" {
+		return c.login, "", nil
+	}
+	return c.login, "oauth:" + strings.TrimPrefix(token, "oauth:"), nil
+}
+
+// authenticate requests the capabilities and logs in, waiting for the server welcome.
+func (c *Client) authenticate(ctx context.Context, t transport, nick, pass string) error {
+	lines := []string{"CAP REQ :" + strings.Join(Capabilities, " ")}
+	if pass != "" {
+		lines = append(lines, "PASS "+pass)
+	}
+	lines = append(lines, "NICK "+nick)
+	if err := writeLines(t, lines); err != nil {
+		return err
+	}
+
+	_ = t.setReadDeadline(time.Now().Add(loginTimeout))
+	for {
+		msg, err := c.readMessage(t)
+		if err != nil {
+			return err
+		}
+		if msg == nil {
+			continue
+		}
+
+		switch msg.Command {
+		case "001":
+			c.handle(ctx, msg)
+			return nil
+
+		case irc.CommandNotice:
+			// Twitch sends login failures as a NOTICE to *, and closes the connection
+			if text := msg.Trailing(); strings.Contains(text, "authentication failed") || strings.Contains(text, "Improperly formatted auth") {
+				return fmt.Errorf("%w: %s", ErrLoginFailed, text)
+			}
+
+		case irc.CommandPing:
+			if err := t.writeLine(pong(msg)); err != nil {
+				return err
+			}
+
+		case irc.CommandReconnect:
+			return errReconnect
+		}
+
+		c.handle(ctx, msg)
+	}
+}
+
+// read reads messages until the connection fails, answering PINGs and detecting dead connections.
+func (c *Client) read(ctx context.Context, t transport) error {
+	pinged := false
+	for {
+		timeout := c.pingInterval
+		if pinged {
+			timeout = c.pongTimeout
+		}
+		_ = t.setReadDeadline(time.Now().Add(timeout))
+
+		msg, err := c.readMessage(t)
+		if err != nil {
+			var netErr net.Error
+			if !errors.As(err, &netErr) || !netErr.Timeout() {
+				return err
+			}
+			if pinged {
+				return ErrPingTimeout
+			}
+			pinged = true
+			if err := t.writeLine("PING :tmi.twitch.tv"); err != nil {
+				return err
+			}
+			continue
+		}
+
+		pinged = false
+		if msg == nil {
+			continue
+		}
+
+		switch msg.Command {
+		case irc.CommandPing:
+			if err := t.writeLine(pong(msg)); err != nil {
+				return err
+			}
+
+		case irc.CommandReconnect:
+			c.handle(ctx, msg)
+			return errReconnect
+		}
+
+		c.handle(ctx, msg)
+	}
+}
+
+// readMessage reads and parses the next line, malformed lines are reported and returned as nil.
+func (c *Client) readMessage(t transport) (*irc.Message, error) {
+	line, err := t.readLine()
+	if err != nil {
+		return nil, err
+	}
+	if line == "" {
+		return nil, nil
+	}
+
+	msg, err := irc.Parse(line)
+	if err != nil {
+		c.reportError(fmt.Errorf("chat: parsing %q: %w", line, err))
+		return nil, nil
+	}
+	return msg, nil
+}
+
+func (c *Client) handle(ctx context.Context, msg *irc.Message) {
+	c.limiter.Observe(msg)
+	if c.onMessage != nil {
+		c.onMessage(ctx, msg)
+	}
+}
+
+// reportError passes non-nil errors to the OnError handler.
+func (c *Client) reportError(err error) {
+	if err != nil && c.onError != nil {
+		c.onError(err)
+	}
+}
+
+// sortedChannels returns the channels in sorted order, c.mu must be held.
+func (c *Client) sortedChannels() []string {
+	channels := make([]string, 0, len(c.channels))
+	for channel := range c.channels {
+		channels = append(channels, channel)
+	}
+	sort.Strings(channels)
+	return channels
+}
+
+// channelCommands batches the channels into as few JOIN or PART lines as fit the line length limit.
+func channelCommands(command string, channels []string) []string {
+	var lines []string
+	var b strings.Builder
+	for _, channel := range channels {
+		if b.Len() > 0 && b.Len()+len(channel)+2 > maxLineLength {
+			lines = append(lines, b.String())
+			b.Reset()
+		}
+		if b.Len() == 0 {
+			b.WriteString(command + " ")
+		} else {
+			b.WriteByte(',')
+		}
+		b.WriteString("#" + channel)
+	}
+	if b.Len() > 0 {
+		lines = append(lines, b.String())
+	}
+	return lines
+}
+
+func writeLines(t transport, lines []string) error {
+	for _, line := range lines {
+		if err := t.writeLine(line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// pong returns the PONG reply to a PING message.
+func pong(ping *irc.Message) string {
+	return irc.CommandPong + " :" + ping.Trailing()
+}
+
+// normalizeChannel lowercases the channel, and strips the leading #.
+func normalizeChannel(channel string) string {
+	return strings.ToLower(strings.TrimPrefix(strings.TrimSpace(channel), "#"))
+}
+
+// anonymousNick returns a random justinfan nickname, which Twitch accepts without a token.
+func anonymousNick() string {
+	n, err := rand.Int(rand.Reader, big.NewInt(90000))
+	if err != nil {
+		return "justinfan12345"
+	}
+	return fmt.Sprintf("justinfan%d", n.Int64()+10000)
+}
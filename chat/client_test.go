@@ -0,0 +1,311 @@
+package chat
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aidenwallis/go-twitch-client/internal/testutils/assert"
+	"github.com/aidenwallis/go-twitch-client/internal/websocket"
+	"github.com/aidenwallis/go-twitch-client/irc"
+)
+
+// fakeServer is a local stand-in for the Twitch chat server, speaking plain IRC over TCP
+type fakeServer struct {
+	listener net.Listener
+	conns    chan *fakeConn
+}
+
+func newFakeServer(t *testing.T) *fakeServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+
+	s := &fakeServer{listener: listener, conns: make(chan *fakeConn, 8)}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			s.conns <- &fakeConn{conn: conn, reader: bufio.NewReader(conn)}
+		}
+	}()
+	t.Cleanup(func() { _ = listener.Close() })
+	return s
+}
+
+func (s *fakeServer) address() string {
+	return "tcp://" + s.listener.Addr().String()
+}
+
+func (s *fakeServer) accept(t *testing.T) *fakeConn {
+	t.Helper()
+	select {
+	case conn := <-s.conns:
+		t.Cleanup(func() { _ = conn.conn.Close() })
+		return conn
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for connection")
+		return nil
+	}
+}
+
+type fakeConn struct {
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+func (c *fakeConn) expect(t *testing.T, expected string) {
+	t.Helper()
+	_ = c.conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	line, err := c.reader.ReadString('\n')
+	assert.NoError(t, err)
+	assert.Equal(t, expected, strings.TrimRight(line, "\r\n"))
+}
+
+func (c *fakeConn) send(t *testing.T, line string) {
+	t.Helper()
+	_, err := c.conn.Write([]byte(line + "\r\n"))
+	assert.NoError(t, err)
+}
+
+// login expects the login sequence for nick, and welcomes the client
+func (c *fakeConn) login(t *testing.T, pass, nick string) {
+	t.Helper()
+	c.expect(t, "CAP REQ :twitch.tv/tags twitch.tv/commands twitch.tv/membership")
+	if pass != "" {
+		c.expect(t, "PASS "+pass)
+	}
+	c.expect(t, "NICK "+nick)
+	c.send(t, ":tmi.twitch.tv 001 "+nick+" :Welcome, GLHF!")
+}
+
+func TestClient(t *testing.T) {
+	srv := newFakeServer(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	messages := make(chan *irc.Message, 10)
+	c := NewClient(&ClientOptions{
+		Address:  srv.address(),
+		Login:    "Bot",
+		Token:    "abc",
+		Channels: []string{"#Forsen", "nymn"},
+	})
+	c.OnMessage(func(ctx context.Context, msg *irc.Message) {
+		if msg.Command == irc.CommandPrivmsg {
+			messages <- msg
+		}
+	})
+
+	done := make(chan error, 1)
+	go func() {
+		done <- c.Connect(ctx)
+	}()
+
+	conn := srv.accept(t)
+	conn.login(t, "oauth:abc", "bot")
+	conn.expect(t, "JOIN #forsen,#nymn")
+
+	conn.send(t, "PING :tmi.twitch.tv")
+	conn.expect(t, "PONG :tmi.twitch.tv")
+
+	conn.send(t, "@id=1 :forsen!forsen@forsen.tmi.twitch.tv PRIVMSG #forsen :hello")
+	assert.Equal(t, "hello", (<-messages).Trailing())
+	assert.Equal(t, "bot", c.Nick())
+
+	assert.NoError(t, c.Say(ctx, "#Forsen", "hi chat"))
+	conn.expect(t, "PRIVMSG #forsen :hi chat")
+	assert.NoError(t, c.Reply(ctx, "forsen", "abc-123", "hi forsen"))
+	conn.expect(t, "@reply-parent-msg-id=abc-123 PRIVMSG #forsen :hi forsen")
+	assert.ErrorIs(t, c.SendRaw("PRIVMSG #forsen :a\r\nPART #forsen"), ErrInvalidLine)
+
+	assert.NoError(t, c.Join(ctx, "pajlada", "forsen"))
+	conn.expect(t, "JOIN #pajlada")
+	assert.NoError(t, c.Part("nymn"))
+	conn.expect(t, "PART #nymn")
+
+	cancel()
+	assert.ErrorIs(t, <-done, context.Canceled)
+	assert.Equal(t, "", c.Nick())
+	assert.ErrorIs(t, c.Say(context.Background(), "forsen", "hi"), ErrNotConnected)
+	assert.Equal(t, "forsen,pajlada", strings.Join(c.Channels(), ","))
+}
+
+func TestClientSayNotConnected(t *testing.T) {
+	limiter := NewRateLimiter(nil)
+	limiter.SetSlowMode("forsen", time.Hour)
+	preparer := NewMessagePreparer(&MessagePreparerOptions{VaryDuplicates: true})
+	c := NewClient(&ClientOptions{Login: "bot", RateLimiter: limiter, MessagePreparer: preparer})
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	// messages that are not sent do not count against the rate limits, and are not recorded as sent
+	assert.ErrorIs(t, c.Say(ctx, "forsen", "hi"), ErrNotConnected)
+	assert.ErrorIs(t, c.Reply(ctx, "forsen", "m1", "hi"), ErrNotConnected)
+	assert.Equal(t, time.Duration(0), limiter.reserve(limiter.now(), "forsen"))
+
+	messages, err := preparer.Prepare("forsen", "hi")
+	assert.NoError(t, err)
+	assert.Equal(t, "hi", messages[0])
+}
+
+func TestClientReconnect(t *testing.T) {
+	srv := newFakeServer(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	errs := make(chan error, 10)
+	c := NewClient(&ClientOptions{Address: srv.address(), Channels: []string{"forsen"}, Backoff: time.Millisecond})
+	c.OnError(func(err error) {
+		errs <- err
+	})
+
+	done := make(chan error, 1)
+	go func() {
+		done <- c.Connect(ctx)
+	}()
+
+	// anonymous login
+	conn := srv.accept(t)
+	conn.expect(t, "CAP REQ :twitch.tv/tags twitch.tv/commands twitch.tv/membership")
+	_ = conn.conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	line, err := conn.reader.ReadString('\n')
+	assert.NoError(t, err)
+	nick := strings.TrimPrefix(strings.TrimSpace(line), "NICK ")
+	assert.Equal(t, true, strings.HasPrefix(nick, "justinfan"))
+	conn.send(t, ":tmi.twitch.tv 001 "+nick+" :Welcome, GLHF!")
+	conn.expect(t, "JOIN #forsen")
+
+	// RECONNECT reconnects immediately, without reporting an error
+	conn.send(t, ":tmi.twitch.tv RECONNECT")
+	conn = srv.accept(t)
+	conn.expect(t, "CAP REQ :twitch.tv/tags twitch.tv/commands twitch.tv/membership")
+	_, err = conn.reader.ReadString('\n')
+	assert.NoError(t, err)
+	conn.send(t, ":tmi.twitch.tv 001 "+nick+" :Welcome, GLHF!")
+	conn.expect(t, "JOIN #forsen")
+	assert.Equal(t, 0, len(errs))
+
+	// unexpected disconnects are reported, and channels are rejoined
+	assert.NoError(t, c.Join(ctx, "nymn"))
+	conn.expect(t, "JOIN #nymn")
+	_ = conn.conn.Close()
+	assert.Equal(t, true, <-errs != nil)
+
+	conn = srv.accept(t)
+	conn.expect(t, "CAP REQ :twitch.tv/tags twitch.tv/commands twitch.tv/membership")
+	_, err = conn.reader.ReadString('\n')
+	assert.NoError(t, err)
+	conn.send(t, ":tmi.twitch.tv 001 "+nick+" :Welcome, GLHF!")
+	conn.expect(t, "JOIN #forsen,#nymn")
+
+	cancel()
+	assert.ErrorIs(t, <-done, context.Canceled)
+}
+
+func TestClientLoginFailed(t *testing.T) {
+	srv := newFakeServer(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	c := NewClient(&ClientOptions{
+		Address: srv.address(),
+		Login:   "bot",
+		TokenLoader: func(ctx context.Context) (string, error) {
+			return "oauth:expired", nil
+		},
+	})
+
+	done := make(chan error, 1)
+	go func() {
+		done <- c.Connect(ctx)
+	}()
+
+	conn := srv.accept(t)
+	conn.expect(t, "CAP REQ :twitch.tv/tags twitch.tv/commands twitch.tv/membership")
+	conn.expect(t, "PASS oauth:expired")
+	conn.expect(t, "NICK bot")
+	conn.send(t, ":tmi.twitch.tv NOTICE * :Login authentication failed")
+	assert.ErrorIs(t, <-done, ErrLoginFailed)
+
+	assert.ErrorIs(t, NewClient(&ClientOptions{Token: "abc"}).Connect(ctx), ErrMissingLogin)
+}
+
+func TestClientPingTimeout(t *testing.T) {
+	srv := newFakeServer(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	errs := make(chan error, 10)
+	c := NewClient(&ClientOptions{Address: srv.address(), Login: "bot", PingInterval: 50 * time.Millisecond, Backoff: time.Hour})
+	c.pongTimeout = 50 * time.Millisecond
+	c.OnError(func(err error) {
+		errs <- err
+	})
+
+	go func() {
+		_ = c.Connect(ctx)
+	}()
+
+	conn := srv.accept(t)
+	conn.login(t, "", "bot")
+	conn.expect(t, "PING :tmi.twitch.tv")
+	assert.ErrorIs(t, <-errs, ErrPingTimeout)
+}
+
+func TestClientWebSocket(t *testing.T) {
+	lines := make(chan string, 10)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := websocket.Upgrade(w, r)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		for {
+			_, data, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			lines <- string(data)
+			if strings.HasPrefix(string(data), "NICK ") {
+				// a single message may contain multiple lines
+				_ = conn.WriteMessage(websocket.TextMessage, []byte(":tmi.twitch.tv 001 bot :Welcome, GLHF!\r\nPING :tmi.twitch.tv\r\n"))
+			}
+		}
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	c := NewClient(&ClientOptions{Address: "ws" + strings.TrimPrefix(srv.URL, "http"), Login: "bot", Token: "abc"})
+	go func() {
+		_ = c.Connect(ctx)
+	}()
+
+	assert.Equal(t, "CAP REQ :twitch.tv/tags twitch.tv/commands twitch.tv/membership", <-lines)
+	assert.Equal(t, "PASS oauth:abc", <-lines)
+	assert.Equal(t, "NICK bot", <-lines)
+	assert.Equal(t, "PONG :tmi.twitch.tv", <-lines)
+}
+
+func TestChannelCommands(t *testing.T) {
+	channels := make([]string, 100)
+	for i := range channels {
+		channels[i] = strings.Repeat("a", 20)
+	}
+
+	lines := channelCommands(irc.CommandJoin, channels)
+	assert.Equal(t, 5, len(lines))
+	for _, line := range lines {
+		assert.Equal(t, true, len(line) <= maxLineLength)
+	}
+	assert.Equal(t, 0, len(channelCommands(irc.CommandJoin, nil)))
+}
@@ -0,0 +1,478 @@
+package chat
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/aidenwallis/go-twitch-client/helix"
+	"github.com/aidenwallis/go-twitch-client/irc"
+)
+
+const (
+	// defaultEmoteRefreshInterval is how often Run refreshes the loaded emotes.
+	defaultEmoteRefreshInterval = time.Hour
+
+	// maxEmoteSetIDs is how many emote sets GetEmoteSets accepts per request.
+	maxEmoteSetIDs = 25
+)
+
+// EmoteProviderTwitch is the provider of Twitch emotes
+const EmoteProviderTwitch = "twitch"
+
+// Emote is an emote from any source, unified into a single representation
+type Emote struct {
+	// ID is the emote ID, unique per provider.
+	ID string
+
+	// Code is the text that is replaced by the emote, such as Kappa.
+	Code string
+
+	// Provider is the service the emote comes from, such as EmoteProviderTwitch.
+	Provider string
+
+	// Type is the kind of emote, such as globals, subscriptions, bitstier or follower for Twitch emotes.
+	Type string
+
+	// Tier is the subscriber tier needed to use subscriber emotes, such as 1000.
+	Tier string
+
+	// SetID is the emote set the emote belongs to.
+	SetID string
+
+	// OwnerID is the ID of the broadcaster who owns the emote, empty for global emotes.
+	OwnerID string
+
+	// Formats are the available formats, such as static and animated.
+	Formats []string
+
+	// Scales are the available scales, such as 1.0.
+	Scales []string
+
+	// ThemeModes are the available themes, such as light and dark.
+	ThemeModes []string
+
+	// Template is the CDN template to build image URLs with, see helix.FormatChatEmoteTemplate.
+	Template string
+
+	// Images are the image URLs by scale, such as 1.0, for third-party emotes which are not built from a template.
+	Images map[string]string
+}
+
+// URL returns the image URL of the emote, see helix.FormatChatEmoteTemplate. Third-party emotes ignore the format and
+// theme, and fall back to their smallest image when the scale is not available.
+func (e *Emote) URL(format, themeMode, scale string) string {
+	if e.Provider != EmoteProviderTwitch && e.Provider != "" {
+		if u, ok := e.Images[scale]; ok {
+			return u
+		}
+		return e.Images[EmoteScale1]
+	}
+
+	template := e.Template
+	if template == "" {
+		template = helix.DefaultChatEmoteTemplate
+	}
+	return helix.FormatChatEmoteTemplate(template, e.ID, format, themeMode, scale)
+}
+
+// key is the ID of the emote in the ID index, unique across providers.
+func (e *Emote) key() string {
+	if e.Provider == EmoteProviderTwitch || e.Provider == "" {
+		return e.ID
+	}
+	return e.Provider + ":" + e.ID
+}
+
+// EmoteCatalogOptions defines all options the emote catalog supports.
+type EmoteCatalogOptions struct {
+	// Helix (optional) is used to load the Twitch emotes, it is required unless Providers are set.
+	Helix helix.Client
+
+	// Providers (optional) are the third-party emote providers, such as BetterTTV, merged into the global and channel
+	// emotes. Earlier providers take precedence over later ones.
+	Providers []EmoteProvider
+
+	// RefreshInterval (optional) is how often Run refreshes the loaded emotes, defaults to 1 hour.
+	RefreshInterval time.Duration
+}
+
+// EmoteCatalog indexes the global, channel and emote set emotes by code and ID.
+//
+// When resolving a code, the emotes of the channel take precedence over emote set emotes, which take precedence over
+// global emotes. Within the global and channel scopes, Twitch emotes take precedence over third-party emotes, which
+// take precedence in the order of the providers. Between emote sets, the set with the lowest ID takes precedence.
+type EmoteCatalog struct {
+	helix           helix.Client
+	providers       []EmoteProvider
+	refreshInterval time.Duration
+
+	mu       sync.RWMutex
+	global   map[string]*Emote
+	channels map[string]map[string]*Emote
+	sets     map[string]map[string]*Emote
+	byCode   map[string][]*Emote // emote set emotes by code, in order of precedence
+	byID     map[string][]*Emote // emotes of every scope by key, in the order they were loaded
+
+	onError func(err error)
+}
+
+// NewEmoteCatalog creates a new instance of EmoteCatalog
+func NewEmoteCatalog(options *EmoteCatalogOptions) *EmoteCatalog {
+	if options == nil {
+		options = &EmoteCatalogOptions{}
+	}
+
+	c := &EmoteCatalog{
+		helix:           options.Helix,
+		providers:       options.Providers,
+		refreshInterval: defaultEmoteRefreshInterval,
+		channels:        map[string]map[string]*Emote{},
+		sets:            map[string]map[string]*Emote{},
+		byCode:          map[string][]*Emote{},
+		byID:            map[string][]*Emote{},
+	}
+	if options.RefreshInterval > 0 {
+		c.refreshInterval = options.RefreshInterval
+	}
+	return c
+}
+
+// OnError sets the handler for errors of the refreshes done by Run.
+//
+// Handlers must be registered before calling Run.
+func (c *EmoteCatalog) OnError(handler func(err error)) {
+	c.onError = handler
+}
+
+// LoadGlobal loads the global emotes, of Twitch and of every provider.
+//
+// When a provider fails, the emotes of the other providers are still loaded, its previously loaded emotes are kept,
+// and its error is returned.
+func (c *EmoteCatalog) LoadGlobal(ctx context.Context) error {
+	if c.helix == nil && len(c.providers) == 0 {
+		return ErrMissingHelix
+	}
+
+	emotes := map[string]*Emote{}
+	if c.helix != nil {
+		resp, err := c.helix.GetGlobalEmotes(ctx, &helix.GetGlobalEmotesRequest{})
+		if err != nil {
+			return err
+		}
+
+		for _, e := range resp.Data {
+			emotes[e.Name] = &Emote{
+				ID:         e.ID,
+				Code:       e.Name,
+				Provider:   EmoteProviderTwitch,
+				Type:       "globals",
+				Formats:    e.Format,
+				Scales:     e.Scale,
+				ThemeModes: e.ThemeMode,
+				Template:   resp.Template,
+			}
+		}
+	}
+
+	c.mu.RLock()
+	previous := c.global
+	c.mu.RUnlock()
+
+	err := c.loadProviders(emotes, previous, func(p EmoteProvider) ([]*Emote, error) {
+		return p.GlobalEmotes(ctx)
+	})
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.reindex(c.global, emotes, false)
+	c.global = emotes
+	return err
+}
+
+// LoadChannel loads the emotes of the broadcaster's channel, of Twitch and of every provider. Provider errors are
+// handled as in LoadGlobal.
+func (c *EmoteCatalog) LoadChannel(ctx context.Context, broadcasterID string) error {
+	if c.helix == nil && len(c.providers) == 0 {
+		return ErrMissingHelix
+	}
+
+	emotes := map[string]*Emote{}
+	if c.helix != nil {
+		resp, err := c.helix.GetChannelEmotes(ctx, &helix.GetChannelEmotesRequest{BroadcasterID: broadcasterID})
+		if err != nil {
+			return err
+		}
+
+		for _, e := range resp.Data {
+			emotes[e.Name] = &Emote{
+				ID:         e.ID,
+				Code:       e.Name,
+				Provider:   EmoteProviderTwitch,
+				Type:       e.EmoteType,
+				Tier:       e.Tier,
+				SetID:      e.EmoteSetID,
+				OwnerID:    broadcasterID,
+				Formats:    e.Format,
+				Scales:     e.Scale,
+				ThemeModes: e.ThemeMode,
+				Template:   resp.Template,
+			}
+		}
+	}
+
+	c.mu.RLock()
+	previous := c.channels[broadcasterID]
+	c.mu.RUnlock()
+
+	err := c.loadProviders(emotes, previous, func(p EmoteProvider) ([]*Emote, error) {
+		return p.ChannelEmotes(ctx, broadcasterID)
+	})
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.reindex(c.channels[broadcasterID], emotes, false)
+	c.channels[broadcasterID] = emotes
+	return err
+}
+
+// loadProviders adds the emotes of every provider to the scope, in order of precedence, keeping the previous emotes
+// of failed providers. The first provider error is returned.
+func (c *EmoteCatalog) loadProviders(emotes, previous map[string]*Emote, load func(p EmoteProvider) ([]*Emote, error)) error {
+	var firstErr error
+	for _, p := range c.providers {
+		loaded, err := load(p)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("chat: failed to load %s emotes: %w", p.Name(), err)
+			}
+			loaded = loaded[:0]
+			for _, e := range previous {
+				if e.Provider == p.Name() {
+					loaded = append(loaded, e)
+				}
+			}
+		}
+
+		for _, e := range loaded {
+			if _, ok := emotes[e.Code]; !ok {
+				emotes[e.Code] = e
+			}
+		}
+	}
+	return firstErr
+}
+
+// LoadEmoteSets loads the emotes of the emote sets, such as the sets from the emote-sets tag of USERSTATE messages.
+func (c *EmoteCatalog) LoadEmoteSets(ctx context.Context, setIDs ...string) error {
+	if c.helix == nil {
+		return ErrMissingHelix
+	}
+
+	sets := make(map[string]map[string]*Emote, len(setIDs))
+	for _, id := range setIDs {
+		sets[id] = map[string]*Emote{}
+	}
+
+	for len(setIDs) > 0 {
+		batch := setIDs
+		if len(batch) > maxEmoteSetIDs {
+			batch = batch[:maxEmoteSetIDs]
+		}
+		setIDs = setIDs[len(batch):]
+
+		resp, err := c.helix.GetEmoteSets(ctx, &helix.GetEmoteSetsRequest{EmoteSetIDs: batch})
+		if err != nil {
+			return err
+		}
+
+		for _, e := range resp.Data {
+			set := sets[e.EmoteSetID]
+			if set == nil {
+				set = map[string]*Emote{}
+				sets[e.EmoteSetID] = set
+			}
+			set[e.Name] = &Emote{
+				ID:         e.ID,
+				Code:       e.Name,
+				Provider:   EmoteProviderTwitch,
+				Type:       e.EmoteType,
+				SetID:      e.EmoteSetID,
+				OwnerID:    e.OwnerID,
+				Formats:    e.Format,
+				Scales:     e.Scale,
+				ThemeModes: e.ThemeMode,
+				Template:   resp.Template,
+			}
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for id, set := range sets {
+		c.reindex(c.sets[id], set, true)
+		c.sets[id] = set
+	}
+	return nil
+}
+
+// Refresh reloads every scope that was loaded before.
+func (c *EmoteCatalog) Refresh(ctx context.Context) error {
+	c.mu.RLock()
+	global := c.global != nil
+	channels := make([]string, 0, len(c.channels))
+	for id := range c.channels {
+		channels = append(channels, id)
+	}
+	sets := make([]string, 0, len(c.sets))
+	for id := range c.sets {
+		sets = append(sets, id)
+	}
+	c.mu.RUnlock()
+
+	if global {
+		if err := c.LoadGlobal(ctx); err != nil {
+			return err
+		}
+	}
+	for _, id := range channels {
+		if err := c.LoadChannel(ctx, id); err != nil {
+			return err
+		}
+	}
+	if len(sets) > 0 {
+		return c.LoadEmoteSets(ctx, sets...)
+	}
+	return nil
+}
+
+// Run refreshes the loaded emotes every refresh interval until ctx is cancelled, which is returned. Refresh errors are
+// passed to the OnError handler.
+func (c *EmoteCatalog) Run(ctx context.Context) error {
+	ticker := time.NewTicker(c.refreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			if err := c.Refresh(ctx); err != nil && c.onError != nil && ctx.Err() == nil {
+				c.onError(err)
+			}
+		}
+	}
+}
+
+// Lookup resolves an emote code used in the broadcaster's channel, broadcasterID may be empty to only look up emote set
+// and global emotes.
+func (c *EmoteCatalog) Lookup(broadcasterID, code string) (*Emote, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.lookup(broadcasterID, code)
+}
+
+// ByID returns the emote with the ID. Third-party emote IDs are prefixed with their provider, such as bttv:abc, as
+// returned by Tokenize, since their IDs may collide with Twitch emote IDs.
+func (c *EmoteCatalog) ByID(id string) (*Emote, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if emotes := c.byID[id]; len(emotes) > 0 {
+		return emotes[0], true
+	}
+	return nil, false
+}
+
+// Tokenize finds the emote codes in text, for messages from sources without an emotes tag or with third-party emotes.
+// The matches are returned as code point positions, in the same form as the emotes tag, so they can be passed to
+// Fragments. Third-party emote IDs are prefixed with their provider, see ByID.
+func (c *EmoteCatalog) Tokenize(broadcasterID, text string) []irc.Emote {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var emotes []irc.Emote
+	index, wordStart, wordIndex := 0, 0, 0
+	for i, r := range text + " " {
+		if r != ' ' {
+			index++
+			continue
+		}
+
+		if i > wordStart {
+			if e, ok := c.lookup(broadcasterID, text[wordStart:i]); ok {
+				emotes = append(emotes, irc.Emote{ID: e.key(), Start: wordIndex, End: index - 1})
+			}
+		}
+		index++
+		wordStart, wordIndex = i+1, index
+	}
+	return emotes
+}
+
+// lookup resolves an emote code, c.mu must be held.
+func (c *EmoteCatalog) lookup(broadcasterID, code string) (*Emote, bool) {
+	if e, ok := c.channels[broadcasterID][code]; ok {
+		return e, true
+	}
+	if emotes := c.byCode[code]; len(emotes) > 0 {
+		return emotes[0], true
+	}
+	e, ok := c.global[code]
+	return e, ok
+}
+
+// reindex replaces the previous emotes of a scope with its loaded emotes in the indexes, emote set emotes are indexed
+// by code too. c.mu must be held.
+func (c *EmoteCatalog) reindex(previous, emotes map[string]*Emote, set bool) {
+	for code, e := range previous {
+		c.byID[e.key()] = removeEmote(c.byID[e.key()], e)
+		if len(c.byID[e.key()]) == 0 {
+			delete(c.byID, e.key())
+		}
+		if set {
+			c.byCode[code] = removeEmote(c.byCode[code], e)
+			if len(c.byCode[code]) == 0 {
+				delete(c.byCode, code)
+			}
+		}
+	}
+
+	for code, e := range emotes {
+		c.byID[e.key()] = append(c.byID[e.key()], e)
+		if set {
+			byCode := c.byCode[code]
+			i := sort.Search(len(byCode), func(i int) bool {
+				return lessSetID(e.SetID, byCode[i].SetID)
+			})
+			byCode = append(byCode, nil)
+			copy(byCode[i+1:], byCode[i:])
+			byCode[i] = e
+			c.byCode[code] = byCode
+		}
+	}
+}
+
+// removeEmote removes the emote from the emotes, keeping their order
+func removeEmote(emotes []*Emote, e *Emote) []*Emote {
+	for i, v := range emotes {
+		if v == e {
+			return append(emotes[:i], emotes[i+1:]...)
+		}
+	}
+	return emotes
+}
+
+// lessSetID returns whether emote set a takes precedence over b, comparing numeric IDs by value
+func lessSetID(a, b string) bool {
+	if len(a) != len(b) {
+		return len(a) < len(b)
+	}
+	return a < b
+}
@@ -0,0 +1,166 @@
+package chat
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/aidenwallis/go-twitch-client/helix"
+	"github.com/aidenwallis/go-twitch-client/internal/testutils"
+	"github.com/aidenwallis/go-twitch-client/internal/testutils/assert"
+)
+
+const fakeEmoteTemplate = "https://cdn/{{id}}/{{format}}/{{theme_mode}}/{{scale}}"
+
+func emoteHelix(t *testing.T, setRequests *[]string) helix.Client {
+	return helix.NewClient(&helix.ClientOptions{
+		ClientID: "clientID",
+		Transport: testutils.Middleware(func(req *http.Request) *testutils.Response {
+			switch {
+			case strings.HasSuffix(req.URL.Path, "/emotes/global"):
+				return testutils.JSONResponse(t, http.StatusOK, &helix.GetGlobalEmotesResponse{
+					Template: fakeEmoteTemplate,
+					Data: []*helix.GlobalEmote{
+						{ID: "25", Name: "Kappa", Format: []string{"static"}},
+						{ID: "1", Name: "LUL"},
+					},
+				})
+
+			case strings.HasSuffix(req.URL.Path, "/emotes/set"):
+				ids := req.URL.Query()["emote_set_id"]
+				*setRequests = append(*setRequests, strings.Join(ids, ","))
+				data := []*helix.SetEmote{}
+				for _, id := range ids {
+					switch id {
+					case "300":
+						data = append(data, &helix.SetEmote{ID: "300-1", Name: "LUL", EmoteSetID: "300", OwnerID: "9", EmoteType: "subscriptions"})
+					case "1000", "20":
+						data = append(data, &helix.SetEmote{ID: id + "-1", Name: "LUL", EmoteSetID: id, EmoteType: "follower"})
+					}
+				}
+				return testutils.JSONResponse(t, http.StatusOK, &helix.GetEmoteSetsResponse{Template: fakeEmoteTemplate, Data: data})
+
+			default:
+				assert.Equal(t, "1", req.URL.Query().Get("broadcaster_id"))
+				return testutils.JSONResponse(t, http.StatusOK, &helix.GetChannelEmotesResponse{
+					Template: fakeEmoteTemplate,
+					Data: []*helix.ChannelEmote{
+						{ID: "c1", Name: "forsenE", Tier: "1000", EmoteType: "subscriptions", EmoteSetID: "100"},
+						{ID: "c2", Name: "Kappa", EmoteType: "follower"},
+					},
+				})
+			}
+		}),
+	})
+}
+
+func TestEmoteCatalog(t *testing.T) {
+	ctx := context.Background()
+	var setRequests []string
+	c := NewEmoteCatalog(&EmoteCatalogOptions{Helix: emoteHelix(t, &setRequests)})
+
+	assert.NoError(t, c.LoadGlobal(ctx))
+	assert.NoError(t, c.LoadChannel(ctx, "1"))
+	assert.NoError(t, c.LoadEmoteSets(ctx, "300"))
+
+	e, ok := c.Lookup("1", "forsenE")
+	assert.Equal(t, true, ok)
+	assert.Equal(t, "c1", e.ID)
+	assert.Equal(t, "1000", e.Tier)
+	assert.Equal(t, "1", e.OwnerID)
+	assert.Equal(t, EmoteProviderTwitch, e.Provider)
+	assert.Equal(t, "https://cdn/c1/static/dark/1.0", e.URL(EmoteFormatStatic, EmoteThemeDark, EmoteScale1))
+
+	// channel emotes take precedence, and are scoped to their channel
+	e, _ = c.Lookup("1", "Kappa")
+	assert.Equal(t, "c2", e.ID)
+	e, _ = c.Lookup("2", "Kappa")
+	assert.Equal(t, "25", e.ID)
+	assert.Equal(t, "globals", e.Type)
+	_, ok = c.Lookup("2", "forsenE")
+	assert.Equal(t, false, ok)
+
+	// emote set emotes take precedence over global emotes
+	e, _ = c.Lookup("", "LUL")
+	assert.Equal(t, "300-1", e.ID)
+	assert.Equal(t, "9", e.OwnerID)
+
+	e, ok = c.ByID("c1")
+	assert.Equal(t, true, ok)
+	assert.Equal(t, "forsenE", e.Code)
+	_, ok = c.ByID("unknown")
+	assert.Equal(t, false, ok)
+
+	assert.NoError(t, c.Refresh(ctx))
+	assert.Equal(t, "300,300", strings.Join(setRequests, ","))
+
+	// reloaded scopes keep their emotes indexed
+	e, ok = c.ByID("c2")
+	assert.Equal(t, true, ok)
+	assert.Equal(t, "Kappa", e.Code)
+	e, ok = c.ByID("300-1")
+	assert.Equal(t, true, ok)
+	assert.Equal(t, "300", e.SetID)
+}
+
+func TestEmoteCatalogSetPrecedence(t *testing.T) {
+	ctx := context.Background()
+	var setRequests []string
+
+	// the set with the lowest ID takes precedence, whatever the load order
+	for i := 0; i < 10; i++ {
+		c := NewEmoteCatalog(&EmoteCatalogOptions{Helix: emoteHelix(t, &setRequests)})
+		assert.NoError(t, c.LoadEmoteSets(ctx, "1000", "300", "20"))
+		e, _ := c.Lookup("", "LUL")
+		assert.Equal(t, "20-1", e.ID)
+
+		assert.NoError(t, c.LoadEmoteSets(ctx, "1000"))
+		e, _ = c.Lookup("", "LUL")
+		assert.Equal(t, "20-1", e.ID)
+		assert.NoError(t, c.LoadEmoteSets(ctx, "20", "300"))
+		e, _ = c.Lookup("", "LUL")
+		assert.Equal(t, "20-1", e.ID)
+
+		// reloading a set replaces its emotes in the indexes
+		_, ok := c.ByID("20-1")
+		assert.Equal(t, true, ok)
+		assert.Equal(t, 1, len(c.byID["20-1"]))
+		assert.Equal(t, 3, len(c.byCode["LUL"]))
+	}
+}
+
+func TestEmoteCatalogTokenize(t *testing.T) {
+	ctx := context.Background()
+	var setRequests []string
+	c := NewEmoteCatalog(&EmoteCatalogOptions{Helix: emoteHelix(t, &setRequests)})
+	assert.NoError(t, c.LoadGlobal(ctx))
+	assert.NoError(t, c.LoadChannel(ctx, "1"))
+
+	text := "héllo Kappa  👋 forsenE Kappa, LUL"
+	emotes := c.Tokenize("1", text)
+	assert.Equal(t, 3, len(emotes))
+	assert.Equal(t, "c2", emotes[0].ID)
+	assert.Equal(t, 6, emotes[0].Start)
+	assert.Equal(t, 10, emotes[0].End)
+	assert.Equal(t, "c1", emotes[1].ID)
+	assert.Equal(t, 15, emotes[1].Start)
+
+	// the positions line up with Fragments
+	fragments := Fragments(text, emotes, nil)
+	assert.Equal(t, "text:héllo |emote:Kappa|text:  👋 |emote:forsenE|text: Kappa, |emote:LUL", fragmentTexts(fragments))
+}
+
+func TestEmoteCatalogSetBatches(t *testing.T) {
+	var setRequests []string
+	c := NewEmoteCatalog(&EmoteCatalogOptions{Helix: emoteHelix(t, &setRequests)})
+
+	ids := make([]string, 30)
+	for i := range ids {
+		ids[i] = "s"
+	}
+	assert.NoError(t, c.LoadEmoteSets(context.Background(), ids...))
+	assert.Equal(t, 2, len(setRequests))
+
+	assert.ErrorIs(t, NewEmoteCatalog(nil).LoadGlobal(context.Background()), ErrMissingHelix)
+}
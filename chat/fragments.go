@@ -0,0 +1,262 @@
+package chat
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/aidenwallis/go-twitch-client/helix"
+	"github.com/aidenwallis/go-twitch-client/irc"
+)
+
+// FragmentType is the kind of a message fragment
+type FragmentType string
+
+// Fragment types, these match the fragment types of EventSub chat messages.
+const (
+	FragmentTypeText      FragmentType = "text"
+	FragmentTypeEmote     FragmentType = "emote"
+	FragmentTypeMention   FragmentType = "mention"
+	FragmentTypeCheermote FragmentType = "cheermote"
+)
+
+// Emote CDN values accepted by helix.FormatChatEmoteTemplate.
+const (
+	EmoteFormatDefault  = "default"
+	EmoteFormatStatic   = "static"
+	EmoteFormatAnimated = "animated"
+
+	EmoteThemeLight = "light"
+	EmoteThemeDark  = "dark"
+
+	EmoteScale1 = "1.0"
+	EmoteScale2 = "2.0"
+	EmoteScale3 = "3.0"
+)
+
+// DefaultCheermotePrefixes are the cheermote prefixes recognized when none are configured.
+var DefaultCheermotePrefixes = []string{"cheer"}
+
+// Fragment is a part of a chat message
+type Fragment struct {
+	// Type is the kind of fragment.
+	Type FragmentType
+
+	// Text is the text of the fragment, as it appears in the message.
+	Text string
+
+	// Emote is set for emote fragments.
+	Emote *FragmentEmote
+
+	// Mention is set for mention fragments, it is the mentioned login without the leading @.
+	Mention string
+
+	// Cheermote is set for cheermote fragments.
+	Cheermote *FragmentCheermote
+}
+
+// FragmentEmote is the emote of an emote fragment
+type FragmentEmote struct {
+	// ID is the emote ID.
+	ID string
+
+	// URL is the CDN URL of the emote image.
+	URL string
+}
+
+// FragmentCheermote is the cheermote of a cheermote fragment
+type FragmentCheermote struct {
+	// Prefix is the cheermote prefix, such as cheer, lowercased.
+	Prefix string
+
+	// Bits is the amount of bits cheered.
+	Bits int
+}
+
+// FragmentOptions defines the options for splitting messages into fragments
+type FragmentOptions struct {
+	// Template (optional) is the emote CDN template, defaults to helix.DefaultChatEmoteTemplate.
+	Template string
+
+	// Format (optional) is the emote format, defaults to EmoteFormatDefault which is animated when available.
+	Format string
+
+	// ThemeMode (optional) is the emote theme, defaults to EmoteThemeDark.
+	ThemeMode string
+
+	// Scale (optional) is the emote scale, defaults to EmoteScale1.
+	Scale string
+
+	// UTF16 is whether the emote positions are UTF-16 code unit indices, as some clients send, rather than the code
+	// point indices Twitch sends.
+	UTF16 bool
+
+	// Cheermotes is whether words such as cheer100 are split into cheermote fragments, only enable it for messages
+	// that contain bits.
+	Cheermotes bool
+
+	// CheermotePrefixes (optional) are the recognized cheermote prefixes, defaults to DefaultCheermotePrefixes.
+	CheermotePrefixes []string
+
+	// Emotes (optional) resolves the third-party emotes returned by EmoteCatalog.Tokenize to their image URLs.
+	Emotes *EmoteCatalog
+}
+
+func (o *FragmentOptions) emoteURL(id string) string {
+	template, format, themeMode, scale := helix.DefaultChatEmoteTemplate, EmoteFormatDefault, EmoteThemeDark, EmoteScale1
+	if o.Template != "" {
+		template = o.Template
+	}
+	if o.Format != "" {
+		format = o.Format
+	}
+	if o.ThemeMode != "" {
+		themeMode = o.ThemeMode
+	}
+	if o.Scale != "" {
+		scale = o.Scale
+	}
+	if o.Emotes != nil {
+		if e, ok := o.Emotes.ByID(id); ok && e.Provider != EmoteProviderTwitch {
+			return e.URL(format, themeMode, scale)
+		}
+	}
+	return helix.FormatChatEmoteTemplate(template, id, format, themeMode, scale)
+}
+
+// MessageFragments splits a PRIVMSG into fragments, cheermotes are only recognized when the message contains bits.
+func MessageFragments(msg *irc.PrivateMessage, options *FragmentOptions) []Fragment {
+	o := FragmentOptions{}
+	if options != nil {
+		o = *options
+	}
+	o.Cheermotes = o.Cheermotes || msg.Bits > 0
+	return Fragments(msg.Text, msg.Emotes, &o)
+}
+
+// Fragments splits the message text into ordered text, emote, mention and cheermote fragments.
+//
+// Emote positions are inclusive code point indices, as sent in the emotes tag. Positions that are out of range,
+// overlap an earlier emote, or split a character are ignored, so malformed tags never cause a panic.
+func Fragments(text string, emotes []irc.Emote, options *FragmentOptions) []Fragment {
+	if options == nil {
+		options = &FragmentOptions{}
+	}
+
+	offsets := indexOffsets(text, options.UTF16)
+
+	sorted := make([]irc.Emote, len(emotes))
+	copy(sorted, emotes)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Start < sorted[j].Start
+	})
+
+	var fragments []Fragment
+	pos := 0
+	for _, emote := range sorted {
+		if emote.Start < 0 || emote.End < emote.Start || emote.End+1 >= len(offsets) {
+			continue
+		}
+		start, end := offsets[emote.Start], offsets[emote.End+1]
+		if start < pos || end < 0 {
+			continue
+		}
+
+		fragments = appendText(fragments, text[pos:start], options)
+		fragments = append(fragments, Fragment{
+			Type:  FragmentTypeEmote,
+			Text:  text[start:end],
+			Emote: &FragmentEmote{ID: emote.ID, URL: options.emoteURL(emote.ID)},
+		})
+		pos = end
+	}
+	return appendText(fragments, text[pos:], options)
+}
+
+// indexOffsets maps every code point, or UTF-16 code unit, index to its byte offset in text. The last element is
+// len(text), and indices in the middle of a surrogate pair are -1.
+func indexOffsets(text string, utf16 bool) []int {
+	offsets := make([]int, 0, len(text)+1)
+	for i, r := range text {
+		offsets = append(offsets, i)
+		if utf16 && r > 0xFFFF {
+			// characters outside the basic multilingual plane are a surrogate pair in UTF-16
+			offsets = append(offsets, -1)
+		}
+	}
+	return append(offsets, len(text))
+}
+
+// appendText appends the text as text fragments, splitting out mentions and cheermotes.
+func appendText(fragments []Fragment, text string, options *FragmentOptions) []Fragment {
+	start := 0
+	for i := 0; i < len(text); {
+		// find the next word
+		for i < len(text) && text[i] == ' ' {
+			i++
+		}
+		wordStart := i
+		for i < len(text) && text[i] != ' ' {
+			i++
+		}
+		word := text[wordStart:i]
+		if word == "" {
+			break
+		}
+
+		fragment, ok := wordFragment(word, options)
+		if !ok {
+			continue
+		}
+
+		if wordStart > start {
+			fragments = append(fragments, Fragment{Type: FragmentTypeText, Text: text[start:wordStart]})
+		}
+		fragments = append(fragments, fragment)
+		start = wordStart + len(fragment.Text)
+	}
+
+	if start < len(text) {
+		fragments = append(fragments, Fragment{Type: FragmentTypeText, Text: text[start:]})
+	}
+	return fragments
+}
+
+// wordFragment returns the mention or cheermote fragment of a word, if it is one. Punctuation trailing a mention is
+// not part of the fragment.
+func wordFragment(word string, options *FragmentOptions) (Fragment, bool) {
+	if len(word) > 1 && word[0] == '@' {
+		if login := strings.TrimRight(word[1:], ",.:;!?"); login != "" {
+			return Fragment{Type: FragmentTypeMention, Text: "@" + login, Mention: strings.ToLower(login)}, true
+		}
+	}
+
+	if !options.Cheermotes {
+		return Fragment{}, false
+	}
+
+	i := len(word)
+	for i > 0 && word[i-1] >= '0' && word[i-1] <= '9' {
+		i--
+	}
+	if i == 0 || i == len(word) {
+		return Fragment{}, false
+	}
+
+	prefixes := options.CheermotePrefixes
+	if prefixes == nil {
+		prefixes = DefaultCheermotePrefixes
+	}
+	prefix := strings.ToLower(word[:i])
+	for _, p := range prefixes {
+		if strings.ToLower(p) != prefix {
+			continue
+		}
+		bits, err := strconv.Atoi(word[i:])
+		if err != nil || bits <= 0 {
+			return Fragment{}, false
+		}
+		return Fragment{Type: FragmentTypeCheermote, Text: word, Cheermote: &FragmentCheermote{Prefix: prefix, Bits: bits}}, true
+	}
+	return Fragment{}, false
+}
@@ -0,0 +1,84 @@
+package chat
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/aidenwallis/go-twitch-client/internal/testutils/assert"
+	"github.com/aidenwallis/go-twitch-client/irc"
+)
+
+// fragmentTexts joins the fragments as type:text pairs, for compact assertions
+func fragmentTexts(fragments []Fragment) string {
+	parts := make([]string, len(fragments))
+	for i, fragment := range fragments {
+		parts[i] = string(fragment.Type) + ":" + fragment.Text
+	}
+	return strings.Join(parts, "|")
+}
+
+func TestFragments(t *testing.T) {
+	text := "Kappa hi @Forsen, Kappa"
+	fragments := Fragments(text, irc.ParseEmotes("25:0-4,18-22"), nil)
+	assert.Equal(t, "emote:Kappa|text: hi |mention:@Forsen|text:, |emote:Kappa", fragmentTexts(fragments))
+	assert.Equal(t, "25", fragments[0].Emote.ID)
+	assert.Equal(t, "https://static-cdn.jtvnw.net/emoticons/v2/25/default/dark/1.0", fragments[0].Emote.URL)
+	assert.Equal(t, "forsen", fragments[2].Mention)
+
+	fragments = Fragments("Kappa", irc.ParseEmotes("25:0-4"), &FragmentOptions{
+		Format:    EmoteFormatStatic,
+		ThemeMode: EmoteThemeLight,
+		Scale:     EmoteScale3,
+	})
+	assert.Equal(t, "https://static-cdn.jtvnw.net/emoticons/v2/25/static/light/3.0", fragments[0].Emote.URL)
+
+	assert.Equal(t, "text:just text", fragmentTexts(Fragments("just text", nil, nil)))
+	assert.Equal(t, 0, len(Fragments("", nil, nil)))
+}
+
+func TestFragmentsMultiByte(t *testing.T) {
+	// code point indices: the emoji and accented characters are a single index each
+	text := "héllo 👋 Kappa"
+	assert.Equal(t, "text:héllo 👋 |emote:Kappa", fragmentTexts(Fragments(text, irc.ParseEmotes("25:8-12"), nil)))
+
+	// the emoji is a surrogate pair in UTF-16, shifting the emote by one index
+	fragments := Fragments(text, irc.ParseEmotes("25:9-13"), &FragmentOptions{UTF16: true})
+	assert.Equal(t, "text:héllo 👋 |emote:Kappa", fragmentTexts(fragments))
+
+	// emotes splitting a surrogate pair are ignored
+	fragments = Fragments("👋👋", irc.ParseEmotes("1:1-2"), &FragmentOptions{UTF16: true})
+	assert.Equal(t, "text:👋👋", fragmentTexts(fragments))
+
+	// emotes on emoji
+	fragments = Fragments("👋 hi", irc.ParseEmotes("1:0-0"), nil)
+	assert.Equal(t, "emote:👋|text: hi", fragmentTexts(fragments))
+}
+
+func TestFragmentsMalformedEmotes(t *testing.T) {
+	emotes := []irc.Emote{
+		{ID: "out-of-range", Start: 4, End: 10},
+		{ID: "overlapping", Start: 1, End: 2},
+		{ID: "ok", Start: 0, End: 1},
+		{ID: "negative", Start: -1, End: 0},
+		{ID: "reversed", Start: 3, End: 2},
+	}
+	assert.Equal(t, "emote:ab|text:cde", fragmentTexts(Fragments("abcde", emotes, nil)))
+}
+
+func TestFragmentsCheermotes(t *testing.T) {
+	msg, err := irc.Parse("@bits=101;emotes= :a!a@a PRIVMSG #forsen :Cheer100 nice cheer1 Kappa1 cheer0")
+	assert.NoError(t, err)
+	privmsg, err := msg.PrivateMessage()
+	assert.NoError(t, err)
+
+	fragments := MessageFragments(privmsg, nil)
+	assert.Equal(t, "cheermote:Cheer100|text: nice |cheermote:cheer1|text: Kappa1 cheer0", fragmentTexts(fragments))
+	assert.Equal(t, "cheer", fragments[0].Cheermote.Prefix)
+	assert.Equal(t, 100, fragments[0].Cheermote.Bits)
+
+	fragments = Fragments("Kappa1 cheer1", nil, &FragmentOptions{Cheermotes: true, CheermotePrefixes: []string{"Kappa"}})
+	assert.Equal(t, "cheermote:Kappa1|text: cheer1", fragmentTexts(fragments))
+
+	// without bits, cheermotes are plain text
+	assert.Equal(t, "text:cheer100", fragmentTexts(Fragments("cheer100", nil, nil)))
+}
@@ -0,0 +1,166 @@
+package chat
+
+import (
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/aidenwallis/go-twitch-client/helix"
+)
+
+const (
+	// commandEscape is prepended to messages starting with a command prefix, so Twitch sends them as text. The zero
+	// width space is not rendered by chat clients.
+	commandEscape = "\u200b"
+
+	// duplicateSuffix is appended to a message identical to the previous message sent to the channel, so Twitch does
+	// not drop it. Chat clients do not render the tag character, and trim the space before it.
+	duplicateSuffix = " \U000E0000"
+)
+
+// MessagePreparerOptions defines all options the message preparer supports.
+type MessagePreparerOptions struct {
+	// MaxLength (optional) is the maximum number of characters per message, defaults to helix.MaxChatMessageLength.
+	// It is clamped to helix.MaxChatMessageLength, and to at least 2 characters, or 4 with VaryDuplicates, so every
+	// message has room for a character besides the escape and the duplicate suffix.
+	MaxLength int
+
+	// VaryDuplicates (optional) appends an invisible character to a message identical to the previous message sent
+	// to the channel, which Twitch would otherwise drop.
+	VaryDuplicates bool
+}
+
+// MessagePreparer prepares chat messages for sending: it replaces line breaks, splits messages longer than the limit
+// on word boundaries, escapes messages that Twitch would run as a command, such as "/ban" or ".me", and optionally
+// varies duplicate messages.
+//
+// A MessagePreparer remembers the last message sent to every channel, clients logged in as the same account should
+// share one.
+type MessagePreparer struct {
+	maxLength      int
+	varyDuplicates bool
+
+	mu   sync.Mutex
+	last map[string]string
+}
+
+// NewMessagePreparer creates a new instance of MessagePreparer
+func NewMessagePreparer(options *MessagePreparerOptions) *MessagePreparer {
+	if options == nil {
+		options = &MessagePreparerOptions{}
+	}
+
+	p := &MessagePreparer{
+		maxLength:      helix.MaxChatMessageLength,
+		varyDuplicates: options.VaryDuplicates,
+		last:           map[string]string{},
+	}
+	if options.MaxLength > 0 && options.MaxLength < p.maxLength {
+		p.maxLength = options.MaxLength
+	}
+
+	minLength := 1 + utf8.RuneCountInString(commandEscape)
+	if p.varyDuplicates {
+		minLength += utf8.RuneCountInString(duplicateSuffix)
+	}
+	if p.maxLength < minLength {
+		p.maxLength = minLength
+	}
+	return p
+}
+
+// Prepare returns the messages to send to the channel for text, in order. It returns helix.ErrEmptyChatMessage when
+// text is empty after trimming whitespace.
+//
+// Prepare records the messages as the last sent to the channel, so they should be sent.
+func (p *MessagePreparer) Prepare(channel, text string) ([]string, error) {
+	text = strings.TrimSpace(strings.Map(func(r rune) rune {
+		if r == '\r' || r == '\n' {
+			return ' '
+		}
+		return r
+	}, text))
+
+	limit := p.maxLength
+	if p.varyDuplicates {
+		limit -= utf8.RuneCountInString(duplicateSuffix)
+	}
+
+	messages := splitMessage(text, limit)
+	for _, message := range messages {
+		if err := helix.ValidateChatMessage(message); err != nil {
+			return nil, err
+		}
+	}
+	if len(messages) == 0 {
+		return nil, helix.ErrEmptyChatMessage
+	}
+
+	if p.varyDuplicates {
+		channel = normalizeChannel(channel)
+
+		p.mu.Lock()
+		for i, message := range messages {
+			if message == p.last[channel] {
+				messages[i] = message + duplicateSuffix
+			}
+			p.last[channel] = messages[i]
+		}
+		p.mu.Unlock()
+	}
+	return messages, nil
+}
+
+// splitMessage splits text into messages of at most limit characters, on word boundaries where possible, escaping
+// messages that start with a command prefix.
+func splitMessage(text string, limit int) []string {
+	var messages []string
+	for text != "" {
+		escape := hasCommandPrefix(text)
+		partLimit := limit
+		if escape {
+			partLimit -= utf8.RuneCountInString(commandEscape)
+		}
+		if partLimit < 1 {
+			partLimit = 1
+		}
+
+		message, rest := cutWords(text, partLimit)
+		if escape {
+			message = commandEscape + message
+		}
+		messages = append(messages, message)
+		text = strings.TrimLeftFunc(rest, unicode.IsSpace)
+	}
+	return messages
+}
+
+// cutWords cuts text after at most limit characters, at the last whitespace before the limit. Words longer than the
+// limit are cut at the limit.
+func cutWords(text string, limit int) (string, string) {
+	end, n := len(text), 0
+	for i := range text {
+		if n == limit {
+			end = i
+			break
+		}
+		n++
+	}
+	if end == len(text) {
+		return text, ""
+	}
+
+	if r, _ := utf8.DecodeRuneInString(text[end:]); unicode.IsSpace(r) {
+		return strings.TrimRightFunc(text[:end], unicode.IsSpace), text[end:]
+	}
+	if i := strings.LastIndexFunc(text[:end], unicode.IsSpace); i > 0 {
+		return strings.TrimRightFunc(text[:i], unicode.IsSpace), text[i:]
+	}
+	return text[:end], text[end:]
+}
+
+// hasCommandPrefix returns whether Twitch would run the message as a command
+func hasCommandPrefix(text string) bool {
+	return strings.HasPrefix(text, "/") || strings.HasPrefix(text, ".")
+}
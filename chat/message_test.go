@@ -0,0 +1,117 @@
+package chat
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/aidenwallis/go-twitch-client/helix"
+	"github.com/aidenwallis/go-twitch-client/internal/testutils/assert"
+)
+
+func TestMessagePreparerSplit(t *testing.T) {
+	p := NewMessagePreparer(&MessagePreparerOptions{MaxLength: 12})
+
+	tests := map[string]string{
+		"hello":                        "hello",
+		"  hello world, how are you  ": "hello world,|how are you",
+		"line one\r\nline two":         "line one|line two",
+		"exactly twelve":               "exactly|twelve",
+		"abcdefghijklmnopqrstuvwxyz":   "abcdefghijkl|mnopqrstuvwx|yz",
+		"äöü äöü äöü äöü":              "äöü äöü äöü|äöü",
+		"/ban forsen":                  "\u200b/ban forsen",
+		".me waves":                    "\u200b.me waves",
+		"say this: /ban forsen":        "say this:|\u200b/ban forsen",
+		"a /ban":                       "a /ban",
+	}
+	for in, expected := range tests {
+		messages, err := p.Prepare("forsen", in)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, strings.Join(messages, "|"))
+		for _, message := range messages {
+			assert.Equal(t, true, utf8.RuneCountInString(message) <= 12)
+		}
+	}
+
+	for _, in := range []string{"", " \t", "\r\n"} {
+		_, err := p.Prepare("forsen", in)
+		assert.ErrorIs(t, err, helix.ErrEmptyChatMessage)
+	}
+
+	// messages are split at the chat limit by default
+	messages, err := NewMessagePreparer(nil).Prepare("forsen", strings.Repeat("word ", 150))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(messages))
+	assert.Equal(t, helix.MaxChatMessageLength-1, len(messages[0]))
+
+	// the maximum length is clamped to the chat limit
+	messages, err = NewMessagePreparer(&MessagePreparerOptions{MaxLength: 1000}).Prepare("forsen", strings.Repeat("a", 600))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(messages))
+	assert.Equal(t, helix.MaxChatMessageLength, len(messages[0]))
+}
+
+func TestMessagePreparerMinLength(t *testing.T) {
+	tests := map[bool]int{false: 2, true: 4}
+	for vary, minLength := range tests {
+		p := NewMessagePreparer(&MessagePreparerOptions{MaxLength: 1, VaryDuplicates: vary})
+		for i := 0; i < 2; i++ {
+			messages, err := p.Prepare("forsen", "/ban forsen")
+			assert.NoError(t, err)
+			for _, message := range messages {
+				assert.Equal(t, true, utf8.RuneCountInString(message) <= minLength)
+				assert.NoError(t, helix.ValidateChatMessage(message))
+			}
+		}
+	}
+}
+
+func TestMessagePreparerDuplicates(t *testing.T) {
+	p := NewMessagePreparer(&MessagePreparerOptions{MaxLength: 10, VaryDuplicates: true})
+
+	prepare := func(channel, text string) string {
+		messages, err := p.Prepare(channel, text)
+		assert.NoError(t, err)
+		return strings.Join(messages, "|")
+	}
+
+	assert.Equal(t, "hi", prepare("forsen", "hi"))
+	assert.Equal(t, "hi"+duplicateSuffix, prepare("#Forsen", "hi"))
+	assert.Equal(t, "hi", prepare("forsen", "hi"))
+	assert.Equal(t, "hi", prepare("nymn", "hi"))
+
+	// room is left for the suffix, including between the parts of a message
+	assert.Equal(t, "abcdefgh|abcdefgh"+duplicateSuffix, prepare("forsen", "abcdefghabcdefgh"))
+}
+
+func TestClientMessagePreparer(t *testing.T) {
+	srv := newFakeServer(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	c := NewClient(&ClientOptions{
+		Address:         srv.address(),
+		Login:           "bot",
+		MessagePreparer: NewMessagePreparer(&MessagePreparerOptions{MaxLength: 11}),
+	})
+	connected := make(chan struct{})
+	c.OnConnect(func(context.Context) {
+		close(connected)
+	})
+	go func() {
+		_ = c.Connect(ctx)
+	}()
+
+	conn := srv.accept(t)
+	conn.login(t, "", "bot")
+	<-connected
+
+	assert.NoError(t, c.Reply(ctx, "forsen", "m1", "hello there world"))
+	conn.expect(t, "@reply-parent-msg-id=m1 PRIVMSG #forsen :hello there")
+	conn.expect(t, "@reply-parent-msg-id=m1 PRIVMSG #forsen world")
+	assert.NoError(t, c.Say(ctx, "forsen", "/ban me"))
+	conn.expect(t, "PRIVMSG #forsen :\u200b/ban me")
+	assert.ErrorIs(t, c.Say(ctx, "forsen", " "), helix.ErrEmptyChatMessage)
+}
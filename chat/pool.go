@@ -0,0 +1,440 @@
+package chat
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/aidenwallis/go-twitch-client/irc"
+)
+
+// defaultChannelsPerConnection is how many channels a pool connection joins by default.
+const defaultChannelsPerConnection = 50
+
+// PoolOptions defines all options the connection pool supports.
+type PoolOptions struct {
+	// ClientOptions (optional) are the options every connection is created with. Channels is ignored, use
+	// PoolOptions.Channels instead. The RateLimiter is shared by every connection, a new one is created when it is nil,
+	// so the join limit of the account is respected across connections.
+	ClientOptions *ClientOptions
+
+	// ChannelsPerConnection (optional) is the maximum number of channels joined per connection, defaults to 50.
+	ChannelsPerConnection int
+
+	// Channels (optional) are joined once the pool runs.
+	Channels []string
+}
+
+// Pool shards channels across several chat connections, presenting their messages as a single stream.
+//
+// New connections are created as channels are joined, once every connection holds ChannelsPerConnection channels. When
+// a connection fails, its channels are moved to the connected connections with room for them, so they keep receiving
+// messages while the failed connection reconnects. Once it reconnects, the moved channels are moved back to it.
+// Connections left without channels are closed.
+type Pool struct {
+	options       ClientOptions
+	limiter       *RateLimiter
+	perConnection int
+
+	mu       sync.Mutex
+	shards   []*poolShard
+	channels map[string]*poolShard
+	ctx      context.Context // set while running, cleared once Run stops
+	wg       sync.WaitGroup
+	fatal    chan error
+
+	onMessage func(ctx context.Context, msg *irc.Message)
+	onError   func(err error)
+}
+
+// poolShard is a connection of the pool, and the channels assigned to it
+type poolShard struct {
+	client   *Client
+	channels map[string]struct{}
+	lent     map[string]struct{} // channels moved to other connections when the connection failed
+	cancel   context.CancelFunc  // stops the connection, set while running
+}
+
+// NewPool creates a new instance of Pool
+func NewPool(options *PoolOptions) *Pool {
+	if options == nil {
+		options = &PoolOptions{}
+	}
+
+	p := &Pool{
+		perConnection: defaultChannelsPerConnection,
+		channels:      map[string]*poolShard{},
+		fatal:         make(chan error, 1),
+	}
+	if options.ClientOptions != nil {
+		p.options = *options.ClientOptions
+	}
+	p.options.Channels = nil
+	p.limiter = p.options.RateLimiter
+	if p.limiter == nil {
+		p.limiter = NewRateLimiter(nil)
+	}
+	p.options.RateLimiter = p.limiter
+	if options.ChannelsPerConnection > 0 {
+		p.perConnection = options.ChannelsPerConnection
+	}
+
+	p.assign(options.Channels)
+	return p
+}
+
+// OnMessage sets the handler the messages of every connection are passed to. Messages about a connection, such as
+// PING, are received once per connection.
+//
+// Handlers must be registered before calling Join or Run.
+func (p *Pool) OnMessage(handler func(ctx context.Context, msg *irc.Message)) {
+	p.onMessage = handler
+}
+
+// OnError sets the handler for errors that do not stop the pool, such as failed connection attempts.
+//
+// Handlers must be registered before calling Join or Run.
+func (p *Pool) OnError(handler func(err error)) {
+	p.onError = handler
+}
+
+// RateLimiter returns the rate limiter shared by every connection.
+func (p *Pool) RateLimiter() *RateLimiter {
+	return p.limiter
+}
+
+// Connections returns the number of connections.
+func (p *Pool) Connections() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return len(p.shards)
+}
+
+// Channels returns the sorted channels the pool is joined to, or joins once running.
+func (p *Pool) Channels() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	channels := make([]string, 0, len(p.channels))
+	for channel := range p.channels {
+		channels = append(channels, channel)
+	}
+	sort.Strings(channels)
+	return channels
+}
+
+// Join joins the channels, creating connections as needed. Join blocks while the join rate limit is exceeded.
+func (p *Pool) Join(ctx context.Context, channels ...string) error {
+	for shard, assigned := range p.assign(channels) {
+		if err := shard.client.Join(ctx, assigned...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Part leaves the channels.
+func (p *Pool) Part(channels ...string) error {
+	p.mu.Lock()
+	parts := map[*poolShard][]string{}
+	for _, channel := range channels {
+		channel = normalizeChannel(channel)
+		shard := p.channels[channel]
+		if shard == nil {
+			continue
+		}
+		delete(p.channels, channel)
+		delete(shard.channels, channel)
+		for _, s := range p.shards {
+			delete(s.lent, channel)
+		}
+		parts[shard] = append(parts[shard], channel)
+	}
+	// closed connections leave their channels
+	for shard := range parts {
+		if len(shard.channels) == 0 {
+			p.close(shard)
+			delete(parts, shard)
+		}
+	}
+	p.mu.Unlock()
+
+	for shard, channels := range parts {
+		if err := shard.client.Part(channels...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Say sends a chat message to the channel through the connection that joined it, see Client.Say.
+func (p *Pool) Say(ctx context.Context, channel, text string) error {
+	shard := p.shardOf(channel)
+	if shard == nil {
+		return ErrNotConnected
+	}
+	return shard.client.Say(ctx, channel, text)
+}
+
+// Reply sends a reply to a chat message through the connection that joined the channel, see Client.Reply.
+func (p *Pool) Reply(ctx context.Context, channel, parentID, text string) error {
+	shard := p.shardOf(channel)
+	if shard == nil {
+		return ErrNotConnected
+	}
+	return shard.client.Reply(ctx, channel, parentID, text)
+}
+
+// Run connects every connection, and keeps them connected until ctx is cancelled, which is returned. Run returns early
+// with the error of a connection that cannot reconnect, such as ErrLoginFailed.
+func (p *Pool) Run(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	p.mu.Lock()
+	p.ctx = ctx
+	for _, shard := range p.shards {
+		p.start(shard)
+	}
+	p.mu.Unlock()
+
+	var err error
+	select {
+	case <-ctx.Done():
+		err = ctx.Err()
+	case err = <-p.fatal:
+	}
+
+	// no connections are started once ctx is cleared, so none are started while waiting
+	p.mu.Lock()
+	p.ctx = nil
+	p.mu.Unlock()
+
+	cancel()
+	p.wg.Wait()
+	return err
+}
+
+// assign assigns the channels to the least loaded connections with room for them, creating connections when every
+// connection is full. It returns the channels assigned to existing connections, which still have to be joined.
+func (p *Pool) assign(channels []string) map[*poolShard][]string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	joins := map[*poolShard][]string{}
+	created := map[*poolShard][]string{}
+	for _, channel := range channels {
+		channel = normalizeChannel(channel)
+		if _, ok := p.channels[channel]; ok || channel == "" {
+			continue
+		}
+
+		shard := p.leastLoaded(nil, false)
+		if shard == nil {
+			shard = &poolShard{channels: map[string]struct{}{}, lent: map[string]struct{}{}}
+			p.shards = append(p.shards, shard)
+		}
+		shard.channels[channel] = struct{}{}
+		p.channels[channel] = shard
+
+		if shard.client == nil {
+			created[shard] = append(created[shard], channel)
+		} else {
+			joins[shard] = append(joins[shard], channel)
+		}
+	}
+
+	// new connections join their channels once connected
+	for shard, channels := range created {
+		shard.client = p.newClient(shard, channels)
+		if p.ctx != nil {
+			p.start(shard)
+		}
+	}
+	return joins
+}
+
+// leastLoaded returns the connection with the fewest channels that has room for another, nil when every connection is
+// full. p.mu must be held.
+func (p *Pool) leastLoaded(exclude *poolShard, connected bool) *poolShard {
+	var best *poolShard
+	for _, shard := range p.shards {
+		if shard == exclude || len(shard.channels) >= p.perConnection {
+			continue
+		}
+		if connected && (shard.client == nil || shard.client.Nick() == "") {
+			continue
+		}
+		if best == nil || len(shard.channels) < len(best.channels) {
+			best = shard
+		}
+	}
+	return best
+}
+
+func (p *Pool) newClient(shard *poolShard, channels []string) *Client {
+	options := p.options
+	options.Channels = channels
+
+	c := NewClient(&options)
+	c.OnMessage(func(ctx context.Context, msg *irc.Message) {
+		if p.onMessage != nil {
+			p.onMessage(ctx, msg)
+		}
+	})
+	c.OnConnect(func(context.Context) {
+		p.reclaim(shard)
+	})
+	c.OnError(p.reportError)
+	c.onDisconnect = func(error) {
+		p.rebalance(shard)
+	}
+	return c
+}
+
+// start runs the connection until the pool stops or the connection is closed. p.mu must be held, while the pool is
+// running.
+func (p *Pool) start(shard *poolShard) {
+	ctx, cancel := context.WithCancel(p.ctx)
+	shard.cancel = cancel
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		defer cancel()
+
+		err := shard.client.Connect(ctx)
+		if ctx.Err() == nil {
+			select {
+			case p.fatal <- err:
+			default:
+			}
+		}
+	}()
+}
+
+// rebalance moves the channels of a failed connection to the connected connections with room for them.
+func (p *Pool) rebalance(failed *poolShard) {
+	p.mu.Lock()
+	ctx := p.ctx
+	if ctx == nil {
+		p.mu.Unlock()
+		return
+	}
+
+	channels := make([]string, 0, len(failed.channels))
+	for channel := range failed.channels {
+		channels = append(channels, channel)
+	}
+	sort.Strings(channels)
+
+	moves := map[*poolShard][]string{}
+	var moved []string
+	for _, channel := range channels {
+		shard := p.leastLoaded(failed, true)
+		if shard == nil {
+			break
+		}
+		delete(failed.channels, channel)
+		failed.lent[channel] = struct{}{}
+		shard.channels[channel] = struct{}{}
+		p.channels[channel] = shard
+		moves[shard] = append(moves[shard], channel)
+		moved = append(moved, channel)
+	}
+
+	// the failed connection is not connected, and does not reconnect until rebalance returns, so this only stops it
+	// from rejoining the moved channels
+	_ = failed.client.Part(moved...)
+
+	for shard, channels := range moves {
+		shard, channels := shard, channels
+		p.wg.Add(1)
+		go func() {
+			defer p.wg.Done()
+			if err := shard.client.Join(ctx, channels...); err != nil && ctx.Err() == nil {
+				p.reportError(err)
+			}
+		}()
+	}
+	p.mu.Unlock()
+}
+
+// reclaim moves the channels a connection lent to other connections when it failed back to it, once it logged in
+// again. The channels are joined with the other channels of the connection, as reclaim is called before it joins
+// them. The connection is closed when it is left without channels.
+func (p *Pool) reclaim(target *poolShard) {
+	p.mu.Lock()
+	if p.ctx == nil {
+		p.mu.Unlock()
+		return
+	}
+
+	parts := map[*poolShard][]string{}
+	var moved []string
+	for channel := range target.lent {
+		delete(target.lent, channel)
+
+		// channels parted since are not joined again
+		shard := p.channels[channel]
+		if shard == nil || shard == target {
+			continue
+		}
+		delete(shard.channels, channel)
+		delete(shard.lent, channel)
+		target.channels[channel] = struct{}{}
+		p.channels[channel] = target
+		parts[shard] = append(parts[shard], channel)
+		moved = append(moved, channel)
+	}
+	for _, shard := range p.shards {
+		for _, channel := range moved {
+			delete(shard.lent, channel)
+		}
+	}
+
+	target.client.addChannels(moved)
+	if len(target.channels) == 0 {
+		p.close(target)
+	}
+	p.mu.Unlock()
+
+	for shard, channels := range parts {
+		sort.Strings(channels)
+		p.reportError(shard.client.Part(channels...))
+	}
+}
+
+// close stops the connection and removes it from the pool. p.mu must be held.
+func (p *Pool) close(shard *poolShard) {
+	for i, s := range p.shards {
+		if s == shard {
+			p.shards = append(p.shards[:i], p.shards[i+1:]...)
+			break
+		}
+	}
+	if shard.cancel != nil {
+		shard.cancel()
+	}
+}
+
+// shardOf returns the connection that joined the channel, or the first connection when no connection joined it.
+func (p *Pool) shardOf(channel string) *poolShard {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if shard := p.channels[normalizeChannel(channel)]; shard != nil {
+		return shard
+	}
+	if len(p.shards) > 0 {
+		return p.shards[0]
+	}
+	return nil
+}
+
+// reportError passes non-nil errors to the OnError handler.
+func (p *Pool) reportError(err error) {
+	if err != nil && p.onError != nil {
+		p.onError(err)
+	}
+}
@@ -0,0 +1,207 @@
+package chat
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aidenwallis/go-twitch-client/internal/testutils/assert"
+	"github.com/aidenwallis/go-twitch-client/irc"
+)
+
+// readLine reads the next line the client sent
+func (c *fakeConn) readLine(t *testing.T) string {
+	t.Helper()
+	_ = c.conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	line, err := c.reader.ReadString('\n')
+	assert.NoError(t, err)
+	return strings.TrimRight(line, "\r\n")
+}
+
+func TestPool(t *testing.T) {
+	srv := newFakeServer(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	messages := make(chan *irc.Message, 10)
+	errs := make(chan error, 10)
+	p := NewPool(&PoolOptions{
+		ClientOptions:         &ClientOptions{Address: srv.address(), Login: "bot", Token: "abc", Backoff: time.Millisecond},
+		ChannelsPerConnection: 3,
+		Channels:              []string{"a", "#B", "c", "d"},
+	})
+	p.OnMessage(func(ctx context.Context, msg *irc.Message) {
+		if msg.Command == irc.CommandPrivmsg {
+			messages <- msg
+		}
+	})
+	p.OnError(func(err error) {
+		errs <- err
+	})
+	assert.Equal(t, 2, p.Connections())
+	assert.Equal(t, "a,b,c,d", strings.Join(p.Channels(), ","))
+
+	done := make(chan error, 1)
+	go func() {
+		done <- p.Run(ctx)
+	}()
+
+	// connections are dialed concurrently, so they may be accepted in any order
+	conns := map[string]*fakeConn{}
+	for i := 0; i < 2; i++ {
+		conn := srv.accept(t)
+		conn.login(t, "oauth:abc", "bot")
+		conns[conn.readLine(t)] = conn
+	}
+	full, spare := conns["JOIN #a,#b,#c"], conns["JOIN #d"]
+	assert.Equal(t, true, full != nil && spare != nil)
+
+	// messages of every connection are merged
+	full.send(t, ":a!a@a PRIVMSG #a :hello")
+	assert.Equal(t, "hello", (<-messages).Trailing())
+	spare.send(t, ":d!d@d PRIVMSG #d :world")
+	assert.Equal(t, "world", (<-messages).Trailing())
+
+	// messages are sent through the connection that joined the channel
+	assert.NoError(t, p.Say(ctx, "D", "hi chat"))
+	spare.expect(t, "PRIVMSG #d :hi chat")
+	assert.NoError(t, p.Reply(ctx, "a", "m1", "hi there"))
+	full.expect(t, "@reply-parent-msg-id=m1 PRIVMSG #a :hi there")
+
+	// channels are joined on the least loaded connection, until every connection is full
+	assert.NoError(t, p.Join(ctx, "e", "a"))
+	spare.expect(t, "JOIN #e")
+	assert.NoError(t, p.Part("b"))
+	full.expect(t, "PART #b")
+
+	// channels of a failed connection move to connections with room, and move back once it reconnected
+	_ = full.conn.Close()
+	assert.Equal(t, true, <-errs != nil)
+	spare.expect(t, "JOIN #a")
+
+	conn := srv.accept(t)
+	conn.login(t, "oauth:abc", "bot")
+	spare.expect(t, "PART #a")
+	conn.expect(t, "JOIN #a,#c")
+	assert.Equal(t, 2, p.Connections())
+	assert.Equal(t, "a,c,d,e", strings.Join(p.Channels(), ","))
+
+	cancel()
+	assert.ErrorIs(t, <-done, context.Canceled)
+}
+
+func TestPoolLoginFailed(t *testing.T) {
+	srv := newFakeServer(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	limiter := NewRateLimiter(nil)
+	p := NewPool(&PoolOptions{
+		ClientOptions: &ClientOptions{Address: srv.address(), Login: "bot", Token: "expired", RateLimiter: limiter},
+	})
+	assert.Equal(t, limiter, p.RateLimiter())
+	assert.ErrorIs(t, p.Say(ctx, "forsen", "hi"), ErrNotConnected)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- p.Run(ctx)
+	}()
+
+	// connections are created once channels are joined, and start right away while running
+	assert.NoError(t, p.Join(ctx, "forsen"))
+	assert.Equal(t, 1, p.Connections())
+
+	conn := srv.accept(t)
+	conn.expect(t, "CAP REQ :twitch.tv/tags twitch.tv/commands twitch.tv/membership")
+	conn.expect(t, "PASS oauth:expired")
+	conn.expect(t, "NICK bot")
+	conn.send(t, ":tmi.twitch.tv NOTICE * :Login authentication failed")
+	assert.ErrorIs(t, <-done, ErrLoginFailed)
+}
+
+func TestPoolFlapping(t *testing.T) {
+	srv := newFakeServer(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	errs := make(chan error, 10)
+	p := NewPool(&PoolOptions{
+		ClientOptions:         &ClientOptions{Address: srv.address(), Login: "bot", Token: "abc", Backoff: time.Millisecond},
+		ChannelsPerConnection: 3,
+		Channels:              []string{"a", "b", "c", "d"},
+	})
+	p.OnError(func(err error) {
+		errs <- err
+	})
+
+	done := make(chan error, 1)
+	go func() {
+		done <- p.Run(ctx)
+	}()
+
+	conns := map[string]*fakeConn{}
+	for i := 0; i < 2; i++ {
+		conn := srv.accept(t)
+		conn.login(t, "oauth:abc", "bot")
+		conns[conn.readLine(t)] = conn
+	}
+	full, spare := conns["JOIN #a,#b,#c"], conns["JOIN #d"]
+	assert.Equal(t, true, full != nil && spare != nil)
+
+	// channels do not pile up on the other connection when a connection fails repeatedly
+	for i := 0; i < 2; i++ {
+		_ = full.conn.Close()
+		assert.Equal(t, true, <-errs != nil)
+		spare.expect(t, "JOIN #a,#b")
+
+		full = srv.accept(t)
+		full.login(t, "oauth:abc", "bot")
+		spare.expect(t, "PART #a,#b")
+		full.expect(t, "JOIN #a,#b,#c")
+		assert.Equal(t, p.shardOf("c"), p.shardOf("a"))
+		assert.Equal(t, p.shardOf("c"), p.shardOf("b"))
+		assert.Equal(t, false, p.shardOf("c") == p.shardOf("d"))
+	}
+
+	cancel()
+	assert.ErrorIs(t, <-done, context.Canceled)
+}
+
+func TestPoolCloseEmpty(t *testing.T) {
+	srv := newFakeServer(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	p := NewPool(&PoolOptions{
+		ClientOptions:         &ClientOptions{Address: srv.address(), Login: "bot", Token: "abc"},
+		ChannelsPerConnection: 1,
+		Channels:              []string{"a", "b"},
+	})
+
+	done := make(chan error, 1)
+	go func() {
+		done <- p.Run(ctx)
+	}()
+
+	conns := map[string]*fakeConn{}
+	for i := 0; i < 2; i++ {
+		conn := srv.accept(t)
+		conn.login(t, "oauth:abc", "bot")
+		conns[conn.readLine(t)] = conn
+	}
+
+	// connections without channels are closed
+	assert.NoError(t, p.Part("b"))
+	assert.Equal(t, 1, p.Connections())
+	_ = conns["JOIN #b"].conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	_, err := conns["JOIN #b"].reader.ReadString('\n')
+	assert.ErrorIs(t, err, io.EOF)
+
+	assert.NoError(t, p.Say(ctx, "a", "hi chat"))
+	conns["JOIN #a"].expect(t, "PRIVMSG #a :hi chat")
+
+	cancel()
+	assert.ErrorIs(t, <-done, context.Canceled)
+}
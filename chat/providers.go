@@ -0,0 +1,379 @@
+package chat
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aidenwallis/go-twitch-client/internal/client"
+)
+
+// Third-party emote providers.
+const (
+	EmoteProviderBetterTTV    = "bttv"
+	EmoteProviderFrankerFaceZ = "ffz"
+	EmoteProviderSevenTV      = "7tv"
+)
+
+// Default base URLs of the third-party emote APIs.
+const (
+	DefaultBetterTTVURL    = "https://api.betterttv.net/3"
+	DefaultFrankerFaceZURL = "https://api.frankerfacez.com/v1"
+	DefaultSevenTVURL      = "https://7tv.io/v3"
+)
+
+const (
+	// defaultEmoteProviderTimeout is the request timeout of emote providers without one.
+	defaultEmoteProviderTimeout = 10 * time.Second
+
+	// betterTTVCDN is the base URL of BetterTTV emote images.
+	betterTTVCDN = "https://cdn.betterttv.net/emote/"
+)
+
+// EmoteProvider loads the emotes of an emote service, so they can be merged into an EmoteCatalog.
+type EmoteProvider interface {
+	// Name is the provider name, set as the Provider of its emotes.
+	Name() string
+
+	// GlobalEmotes returns the emotes usable in every channel.
+	GlobalEmotes(ctx context.Context) ([]*Emote, error)
+
+	// ChannelEmotes returns the emotes of the broadcaster's channel, channels without an account on the service have
+	// no emotes rather than an error.
+	ChannelEmotes(ctx context.Context, broadcasterID string) ([]*Emote, error)
+}
+
+// EmoteProviderOptions defines all options the third-party emote providers support.
+type EmoteProviderOptions struct {
+	// BaseURL (optional) is the base URL of the API, defaults to the public API of the provider.
+	BaseURL string
+
+	// RequestTimeout (optional) is the request timeout, defaults to 10 seconds.
+	RequestTimeout time.Duration
+
+	// Transport (optional) is the HTTP transport, defaults to http.DefaultTransport.
+	Transport http.RoundTripper
+}
+
+// providerAPI is the HTTP client of a third-party emote API
+type providerAPI struct {
+	baseURL string
+	client  *client.Client
+}
+
+func newProviderAPI(options *EmoteProviderOptions, defaultURL string) *providerAPI {
+	if options == nil {
+		options = &EmoteProviderOptions{}
+	}
+
+	api := &providerAPI{baseURL: defaultURL}
+	if options.BaseURL != "" {
+		api.baseURL = strings.TrimSuffix(options.BaseURL, "/")
+	}
+	timeout := defaultEmoteProviderTimeout
+	if options.RequestTimeout > 0 {
+		timeout = options.RequestTimeout
+	}
+	api.client = client.NewClient(&client.Options{RequestTimeout: timeout, Transport: options.Transport})
+	return api
+}
+
+// get fetches the JSON body of the path, the body is nil when the API returns 404.
+func get[Body any](ctx context.Context, api *providerAPI, path string) (*Body, error) {
+	resp := api.client.Request(&client.RequestConfig{
+		Method: http.MethodGet,
+		URL:    api.baseURL + path,
+		Headers: func(context.Context) (http.Header, error) {
+			return http.Header{}, nil
+		},
+	}).Do(ctx)
+
+	if resp.Response != nil && resp.StatusCode == http.StatusNotFound {
+		resp.Body.Close()
+		return nil, nil
+	}
+	return client.WithBody[Body](resp)
+}
+
+// absoluteURL adds the scheme to protocol-relative URLs, as returned by some providers.
+func absoluteURL(u string) string {
+	if strings.HasPrefix(u, "//") {
+		return "https:" + u
+	}
+	return u
+}
+
+// emoteFormats returns the formats of an emote.
+func emoteFormats(animated bool) []string {
+	if animated {
+		return []string{EmoteFormatAnimated}
+	}
+	return []string{EmoteFormatStatic}
+}
+
+// BetterTTVProvider loads emotes from BetterTTV
+type BetterTTVProvider struct {
+	api *providerAPI
+}
+
+var _ EmoteProvider = (*BetterTTVProvider)(nil)
+
+// NewBetterTTVProvider creates a new instance of BetterTTVProvider
+func NewBetterTTVProvider(options *EmoteProviderOptions) *BetterTTVProvider {
+	return &BetterTTVProvider{api: newProviderAPI(options, DefaultBetterTTVURL)}
+}
+
+type betterTTVEmote struct {
+	ID        string `json:"id"`
+	Code      string `json:"code"`
+	ImageType string `json:"imageType"`
+	Animated  bool   `json:"animated"`
+}
+
+type betterTTVUser struct {
+	ChannelEmotes []*betterTTVEmote `json:"channelEmotes"`
+	SharedEmotes  []*betterTTVEmote `json:"sharedEmotes"`
+}
+
+// Name returns EmoteProviderBetterTTV.
+func (p *BetterTTVProvider) Name() string {
+	return EmoteProviderBetterTTV
+}
+
+// GlobalEmotes returns the global BetterTTV emotes.
+func (p *BetterTTVProvider) GlobalEmotes(ctx context.Context) ([]*Emote, error) {
+	resp, err := get[[]*betterTTVEmote](ctx, p.api, "/cached/emotes/global")
+	if err != nil || resp == nil {
+		return nil, err
+	}
+	return p.convert(*resp, "", "globals"), nil
+}
+
+// ChannelEmotes returns the channel and shared BetterTTV emotes of the broadcaster.
+func (p *BetterTTVProvider) ChannelEmotes(ctx context.Context, broadcasterID string) ([]*Emote, error) {
+	resp, err := get[betterTTVUser](ctx, p.api, "/cached/users/twitch/"+url.PathEscape(broadcasterID))
+	if err != nil || resp == nil {
+		return nil, err
+	}
+	emotes := p.convert(resp.ChannelEmotes, broadcasterID, "channel")
+	return append(emotes, p.convert(resp.SharedEmotes, broadcasterID, "shared")...), nil
+}
+
+func (p *BetterTTVProvider) convert(in []*betterTTVEmote, ownerID, emoteType string) []*Emote {
+	emotes := make([]*Emote, 0, len(in))
+	for _, e := range in {
+		if e == nil || e.ID == "" || e.Code == "" {
+			continue
+		}
+		emotes = append(emotes, &Emote{
+			ID:       e.ID,
+			Code:     e.Code,
+			Provider: EmoteProviderBetterTTV,
+			Type:     emoteType,
+			OwnerID:  ownerID,
+			Formats:  emoteFormats(e.Animated || e.ImageType == "gif"),
+			Scales:   []string{EmoteScale1, EmoteScale2, EmoteScale3},
+			Images: map[string]string{
+				EmoteScale1: betterTTVCDN + e.ID + "/1x",
+				EmoteScale2: betterTTVCDN + e.ID + "/2x",
+				EmoteScale3: betterTTVCDN + e.ID + "/3x",
+			},
+		})
+	}
+	return emotes
+}
+
+// FrankerFaceZProvider loads emotes from FrankerFaceZ
+type FrankerFaceZProvider struct {
+	api *providerAPI
+}
+
+var _ EmoteProvider = (*FrankerFaceZProvider)(nil)
+
+// NewFrankerFaceZProvider creates a new instance of FrankerFaceZProvider
+func NewFrankerFaceZProvider(options *EmoteProviderOptions) *FrankerFaceZProvider {
+	return &FrankerFaceZProvider{api: newProviderAPI(options, DefaultFrankerFaceZURL)}
+}
+
+type frankerFaceZEmote struct {
+	ID       int               `json:"id"`
+	Name     string            `json:"name"`
+	URLs     map[string]string `json:"urls"`
+	Animated map[string]string `json:"animated"`
+}
+
+type frankerFaceZSet struct {
+	ID        int                  `json:"id"`
+	Emoticons []*frankerFaceZEmote `json:"emoticons"`
+}
+
+type frankerFaceZGlobal struct {
+	DefaultSets []int                       `json:"default_sets"`
+	Sets        map[string]*frankerFaceZSet `json:"sets"`
+}
+
+type frankerFaceZRoom struct {
+	Room struct {
+		Set int `json:"set"`
+	} `json:"room"`
+	Sets map[string]*frankerFaceZSet `json:"sets"`
+}
+
+// frankerFaceZScales maps FrankerFaceZ image scales to Twitch emote scales
+var frankerFaceZScales = map[string]string{"1": EmoteScale1, "2": EmoteScale2, "4": EmoteScale3}
+
+// Name returns EmoteProviderFrankerFaceZ.
+func (p *FrankerFaceZProvider) Name() string {
+	return EmoteProviderFrankerFaceZ
+}
+
+// GlobalEmotes returns the emotes of the FrankerFaceZ default sets, which are available to every user.
+func (p *FrankerFaceZProvider) GlobalEmotes(ctx context.Context) ([]*Emote, error) {
+	resp, err := get[frankerFaceZGlobal](ctx, p.api, "/set/global")
+	if err != nil || resp == nil {
+		return nil, err
+	}
+
+	var emotes []*Emote
+	for _, id := range resp.DefaultSets {
+		emotes = append(emotes, p.convert(resp.Sets[strconv.Itoa(id)], "", "globals")...)
+	}
+	return emotes, nil
+}
+
+// ChannelEmotes returns the emotes of the broadcaster's FrankerFaceZ room.
+func (p *FrankerFaceZProvider) ChannelEmotes(ctx context.Context, broadcasterID string) ([]*Emote, error) {
+	resp, err := get[frankerFaceZRoom](ctx, p.api, "/room/id/"+url.PathEscape(broadcasterID))
+	if err != nil || resp == nil {
+		return nil, err
+	}
+	return p.convert(resp.Sets[strconv.Itoa(resp.Room.Set)], broadcasterID, "channel"), nil
+}
+
+func (p *FrankerFaceZProvider) convert(set *frankerFaceZSet, ownerID, emoteType string) []*Emote {
+	if set == nil {
+		return nil
+	}
+
+	emotes := make([]*Emote, 0, len(set.Emoticons))
+	for _, e := range set.Emoticons {
+		if e == nil || e.Name == "" {
+			continue
+		}
+
+		emote := &Emote{
+			ID:       strconv.Itoa(e.ID),
+			Code:     e.Name,
+			Provider: EmoteProviderFrankerFaceZ,
+			Type:     emoteType,
+			SetID:    strconv.Itoa(set.ID),
+			OwnerID:  ownerID,
+			Formats:  emoteFormats(len(e.Animated) > 0),
+			Images:   map[string]string{},
+		}
+		urls := e.URLs
+		if len(e.Animated) > 0 {
+			urls = e.Animated
+		}
+		for _, size := range []string{"1", "2", "4"} {
+			if u := urls[size]; u != "" {
+				emote.Scales = append(emote.Scales, frankerFaceZScales[size])
+				emote.Images[frankerFaceZScales[size]] = absoluteURL(u)
+			}
+		}
+		emotes = append(emotes, emote)
+	}
+	return emotes
+}
+
+// SevenTVProvider loads emotes from 7TV
+type SevenTVProvider struct {
+	api *providerAPI
+}
+
+var _ EmoteProvider = (*SevenTVProvider)(nil)
+
+// NewSevenTVProvider creates a new instance of SevenTVProvider
+func NewSevenTVProvider(options *EmoteProviderOptions) *SevenTVProvider {
+	return &SevenTVProvider{api: newProviderAPI(options, DefaultSevenTVURL)}
+}
+
+type sevenTVEmote struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Data struct {
+		Animated bool `json:"animated"`
+		Host     struct {
+			URL string `json:"url"`
+		} `json:"host"`
+	} `json:"data"`
+}
+
+type sevenTVEmoteSet struct {
+	ID     string          `json:"id"`
+	Emotes []*sevenTVEmote `json:"emotes"`
+}
+
+type sevenTVUser struct {
+	EmoteSet *sevenTVEmoteSet `json:"emote_set"`
+}
+
+// Name returns EmoteProviderSevenTV.
+func (p *SevenTVProvider) Name() string {
+	return EmoteProviderSevenTV
+}
+
+// GlobalEmotes returns the emotes of the global 7TV emote set.
+func (p *SevenTVProvider) GlobalEmotes(ctx context.Context) ([]*Emote, error) {
+	resp, err := get[sevenTVEmoteSet](ctx, p.api, "/emote-sets/global")
+	if err != nil || resp == nil {
+		return nil, err
+	}
+	return p.convert(resp, "", "globals"), nil
+}
+
+// ChannelEmotes returns the emotes of the active 7TV emote set of the broadcaster.
+func (p *SevenTVProvider) ChannelEmotes(ctx context.Context, broadcasterID string) ([]*Emote, error) {
+	resp, err := get[sevenTVUser](ctx, p.api, "/users/twitch/"+url.PathEscape(broadcasterID))
+	if err != nil || resp == nil {
+		return nil, err
+	}
+	return p.convert(resp.EmoteSet, broadcasterID, "channel"), nil
+}
+
+func (p *SevenTVProvider) convert(set *sevenTVEmoteSet, ownerID, emoteType string) []*Emote {
+	if set == nil {
+		return nil
+	}
+
+	emotes := make([]*Emote, 0, len(set.Emotes))
+	for _, e := range set.Emotes {
+		if e == nil || e.ID == "" || e.Name == "" {
+			continue
+		}
+
+		host := absoluteURL(e.Data.Host.URL)
+		if host == "" {
+			host = "https://cdn.7tv.app/emote/" + e.ID
+		}
+		emotes = append(emotes, &Emote{
+			ID:       e.ID,
+			Code:     e.Name,
+			Provider: EmoteProviderSevenTV,
+			Type:     emoteType,
+			SetID:    set.ID,
+			OwnerID:  ownerID,
+			Formats:  emoteFormats(e.Data.Animated),
+			Scales:   []string{EmoteScale1, EmoteScale2, EmoteScale3},
+			Images: map[string]string{
+				EmoteScale1: host + "/1x.webp",
+				EmoteScale2: host + "/2x.webp",
+				EmoteScale3: host + "/4x.webp",
+			},
+		})
+	}
+	return emotes
+}
@@ -0,0 +1,179 @@
+package chat
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/aidenwallis/go-twitch-client/internal/testutils/assert"
+)
+
+// providerAPIs stands in for the BetterTTV, FrankerFaceZ and 7TV APIs
+type providerAPIs struct {
+	mu   sync.Mutex
+	fail bool
+}
+
+func (a *providerAPIs) serve(t *testing.T) *httptest.Server {
+	responses := map[string]string{
+		"/bttv/cached/emotes/global": `[{"id":"b1","code":"FeelsBirthdayMan","imageType":"png"},{"id":"b2","code":"Kappa","imageType":"png"}]`,
+		"/bttv/cached/users/twitch/1": `{"channelEmotes":[{"id":"b3","code":"monkaS","imageType":"gif","animated":true}],
+			"sharedEmotes":[{"id":"b4","code":"catJAM","imageType":"gif","animated":true}]}`,
+		"/ffz/set/global": `{"default_sets":[3],"sets":{
+			"3":{"id":3,"emoticons":[{"id":25,"name":"ZrehplaR","urls":{"1":"//cdn.frankerfacez.com/emote/25/1","4":"https://cdn.frankerfacez.com/emote/25/4"}}]},
+			"4":{"id":4,"emoticons":[{"id":26,"name":"NotDefault","urls":{"1":"https://cdn.frankerfacez.com/emote/26/1"}}]}}}`,
+		"/ffz/room/id/1":         `{"room":{"set":100},"sets":{"100":{"id":100,"emoticons":[{"id":7,"name":"monkaS","urls":{"1":"https://ffz/7/1"}},{"id":8,"name":"OMEGALUL","urls":{"1":"https://ffz/8/1"}}]}}}`,
+		"/7tv/emote-sets/global": `{"id":"g","emotes":[{"id":"s1","name":"EZ","data":{"host":{"url":"//cdn.7tv.app/emote/s1"}}}]}`,
+		"/7tv/users/twitch/1":    `{"emote_set":{"id":"set1","emotes":[{"id":"s2","name":"OMEGALUL","data":{"animated":true,"host":{"url":"//cdn.7tv.app/emote/s2"}}}]}}`,
+	}
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		a.mu.Lock()
+		fail := a.fail
+		a.mu.Unlock()
+
+		if fail {
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write([]byte(`{"message":"internal error"}`))
+			return
+		}
+
+		body, ok := responses[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message":"not found"}`))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (a *providerAPIs) setFail(fail bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.fail = fail
+}
+
+func TestEmoteProviders(t *testing.T) {
+	ctx := context.Background()
+	s := (&providerAPIs{}).serve(t)
+
+	bttv := NewBetterTTVProvider(&EmoteProviderOptions{BaseURL: s.URL + "/bttv/"})
+	emotes, err := bttv.GlobalEmotes(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(emotes))
+	assert.Equal(t, EmoteProviderBetterTTV, emotes[0].Provider)
+	assert.Equal(t, "https://cdn.betterttv.net/emote/b1/3x", emotes[0].URL(EmoteFormatDefault, EmoteThemeDark, EmoteScale3))
+
+	emotes, err = bttv.ChannelEmotes(ctx, "1")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(emotes))
+	assert.Equal(t, "shared", emotes[1].Type)
+	assert.Equal(t, "1", emotes[1].OwnerID)
+	assert.Equal(t, EmoteFormatAnimated, emotes[1].Formats[0])
+
+	// channels without an account have no emotes
+	emotes, err = bttv.ChannelEmotes(ctx, "2")
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(emotes))
+
+	ffz := NewFrankerFaceZProvider(&EmoteProviderOptions{BaseURL: s.URL + "/ffz"})
+	emotes, err = ffz.GlobalEmotes(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(emotes))
+	assert.Equal(t, "25", emotes[0].ID)
+	assert.Equal(t, "3", emotes[0].SetID)
+	assert.Equal(t, "https://cdn.frankerfacez.com/emote/25/1", emotes[0].URL(EmoteFormatDefault, EmoteThemeDark, EmoteScale1))
+	assert.Equal(t, "https://cdn.frankerfacez.com/emote/25/4", emotes[0].URL(EmoteFormatDefault, EmoteThemeDark, EmoteScale3))
+
+	// missing scales fall back to the smallest image
+	assert.Equal(t, "https://cdn.frankerfacez.com/emote/25/1", emotes[0].URL(EmoteFormatDefault, EmoteThemeDark, EmoteScale2))
+
+	emotes, err = ffz.ChannelEmotes(ctx, "1")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(emotes))
+
+	seventv := NewSevenTVProvider(&EmoteProviderOptions{BaseURL: s.URL + "/7tv"})
+	emotes, err = seventv.GlobalEmotes(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "https://cdn.7tv.app/emote/s1/1x.webp", emotes[0].URL(EmoteFormatDefault, EmoteThemeDark, EmoteScale1))
+
+	emotes, err = seventv.ChannelEmotes(ctx, "1")
+	assert.NoError(t, err)
+	assert.Equal(t, "set1", emotes[0].SetID)
+	assert.Equal(t, EmoteProviderSevenTV, emotes[0].Provider)
+}
+
+func TestEmoteCatalogProviders(t *testing.T) {
+	ctx := context.Background()
+	api := &providerAPIs{}
+	s := api.serve(t)
+
+	var setRequests []string
+	c := NewEmoteCatalog(&EmoteCatalogOptions{
+		Helix: emoteHelix(t, &setRequests),
+		Providers: []EmoteProvider{
+			NewSevenTVProvider(&EmoteProviderOptions{BaseURL: s.URL + "/7tv"}),
+			NewBetterTTVProvider(&EmoteProviderOptions{BaseURL: s.URL + "/bttv"}),
+			NewFrankerFaceZProvider(&EmoteProviderOptions{BaseURL: s.URL + "/ffz"}),
+		},
+	})
+	assert.NoError(t, c.LoadGlobal(ctx))
+	assert.NoError(t, c.LoadChannel(ctx, "1"))
+
+	// Twitch emotes take precedence over third-party ones
+	e, _ := c.Lookup("2", "Kappa")
+	assert.Equal(t, EmoteProviderTwitch, e.Provider)
+
+	// earlier providers take precedence over later ones
+	e, _ = c.Lookup("1", "OMEGALUL")
+	assert.Equal(t, EmoteProviderSevenTV, e.Provider)
+	e, _ = c.Lookup("1", "monkaS")
+	assert.Equal(t, EmoteProviderBetterTTV, e.Provider)
+
+	// channel emotes are scoped to their channel
+	_, ok := c.Lookup("2", "monkaS")
+	assert.Equal(t, false, ok)
+	e, _ = c.Lookup("2", "ZrehplaR")
+	assert.Equal(t, EmoteProviderFrankerFaceZ, e.Provider)
+
+	// third-party IDs are prefixed, as they collide with Twitch IDs
+	e, _ = c.ByID("25")
+	assert.Equal(t, "Kappa", e.Code)
+	e, _ = c.ByID("ffz:25")
+	assert.Equal(t, "ZrehplaR", e.Code)
+
+	text := "Kappa ZrehplaR catJAM"
+	emotes := c.Tokenize("1", text)
+	assert.Equal(t, "ffz:25", emotes[1].ID)
+	fragments := Fragments(text, emotes, &FragmentOptions{Emotes: c, Scale: EmoteScale3})
+	assert.Equal(t, "emote:Kappa|text: |emote:ZrehplaR|text: |emote:catJAM", fragmentTexts(fragments))
+	assert.Equal(t, "https://static-cdn.jtvnw.net/emoticons/v2/c2/default/dark/3.0", fragments[0].Emote.URL)
+	assert.Equal(t, "https://cdn.frankerfacez.com/emote/25/4", fragments[2].Emote.URL)
+	assert.Equal(t, "https://cdn.betterttv.net/emote/b4/3x", fragments[4].Emote.URL)
+
+	// failing providers keep their previous emotes
+	api.setFail(true)
+	err := c.LoadChannel(ctx, "1")
+	assert.Equal(t, true, err != nil)
+	e, _ = c.Lookup("1", "catJAM")
+	assert.Equal(t, EmoteProviderBetterTTV, e.Provider)
+}
+
+func TestEmoteCatalogProvidersWithoutHelix(t *testing.T) {
+	s := (&providerAPIs{}).serve(t)
+	c := NewEmoteCatalog(&EmoteCatalogOptions{
+		Providers: []EmoteProvider{NewBetterTTVProvider(&EmoteProviderOptions{BaseURL: s.URL + "/bttv"})},
+	})
+	assert.NoError(t, c.LoadGlobal(context.Background()))
+
+	e, ok := c.Lookup("", "Kappa")
+	assert.Equal(t, true, ok)
+	assert.Equal(t, EmoteProviderBetterTTV, e.Provider)
+	assert.ErrorIs(t, c.LoadEmoteSets(context.Background(), "1"), ErrMissingHelix)
+}
@@ -0,0 +1,244 @@
+package chat
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/aidenwallis/go-twitch-client/helix"
+	"github.com/aidenwallis/go-twitch-client/irc"
+)
+
+// RateLimits are the chat limits of an account, a zero limit is unlimited
+//
+// See: https://dev.twitch.tv/docs/irc/#rate-limits
+type RateLimits struct {
+	// Messages is how many messages may be sent per Period to channels where the account is not a moderator.
+	Messages int
+
+	// ModeratorMessages is how many messages may be sent per Period in total, including the messages sent to
+	// channels where the account is a moderator or the broadcaster.
+	ModeratorMessages int
+
+	// Period is the window Messages and ModeratorMessages apply to.
+	Period time.Duration
+
+	// Joins is how many channels may be joined per JoinPeriod.
+	Joins int
+
+	// JoinPeriod is the window Joins applies to.
+	JoinPeriod time.Duration
+}
+
+// DefaultRateLimits are the limits of regular accounts.
+var DefaultRateLimits = RateLimits{
+	Messages:          20,
+	ModeratorMessages: 100,
+	Period:            30 * time.Second,
+	Joins:             20,
+	JoinPeriod:        10 * time.Second,
+}
+
+// VerifiedBotRateLimits are the limits of verified bot accounts.
+var VerifiedBotRateLimits = RateLimits{
+	Messages:          20,
+	ModeratorMessages: 100,
+	Period:            30 * time.Second,
+	Joins:             2000,
+	JoinPeriod:        10 * time.Second,
+}
+
+// RateLimiter enforces the chat limits of a single account, clients sending as the same account should share one.
+//
+// Messages are queued rather than dropped, Wait blocks until the message may be sent. Every message counts towards
+// the ModeratorMessages bucket, and messages to channels where the account is not a moderator also count towards the
+// Messages bucket and the channel's slow mode.
+type RateLimiter struct {
+	mu sync.Mutex
+
+	messages          *bucket
+	moderatorMessages *bucket
+	joins             *bucket
+
+	moderator map[string]bool
+	slowMode  map[string]time.Duration
+	next      map[string]time.Time
+
+	now func() time.Time
+}
+
+// NewRateLimiter creates a new instance of RateLimiter, limits defaults to DefaultRateLimits.
+func NewRateLimiter(limits *RateLimits) *RateLimiter {
+	if limits == nil {
+		limits = &DefaultRateLimits
+	}
+
+	return &RateLimiter{
+		messages:          newBucket(limits.Messages, limits.Period),
+		moderatorMessages: newBucket(limits.ModeratorMessages, limits.Period),
+		joins:             newBucket(limits.Joins, limits.JoinPeriod),
+		moderator:         map[string]bool{},
+		slowMode:          map[string]time.Duration{},
+		next:              map[string]time.Time{},
+		now:               time.Now,
+	}
+}
+
+// Wait blocks until a message may be sent to the channel. Waiting callers are served in order.
+//
+// The message counts towards the limits as soon as Wait is called, even when ctx is cancelled before it returns.
+func (l *RateLimiter) Wait(ctx context.Context, channel string) error {
+	return sleep(ctx, l.reserve(l.now(), normalizeChannel(channel)))
+}
+
+// WaitJoins blocks until n channels may be joined.
+func (l *RateLimiter) WaitJoins(ctx context.Context, n int) error {
+	l.mu.Lock()
+	delay := l.joins.reserve(l.now(), n)
+	l.mu.Unlock()
+
+	return sleep(ctx, delay)
+}
+
+// SetModerator sets whether the account is a moderator or the broadcaster of the channel.
+func (l *RateLimiter) SetModerator(channel string, moderator bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.moderator[normalizeChannel(channel)] = moderator
+}
+
+// IsModerator returns whether the account is known to be a moderator or the broadcaster of the channel.
+func (l *RateLimiter) IsModerator(channel string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.moderator[normalizeChannel(channel)]
+}
+
+// SetSlowMode sets how long non-moderators must wait between messages in the channel, 0 disables slow mode.
+func (l *RateLimiter) SetSlowMode(channel string, wait time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	channel = normalizeChannel(channel)
+	if wait <= 0 {
+		delete(l.slowMode, channel)
+		return
+	}
+	l.slowMode[channel] = wait
+}
+
+// SetChatSettings sets the slow mode of the channel from its settings, as returned by helix.Client.GetChatSettings.
+func (l *RateLimiter) SetChatSettings(channel string, settings *helix.ChatSettings) {
+	var wait time.Duration
+	if settings.SlowMode && settings.SlowModeWaitTime != nil {
+		wait = time.Duration(*settings.SlowModeWaitTime) * time.Second
+	}
+	l.SetSlowMode(channel, wait)
+}
+
+// Observe updates the moderator status from USERSTATE messages, and the slow mode from ROOMSTATE messages. Other
+// messages are ignored.
+func (l *RateLimiter) Observe(msg *irc.Message) {
+	switch msg.Command {
+	case irc.CommandUserState:
+		state, err := msg.UserState()
+		if err != nil {
+			return
+		}
+		l.SetModerator(state.Channel, state.User.Mod || state.User.IsBroadcaster() || state.User.HasBadge("moderator"))
+
+	case irc.CommandRoomState:
+		state, err := msg.RoomState()
+		if err != nil || state.Slow == nil {
+			return
+		}
+		l.SetSlowMode(state.Channel, time.Duration(*state.Slow)*time.Second)
+	}
+}
+
+// reserve takes a message from the buckets that apply to the channel, returning how long the caller must wait before
+// sending it.
+func (l *RateLimiter) reserve(now time.Time, channel string) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	delay := l.moderatorMessages.reserve(now, 1)
+	if l.moderator[channel] {
+		return delay
+	}
+
+	if d := l.messages.reserve(now, 1); d > delay {
+		delay = d
+	}
+
+	if slow, ok := l.slowMode[channel]; ok {
+		if d := l.next[channel].Sub(now); d > delay {
+			delay = d
+		}
+		l.next[channel] = now.Add(delay + slow)
+	}
+	return delay
+}
+
+// bucket is a token bucket, tokens go negative when reserved ahead of time so waiting callers are served in order
+type bucket struct {
+	capacity float64
+	rate     float64 // tokens per second
+	tokens   float64
+	last     time.Time
+}
+
+// newBucket returns a bucket of capacity tokens refilled every period, or nil when unlimited.
+func newBucket(capacity int, period time.Duration) *bucket {
+	if capacity <= 0 || period <= 0 {
+		return nil
+	}
+	return &bucket{
+		capacity: float64(capacity),
+		rate:     float64(capacity) / period.Seconds(),
+		tokens:   float64(capacity),
+	}
+}
+
+// reserve takes n tokens, returning how long until they are available.
+func (b *bucket) reserve(now time.Time, n int) time.Duration {
+	if b == nil {
+		return 0
+	}
+
+	if !b.last.IsZero() && now.After(b.last) {
+		b.tokens += now.Sub(b.last).Seconds() * b.rate
+		if b.tokens > b.capacity {
+			b.tokens = b.capacity
+		}
+	}
+	if b.last.IsZero() || now.After(b.last) {
+		b.last = now
+	}
+
+	b.tokens -= float64(n)
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(math.Round(-b.tokens / b.rate * float64(time.Second)))
+}
+
+// sleep waits for d, or until ctx is cancelled.
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
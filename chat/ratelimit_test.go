@@ -0,0 +1,109 @@
+package chat
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aidenwallis/go-twitch-client/helix"
+	"github.com/aidenwallis/go-twitch-client/internal/testutils/assert"
+	"github.com/aidenwallis/go-twitch-client/irc"
+)
+
+func TestRateLimiterMessages(t *testing.T) {
+	l := NewRateLimiter(nil)
+	now := time.Unix(0, 0)
+
+	for i := 0; i < 20; i++ {
+		assert.Equal(t, time.Duration(0), l.reserve(now, "forsen"))
+	}
+	// the 21st message waits for a token, refilled at 20 per 30 seconds, and later messages queue behind it
+	assert.Equal(t, 1500*time.Millisecond, l.reserve(now, "forsen"))
+	assert.Equal(t, 3*time.Second, l.reserve(now, "nymn"))
+
+	// tokens refill over time
+	assert.Equal(t, 1500*time.Millisecond, l.reserve(now.Add(3*time.Second), "forsen"))
+	assert.Equal(t, time.Duration(0), l.reserve(now.Add(time.Minute), "forsen"))
+}
+
+func TestRateLimiterModerator(t *testing.T) {
+	l := NewRateLimiter(nil)
+	now := time.Unix(0, 0)
+	l.SetModerator("#Forsen", true)
+	assert.Equal(t, true, l.IsModerator("forsen"))
+
+	for i := 0; i < 100; i++ {
+		assert.Equal(t, time.Duration(0), l.reserve(now, "forsen"))
+	}
+	assert.Equal(t, 300*time.Millisecond, l.reserve(now, "forsen"))
+
+	// messages to moderated channels count towards the non-moderator limit too
+	assert.Equal(t, 600*time.Millisecond, l.reserve(now, "nymn"))
+}
+
+func TestRateLimiterSlowMode(t *testing.T) {
+	l := NewRateLimiter(nil)
+	now := time.Unix(0, 0)
+
+	l.SetChatSettings("forsen", &helix.ChatSettings{SlowMode: true, SlowModeWaitTime: intPtr(10)})
+	assert.Equal(t, time.Duration(0), l.reserve(now, "forsen"))
+	assert.Equal(t, 10*time.Second, l.reserve(now, "forsen"))
+	assert.Equal(t, 18*time.Second, l.reserve(now.Add(2*time.Second), "forsen"))
+	assert.Equal(t, time.Duration(0), l.reserve(now, "nymn"))
+
+	// moderators are exempt from slow mode
+	l.SetModerator("forsen", true)
+	assert.Equal(t, time.Duration(0), l.reserve(now, "forsen"))
+
+	l.SetModerator("forsen", false)
+	l.SetChatSettings("forsen", &helix.ChatSettings{})
+	assert.Equal(t, time.Duration(0), l.reserve(now.Add(3*time.Second), "forsen"))
+}
+
+func TestRateLimiterObserve(t *testing.T) {
+	l := NewRateLimiter(nil)
+	now := time.Unix(0, 0)
+
+	observe := func(line string) {
+		msg, err := irc.Parse(line)
+		assert.NoError(t, err)
+		l.Observe(msg)
+	}
+
+	observe("@badges=moderator/1;mod=1 :tmi.twitch.tv USERSTATE #forsen")
+	assert.Equal(t, true, l.IsModerator("forsen"))
+	observe("@badges=broadcaster/1;mod=0 :tmi.twitch.tv USERSTATE #bot")
+	assert.Equal(t, true, l.IsModerator("bot"))
+	observe("@badges=;mod=0 :tmi.twitch.tv USERSTATE #forsen")
+	assert.Equal(t, false, l.IsModerator("forsen"))
+
+	observe("@room-id=1;slow=5 :tmi.twitch.tv ROOMSTATE #forsen")
+	assert.Equal(t, time.Duration(0), l.reserve(now, "forsen"))
+	assert.Equal(t, 5*time.Second, l.reserve(now, "forsen"))
+
+	// ROOMSTATE deltas without slow keep the slow mode
+	observe("@room-id=1;subs-only=1 :tmi.twitch.tv ROOMSTATE #forsen")
+	assert.Equal(t, 10*time.Second, l.reserve(now, "forsen"))
+}
+
+func TestRateLimiterWait(t *testing.T) {
+	l := NewRateLimiter(&RateLimits{Messages: 1, Period: time.Hour, Joins: 1, JoinPeriod: time.Hour})
+	ctx, cancel := context.WithCancel(context.Background())
+
+	assert.NoError(t, l.Wait(ctx, "forsen"))
+	assert.NoError(t, l.WaitJoins(ctx, 1))
+
+	cancel()
+	assert.ErrorIs(t, l.Wait(ctx, "forsen"), context.Canceled)
+	assert.ErrorIs(t, l.WaitJoins(ctx, 1), context.Canceled)
+
+	// zero limits are unlimited
+	l = NewRateLimiter(&RateLimits{})
+	for i := 0; i < 1000; i++ {
+		assert.NoError(t, l.Wait(context.Background(), "forsen"))
+	}
+}
+
+func intPtr(v int) *int {
+	return &v
+}
@@ -0,0 +1,466 @@
+package chat
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	htmltemplate "html/template"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/aidenwallis/go-twitch-client/helix"
+	"github.com/aidenwallis/go-twitch-client/irc"
+)
+
+// RenderFormat is the output format of a Renderer
+type RenderFormat string
+
+// Render formats.
+const (
+	RenderFormatHTML     RenderFormat = "html"
+	RenderFormatMarkdown RenderFormat = "markdown"
+	RenderFormatANSI     RenderFormat = "ansi"
+)
+
+// ErrUnknownRenderFormat is returned when a renderer is created with an unsupported format
+var ErrUnknownRenderFormat = errors.New("chat: unknown render format")
+
+const (
+	// defaultColorRefreshInterval is how long chat colors loaded from Helix are cached.
+	defaultColorRefreshInterval = time.Hour
+
+	// colorRetryInterval is how long failed chat color loads are cached, at most the refresh interval.
+	colorRetryInterval = time.Minute
+
+	// colorLoadTimeout is how long a background chat color load may take.
+	colorLoadTimeout = 30 * time.Second
+
+	// maxColorBatchSize is the most users GetUserChatColors accepts per request.
+	maxColorBatchSize = 100
+)
+
+// DefaultHTMLTemplate is the default template of RenderFormatHTML, it is executed with html/template so every value is
+// escaped for its context.
+const DefaultHTMLTemplate = `<div class="chat-message{{if .Action}} chat-action{{end}}">` +
+	`{{range .Badges}}<img class="chat-badge" src="{{.ImageURL1x}}" alt="{{.Title}}" title="{{.Title}}">{{end}}` +
+	`<span class="chat-name" style="color: {{.Color}}">{{.Name}}</span>{{if not .Action}}:{{end}} ` +
+	`<span class="chat-text"{{if .Action}} style="color: {{.Color}}"{{end}}>` +
+	`{{range .Fragments}}` +
+	`{{if .Emote}}<img class="chat-emote" src="{{.Emote.URL}}" alt="{{.Text}}" title="{{.Text}}">` +
+	`{{else if .Mention}}<span class="chat-mention">{{.Text}}</span>` +
+	`{{else if .Cheermote}}<span class="chat-cheermote">{{.Text}}</span>` +
+	`{{else}}{{.Text}}{{end}}` +
+	`{{end}}</span></div>`
+
+// DefaultMarkdownTemplate is the default template of RenderFormatMarkdown.
+const DefaultMarkdownTemplate = `{{range .Badges}}![{{escape .Title}}]({{url .ImageURL1x}}) {{end}}` +
+	`**{{escape .Name}}**{{if not .Action}}:{{end}} ` +
+	`{{range .Fragments}}{{if .Emote}}![{{escape .Text}}]({{url .Emote.URL}}){{else}}{{escape .Text}}{{end}}{{end}}`
+
+// DefaultANSITemplate is the default template of RenderFormatANSI, emotes are drawn as bold text.
+const DefaultANSITemplate = `{{range .Badges}}[{{escape .Title}}]{{end}}{{if .Badges}} {{end}}` +
+	`{{color .Color (bold (escape .Name))}}{{if not .Action}}:{{end}} ` +
+	`{{range .Fragments}}{{if .Emote}}{{bold (escape .Text)}}` +
+	`{{else if $.Action}}{{color $.Color (escape .Text)}}` +
+	`{{else}}{{escape .Text}}{{end}}{{end}}`
+
+// defaultNameColors are the colors given to users without a chat color, as Twitch does.
+var defaultNameColors = []string{
+	"#FF0000", "#0000FF", "#008000", "#B22222", "#FF7F50", "#9ACD32", "#FF4500", "#2E8B57",
+	"#DAA520", "#D2691E", "#5F9EA0", "#1E90FF", "#FF69B4", "#8A2BE2", "#00FF7F",
+}
+
+// RenderData is the data templates are executed with
+type RenderData struct {
+	// Message is the rendered message.
+	Message *irc.PrivateMessage
+
+	// Name is the display name of the sender, falling back to their login.
+	Name string
+
+	// Color is the hex name color of the sender, such as #FF0000.
+	Color string
+
+	// Badges are the resolved badges of the sender, empty when the renderer has no badge resolver.
+	Badges []*ResolvedBadge
+
+	// Fragments are the parts of the message text.
+	Fragments []Fragment
+
+	// Action is whether the message was sent with /me.
+	Action bool
+}
+
+// RendererOptions defines all options the renderer supports.
+type RendererOptions struct {
+	// Format is the output format.
+	Format RenderFormat
+
+	// Template (optional) is the template messages are rendered with, defaults to the default template of the format.
+	// Templates are executed with RenderData.
+	//
+	// HTML templates are executed with html/template, which escapes every value for its context. Markdown templates
+	// have an escape function for text and a url function for link targets. ANSI templates have an escape function
+	// that strips control characters, a color function taking a hex color and text, and a bold function.
+	Template string
+
+	// Badges (optional) resolves the badges of the sender.
+	Badges *BadgeResolver
+
+	// Emotes (optional) adds the third-party emotes of the catalog, which are missing from the emotes tag.
+	Emotes *EmoteCatalog
+
+	// Helix (optional) is used to load the chat color of users whose messages have no color tag, in the background.
+	Helix helix.Client
+
+	// ColorRefreshInterval (optional) is how long chat colors loaded from Helix are cached, defaults to 1 hour.
+	ColorRefreshInterval time.Duration
+
+	// DefaultColor (optional) is the name color of users without a chat color, defaults to a color picked from the
+	// user's login, as Twitch does.
+	DefaultColor string
+
+	// FragmentOptions (optional) are the options messages are split into fragments with.
+	FragmentOptions *FragmentOptions
+}
+
+// executor is the common interface of text and html templates
+type executor interface {
+	Execute(w io.Writer, data interface{}) error
+}
+
+// Renderer renders chat messages to sanitized HTML, Markdown or ANSI colored text.
+type Renderer struct {
+	template             executor
+	badges               *BadgeResolver
+	emotes               *EmoteCatalog
+	helix                helix.Client
+	colorRefreshInterval time.Duration
+	colorRetryInterval   time.Duration
+	defaultColor         string
+	fragmentOptions      FragmentOptions
+	now                  func() time.Time
+
+	mu      sync.Mutex
+	colors  map[string]*colorEntry
+	pending []string // user IDs waiting for a background load
+	batcher bool     // whether a background load is running
+
+	// wg tracks background loads
+	wg sync.WaitGroup
+
+	onError func(err error)
+}
+
+// colorEntry is the cached chat color of a user
+type colorEntry struct {
+	color    string
+	loadedAt time.Time
+	failedAt time.Time // zero unless the last load failed
+	loading  bool
+}
+
+// NewRenderer creates a new instance of Renderer, an error is returned when the template fails to parse.
+func NewRenderer(options *RendererOptions) (*Renderer, error) {
+	if options == nil {
+		options = &RendererOptions{}
+	}
+
+	r := &Renderer{
+		badges:               options.Badges,
+		emotes:               options.Emotes,
+		helix:                options.Helix,
+		colorRefreshInterval: defaultColorRefreshInterval,
+		now:                  time.Now,
+		colors:               map[string]*colorEntry{},
+	}
+	if options.ColorRefreshInterval > 0 {
+		r.colorRefreshInterval = options.ColorRefreshInterval
+	}
+	r.colorRetryInterval = colorRetryInterval
+	if r.colorRefreshInterval < r.colorRetryInterval {
+		r.colorRetryInterval = r.colorRefreshInterval
+	}
+	if isHexColor(options.DefaultColor) {
+		r.defaultColor = options.DefaultColor
+	}
+	if options.FragmentOptions != nil {
+		r.fragmentOptions = *options.FragmentOptions
+	}
+	if r.fragmentOptions.Emotes == nil {
+		r.fragmentOptions.Emotes = options.Emotes
+	}
+
+	var err error
+	switch options.Format {
+	case RenderFormatHTML:
+		r.template, err = htmltemplate.New("message").Parse(templateOrDefault(options.Template, DefaultHTMLTemplate))
+	case RenderFormatMarkdown:
+		r.template, err = template.New("message").Funcs(template.FuncMap{
+			"escape": escapeMarkdown,
+			"url":    escapeMarkdownURL,
+		}).Parse(templateOrDefault(options.Template, DefaultMarkdownTemplate))
+	case RenderFormatANSI:
+		r.template, err = template.New("message").Funcs(template.FuncMap{
+			"escape": stripControl,
+			"color":  ansiColor,
+			"bold":   ansiBold,
+		}).Parse(templateOrDefault(options.Template, DefaultANSITemplate))
+	default:
+		err = fmt.Errorf("%w: %q", ErrUnknownRenderFormat, options.Format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func templateOrDefault(t, defaultTemplate string) string {
+	if t == "" {
+		return defaultTemplate
+	}
+	return t
+}
+
+// OnError sets the handler for errors loading chat colors in the background, the messages are still rendered with the
+// cached or default color.
+//
+// Handlers must be registered before calling Render.
+func (r *Renderer) OnError(handler func(err error)) {
+	r.onError = handler
+}
+
+// Render renders the message. Render never blocks on Helix: when the message has no color tag, the chat color of the
+// user is loaded in the background, and the default color is used until it is loaded. Call LoadColors to warm the
+// cache up front.
+func (r *Renderer) Render(msg *irc.PrivateMessage) (string, error) {
+	var b strings.Builder
+	if err := r.template.Execute(&b, r.data(msg)); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+func (r *Renderer) data(msg *irc.PrivateMessage) *RenderData {
+	data := &RenderData{
+		Message: msg,
+		Name:    msg.User.DisplayName,
+		Color:   r.color(&msg.User),
+		Action:  msg.Action,
+	}
+	if data.Name == "" {
+		data.Name = msg.User.Login
+	}
+	if r.badges != nil {
+		data.Badges = r.badges.Resolve(msg.RoomID, msg.User.Badges, msg.User.BadgeInfo)
+	}
+
+	emotes := msg.Emotes
+	if r.emotes != nil {
+		emotes = append(emotes[:len(emotes):len(emotes)], r.thirdPartyEmotes(msg)...)
+	}
+	o := r.fragmentOptions
+	o.Cheermotes = o.Cheermotes || msg.Bits > 0
+	data.Fragments = Fragments(msg.Text, emotes, &o)
+	return data
+}
+
+// thirdPartyEmotes finds the third-party emotes in the message, Twitch emotes come from the emotes tag as the sender may
+// not be allowed to use every Twitch emote of the catalog.
+func (r *Renderer) thirdPartyEmotes(msg *irc.PrivateMessage) []irc.Emote {
+	var emotes []irc.Emote
+	for _, emote := range r.emotes.Tokenize(msg.RoomID, msg.Text) {
+		if e, ok := r.emotes.ByID(emote.ID); ok && e.Provider != EmoteProviderTwitch {
+			emotes = append(emotes, emote)
+		}
+	}
+	return emotes
+}
+
+// color returns the name color of the user, from the color tag, Helix, or the default colors.
+func (r *Renderer) color(user *irc.User) string {
+	if isHexColor(user.Color) {
+		return user.Color
+	}
+	if color := r.helixColor(user.ID); color != "" {
+		return color
+	}
+	if r.defaultColor != "" {
+		return r.defaultColor
+	}
+
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(user.Login))
+	return defaultNameColors[h.Sum32()%uint32(len(defaultNameColors))]
+}
+
+// LoadColors loads the chat colors of the users from Helix, blocking until they are cached.
+func (r *Renderer) LoadColors(ctx context.Context, userIDs ...string) error {
+	if r.helix == nil {
+		return ErrMissingHelix
+	}
+
+	for len(userIDs) > 0 {
+		batch := userIDs
+		if len(batch) > maxColorBatchSize {
+			batch = batch[:maxColorBatchSize]
+		}
+		userIDs = userIDs[len(batch):]
+
+		if err := r.loadColors(ctx, batch); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// helixColor returns the cached chat color of the user, empty when the user has none or it is not loaded yet. Colors
+// that are missing or older than the refresh interval are queued for a background load, unless the last load failed
+// within the retry interval.
+func (r *Renderer) helixColor(userID string) string {
+	if r.helix == nil || userID == "" {
+		return ""
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entry := r.colors[userID]
+	if entry == nil {
+		entry = &colorEntry{}
+		r.colors[userID] = entry
+	}
+
+	now := r.now()
+	stale := entry.loadedAt.IsZero() || now.Sub(entry.loadedAt) >= r.colorRefreshInterval
+	retry := entry.failedAt.IsZero() || now.Sub(entry.failedAt) >= r.colorRetryInterval
+	if stale && retry && !entry.loading {
+		entry.loading = true
+		r.pending = append(r.pending, userID)
+		if !r.batcher {
+			r.batcher = true
+			r.wg.Add(1)
+			go r.loadPending()
+		}
+	}
+	return entry.color
+}
+
+// loadPending loads the queued chat colors in batches, until the queue is empty.
+func (r *Renderer) loadPending() {
+	defer r.wg.Done()
+
+	for {
+		r.mu.Lock()
+		batch := r.pending
+		if len(batch) > maxColorBatchSize {
+			batch = batch[:maxColorBatchSize]
+		}
+		r.pending = r.pending[len(batch):]
+		if len(batch) == 0 {
+			r.pending = nil
+			r.batcher = false
+			r.mu.Unlock()
+			return
+		}
+		r.mu.Unlock()
+
+		ctx, cancel := context.WithTimeout(context.Background(), colorLoadTimeout)
+		err := r.loadColors(ctx, batch)
+		cancel()
+
+		if err != nil && r.onError != nil {
+			r.onError(err)
+		}
+	}
+}
+
+// loadColors fetches the chat colors of at most maxColorBatchSize users, and caches them. Failed loads keep the cached
+// colors, and are retried after the retry interval.
+func (r *Renderer) loadColors(ctx context.Context, userIDs []string) error {
+	resp, err := r.helix.GetUserChatColors(ctx, &helix.GetUserChatColorsRequest{UserIDs: userIDs})
+
+	colors := map[string]string{}
+	if err == nil {
+		for _, c := range resp.Data {
+			if isHexColor(c.Color) {
+				colors[c.UserID] = c.Color
+			}
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+	for _, userID := range userIDs {
+		entry := r.colors[userID]
+		if entry == nil {
+			entry = &colorEntry{}
+			r.colors[userID] = entry
+		}
+		entry.loading = false
+		if err != nil {
+			entry.failedAt = now
+			continue
+		}
+		entry.color = colors[userID]
+		entry.loadedAt = now
+		entry.failedAt = time.Time{}
+	}
+	return err
+}
+
+// isHexColor returns whether the color is in the #RRGGBB form.
+func isHexColor(color string) bool {
+	if len(color) != 7 || color[0] != '#' {
+		return false
+	}
+	_, err := strconv.ParseUint(color[1:], 16, 32)
+	return err == nil
+}
+
+// markdownEscaper escapes the characters that are formatting in Markdown, and mentions such as @everyone in Discord.
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "`", "\\`", `*`, `\*`, `_`, `\_`, `~`, `\~`, `|`, `\|`, `[`, `\[`, `]`, `\]`, `(`, `\(`, `)`, `\)`,
+	`<`, `\<`, `>`, `\>`, `#`, `\#`, `-`, `\-`, `+`, `\+`, `!`, `\!`, `@`, `\@`,
+)
+
+func escapeMarkdown(s string) string {
+	return markdownEscaper.Replace(stripControl(s))
+}
+
+// markdownURLEscaper encodes the characters that end a Markdown link target.
+var markdownURLEscaper = strings.NewReplacer(" ", "%20", "(", "%28", ")", "%29", "<", "%3C", ">", "%3E")
+
+func escapeMarkdownURL(s string) string {
+	return markdownURLEscaper.Replace(stripControl(s))
+}
+
+// stripControl removes control characters, such as newlines and terminal escape sequences.
+func stripControl(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r < 0x20 || (r >= 0x7f && r < 0xa0) {
+			return -1
+		}
+		return r
+	}, s)
+}
+
+// ansiColor colors the text with a 24-bit hex color, text is returned uncolored when the color is invalid.
+func ansiColor(color, text string) string {
+	if !isHexColor(color) {
+		return text
+	}
+	rgb, _ := strconv.ParseUint(color[1:], 16, 32)
+	return fmt.Sprintf("\x1b[38;2;%d;%d;%dm%s\x1b[39m", rgb>>16, (rgb>>8)&0xff, rgb&0xff, text)
+}
+
+func ansiBold(text string) string {
+	return "\x1b[1m" + text + "\x1b[22m"
+}
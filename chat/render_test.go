@@ -0,0 +1,209 @@
+package chat
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/aidenwallis/go-twitch-client/helix"
+	"github.com/aidenwallis/go-twitch-client/internal/testutils"
+	"github.com/aidenwallis/go-twitch-client/internal/testutils/assert"
+	"github.com/aidenwallis/go-twitch-client/irc"
+)
+
+func renderMessage(t *testing.T, line string) *irc.PrivateMessage {
+	msg, err := irc.Parse(line)
+	assert.NoError(t, err)
+	privmsg, err := msg.PrivateMessage()
+	assert.NoError(t, err)
+	return privmsg
+}
+
+func TestRendererHTML(t *testing.T) {
+	r, err := NewRenderer(&RendererOptions{Format: RenderFormatHTML})
+	assert.NoError(t, err)
+
+	msg := renderMessage(t, `@color=#FF0000;display-name=Forsen;emotes=25:0-4;room-id=1 :forsen!forsen@forsen PRIVMSG #forsen :Kappa <script>alert(1)</script> @pajlada`)
+	out, err := r.Render(msg)
+	assert.NoError(t, err)
+	assert.Equal(t, `<div class="chat-message"><span class="chat-name" style="color: #FF0000">Forsen</span>: <span class="chat-text">`+
+		`<img class="chat-emote" src="https://static-cdn.jtvnw.net/emoticons/v2/25/default/dark/1.0" alt="Kappa" title="Kappa">`+
+		` &lt;script&gt;alert(1)&lt;/script&gt; <span class="chat-mention">@pajlada</span></span></div>`, out)
+
+	// invalid colors never reach the output
+	msg = renderMessage(t, `@color=red;x"onclick=1;display-name=a"b :ab!ab@ab PRIVMSG #forsen :`+"\x01ACTION hi\x01")
+	r, err = NewRenderer(&RendererOptions{Format: RenderFormatHTML, DefaultColor: "#123456"})
+	assert.NoError(t, err)
+	out, err = r.Render(msg)
+	assert.NoError(t, err)
+	assert.Equal(t, `<div class="chat-message chat-action"><span class="chat-name" style="color: #123456">a&#34;b</span> `+
+		`<span class="chat-text" style="color: #123456">hi</span></div>`, out)
+}
+
+func TestRendererMarkdown(t *testing.T) {
+	api := &badgeAPI{t: t}
+	badges := NewBadgeResolver(&BadgeResolverOptions{Helix: api.client()})
+	assert.NoError(t, badges.Load(context.Background(), "1"))
+
+	r, err := NewRenderer(&RendererOptions{Format: RenderFormatMarkdown, Badges: badges})
+	assert.NoError(t, err)
+
+	msg := renderMessage(t, `@badges=moderator/1;display-name=Forsen;emotes=25:0-4;room-id=1 :forsen!forsen@forsen PRIVMSG #forsen :Kappa **hi** @everyone`)
+	out, err := r.Render(msg)
+	assert.NoError(t, err)
+	assert.Equal(t, `![Moderator](mod.png) **Forsen**: ![Kappa](https://static-cdn.jtvnw.net/emoticons/v2/25/default/dark/1.0) \*\*hi\*\* \@everyone`, out)
+}
+
+func TestRendererANSI(t *testing.T) {
+	r, err := NewRenderer(&RendererOptions{Format: RenderFormatANSI})
+	assert.NoError(t, err)
+
+	msg := renderMessage(t, "@color=#0A0B0C;emotes=25:6-10 :forsen!forsen@forsen PRIVMSG #forsen :hello Kappa \x1b[2J")
+	out, err := r.Render(msg)
+	assert.NoError(t, err)
+	assert.Equal(t, "\x1b[38;2;10;11;12m\x1b[1mforsen\x1b[22m\x1b[39m: hello \x1b[1mKappa\x1b[22m [2J", out)
+
+	// users without a color get a stable default color
+	msg = renderMessage(t, ":forsen!forsen@forsen PRIVMSG #forsen :hi")
+	first, err := r.Render(msg)
+	assert.NoError(t, err)
+	second, err := r.Render(msg)
+	assert.NoError(t, err)
+	assert.Equal(t, first, second)
+}
+
+func TestRendererHelixColors(t *testing.T) {
+	requests := 0
+	fail := false
+	client := helix.NewClient(&helix.ClientOptions{
+		ClientID: "clientID",
+		Transport: testutils.Middleware(func(req *http.Request) *testutils.Response {
+			requests++
+			if fail {
+				return testutils.EmptyResponse(http.StatusInternalServerError)
+			}
+			assert.Equal(t, "1", req.URL.Query().Get("user_id"))
+			return testutils.JSONResponse(t, http.StatusOK, &helix.GetUserChatColorsResponse{
+				Data: []*helix.UserChatColor{{UserID: "1", Color: "#00FF00"}},
+			})
+		}),
+	})
+
+	now := time.Unix(0, 0)
+	r, err := NewRenderer(&RendererOptions{
+		Format:       RenderFormatANSI,
+		Template:     "{{.Color}}",
+		Helix:        client,
+		DefaultColor: "#FFFFFF",
+	})
+	assert.NoError(t, err)
+	r.now = func() time.Time { return now }
+	var errs []error
+	r.OnError(func(err error) {
+		errs = append(errs, err)
+	})
+
+	render := func(msg *irc.PrivateMessage) string {
+		out, err := r.Render(msg)
+		assert.NoError(t, err)
+		return out
+	}
+
+	// colors are loaded in the background, the default color is used until then
+	msg := renderMessage(t, "@user-id=1 :forsen!forsen@forsen PRIVMSG #forsen :hi")
+	assert.Equal(t, "#FFFFFF", render(msg))
+	r.wg.Wait()
+	for i := 0; i < 2; i++ {
+		assert.Equal(t, "#00FF00", render(msg))
+	}
+	assert.Equal(t, 1, requests)
+
+	// the color tag takes precedence over Helix
+	assert.Equal(t, "#0000FF", render(renderMessage(t, "@user-id=1;color=#0000FF :forsen!forsen@forsen PRIVMSG #forsen :hi")))
+
+	// failed refreshes keep the cached color, and are not retried until the retry interval passed
+	now = now.Add(2 * time.Hour)
+	fail = true
+	for i := 0; i < 5; i++ {
+		assert.Equal(t, "#00FF00", render(msg))
+		r.wg.Wait()
+	}
+	assert.Equal(t, 2, requests)
+	assert.Equal(t, 1, len(errs))
+
+	now = now.Add(colorRetryInterval)
+	fail = false
+	render(msg)
+	r.wg.Wait()
+	assert.Equal(t, 3, requests)
+	assert.Equal(t, "#00FF00", render(msg))
+	assert.Equal(t, 3, requests)
+}
+
+func TestRendererLoadColors(t *testing.T) {
+	var requests []int
+	client := helix.NewClient(&helix.ClientOptions{
+		ClientID: "clientID",
+		Transport: testutils.Middleware(func(req *http.Request) *testutils.Response {
+			userIDs := req.URL.Query()["user_id"]
+			requests = append(requests, len(userIDs))
+			resp := &helix.GetUserChatColorsResponse{}
+			for _, userID := range userIDs {
+				resp.Data = append(resp.Data, &helix.UserChatColor{UserID: userID, Color: "#00FF00"})
+			}
+			return testutils.JSONResponse(t, http.StatusOK, resp)
+		}),
+	})
+
+	r, err := NewRenderer(&RendererOptions{Format: RenderFormatANSI, Template: "{{.Color}}", Helix: client})
+	assert.NoError(t, err)
+
+	userIDs := make([]string, 150)
+	for i := range userIDs {
+		userIDs[i] = strconv.Itoa(i)
+	}
+	assert.NoError(t, r.LoadColors(context.Background(), userIDs...))
+	assert.Equal(t, 2, len(requests))
+	assert.Equal(t, 100, requests[0])
+	assert.Equal(t, 50, requests[1])
+
+	// loaded colors are used right away
+	out, err := r.Render(renderMessage(t, "@user-id=149 :forsen!forsen@forsen PRIVMSG #forsen :hi"))
+	assert.NoError(t, err)
+	assert.Equal(t, "#00FF00", out)
+	assert.Equal(t, 2, len(requests))
+
+	r, err = NewRenderer(&RendererOptions{Format: RenderFormatANSI})
+	assert.NoError(t, err)
+	assert.ErrorIs(t, r.LoadColors(context.Background(), "1"), ErrMissingHelix)
+}
+
+func TestRendererThirdPartyEmotes(t *testing.T) {
+	s := (&providerAPIs{}).serve(t)
+	var setRequests []string
+	emotes := NewEmoteCatalog(&EmoteCatalogOptions{
+		Helix:     emoteHelix(t, &setRequests),
+		Providers: []EmoteProvider{NewBetterTTVProvider(&EmoteProviderOptions{BaseURL: s.URL + "/bttv"})},
+	})
+	assert.NoError(t, emotes.LoadChannel(context.Background(), "1"))
+
+	r, err := NewRenderer(&RendererOptions{Format: RenderFormatMarkdown, Emotes: emotes})
+	assert.NoError(t, err)
+
+	// third-party emotes are added, Twitch emotes missing from the emotes tag are not
+	msg := renderMessage(t, "@room-id=1;emotes= :forsen!forsen@forsen PRIVMSG #forsen :catJAM forsenE")
+	out, err := r.Render(msg)
+	assert.NoError(t, err)
+	assert.Equal(t, "**forsen**: ![catJAM](https://cdn.betterttv.net/emote/b4/1x) forsenE", out)
+}
+
+func TestNewRendererErrors(t *testing.T) {
+	_, err := NewRenderer(nil)
+	assert.ErrorIs(t, err, ErrUnknownRenderFormat)
+
+	_, err = NewRenderer(&RendererOptions{Format: RenderFormatHTML, Template: "{{"})
+	assert.Equal(t, true, err != nil && !errors.Is(err, ErrUnknownRenderFormat))
+}
@@ -0,0 +1,297 @@
+package chat
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/aidenwallis/go-twitch-client"
+	"github.com/aidenwallis/go-twitch-client/eventsub"
+	"github.com/aidenwallis/go-twitch-client/helix"
+	"github.com/aidenwallis/go-twitch-client/irc"
+)
+
+// RoomStateChange is passed to the OnChange handler when the chat settings of a channel change
+type RoomStateChange struct {
+	// Channel is the login of the channel.
+	Channel string
+
+	// BroadcasterID is the user ID of the channel, empty when it is not known yet.
+	BroadcasterID string
+
+	// Previous is the settings before the change, nil when the channel was not tracked before.
+	Previous *helix.ChatSettings
+
+	// Settings is the current settings.
+	Settings *helix.ChatSettings
+}
+
+// RoomStateOptions defines all options the room state tracker supports.
+type RoomStateOptions struct {
+	// Helix (optional) is used by Load to seed the settings of a channel.
+	Helix helix.Client
+
+	// ModeratorID (optional) is passed to GetChatSettings, so the settings include the non-moderator chat delay. It
+	// may be left empty when helix.ClientOptions.ResolveActingUser is enabled.
+	ModeratorID string
+}
+
+// RoomState tracks the chat settings of channels, such as emote-only and slow mode, in the shape returned by
+// helix.Client.GetChatSettings.
+//
+// Settings are seeded by Load, and kept up to date by passing ROOMSTATE messages to Observe, and
+// channel.chat_settings.update notifications to ObserveEvent.
+type RoomState struct {
+	helix       helix.Client
+	moderatorID string
+
+	// notifyMu is held from applying an update until its OnChange handler returns, so changes are notified in the
+	// order they were applied
+	notifyMu sync.Mutex
+
+	mu    sync.Mutex
+	rooms map[string]*room
+
+	onChange func(change *RoomStateChange)
+}
+
+// room is the tracked settings of a channel
+type room struct {
+	broadcasterID string
+	settings      helix.ChatSettings
+	updates       uint64 // number of updates applied, so Load can detect updates during its request
+}
+
+// NewRoomState creates a new instance of RoomState
+func NewRoomState(options *RoomStateOptions) *RoomState {
+	if options == nil {
+		options = &RoomStateOptions{}
+	}
+
+	return &RoomState{
+		helix:       options.Helix,
+		moderatorID: options.ModeratorID,
+		rooms:       map[string]*room{},
+	}
+}
+
+// OnChange sets the handler called whenever the settings of a channel change, including when a channel is first
+// tracked. Changes are notified one at a time, in the order they were applied, so the handler must not call Load,
+// Observe, ObserveEvent or SetChatSettings.
+//
+// Handlers must be registered before calling Load or Observe.
+func (s *RoomState) OnChange(handler func(change *RoomStateChange)) {
+	s.onChange = handler
+}
+
+// Load seeds the settings of the channel from Helix, replacing the tracked settings. When the settings are updated
+// while the request is in flight, such as by a ROOMSTATE message, the response is stale and is discarded.
+func (s *RoomState) Load(ctx context.Context, channel, broadcasterID string) error {
+	if s.helix == nil {
+		return ErrMissingHelix
+	}
+
+	var updates uint64
+	s.mu.Lock()
+	if r, ok := s.rooms[normalizeChannel(channel)]; ok {
+		updates = r.updates
+	}
+	s.mu.Unlock()
+
+	resp, err := s.helix.GetChatSettings(ctx, &helix.GetChatSettingsRequest{
+		BroadcasterID: broadcasterID,
+		ModeratorID:   s.moderatorID,
+	})
+	if err != nil {
+		return err
+	}
+	if len(resp.Data) == 0 {
+		return nil
+	}
+
+	settings := copyChatSettings(resp.Data[0])
+	s.update(channel, broadcasterID, func(r *room) bool {
+		if r.updates != updates {
+			return false
+		}
+		r.settings = *settings
+		return true
+	})
+	return nil
+}
+
+// Settings returns a copy of the settings of the channel, and whether the channel is tracked.
+func (s *RoomState) Settings(channel string) (*helix.ChatSettings, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r, ok := s.rooms[normalizeChannel(channel)]
+	if !ok {
+		return nil, false
+	}
+	return copyChatSettings(&r.settings), true
+}
+
+// Channels returns the sorted channels that are tracked.
+func (s *RoomState) Channels() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	channels := make([]string, 0, len(s.rooms))
+	for channel := range s.rooms {
+		channels = append(channels, channel)
+	}
+	sort.Strings(channels)
+	return channels
+}
+
+// Remove stops tracking the channel, such as after parting it.
+func (s *RoomState) Remove(channel string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.rooms, normalizeChannel(channel))
+}
+
+// SetChatSettings replaces the settings of the channel, such as with the settings returned by
+// helix.Client.UpdateChatSettings. broadcasterID may be left empty when it is not known.
+func (s *RoomState) SetChatSettings(channel, broadcasterID string, settings *helix.ChatSettings) {
+	settings = copyChatSettings(settings)
+	s.update(channel, broadcasterID, func(r *room) bool {
+		r.settings = *settings
+		return true
+	})
+}
+
+// Observe applies the changed settings of ROOMSTATE messages. Other messages are ignored.
+func (s *RoomState) Observe(msg *irc.Message) {
+	if msg.Command != irc.CommandRoomState {
+		return
+	}
+	state, err := msg.RoomState()
+	if err != nil {
+		return
+	}
+
+	s.update(state.Channel, state.RoomID, func(r *room) bool {
+		current := &r.settings
+		if state.EmoteOnly != nil {
+			current.EmoteMode = *state.EmoteOnly
+		}
+		if state.FollowersOnly != nil {
+			current.FollowerMode = *state.FollowersOnly >= 0
+			current.FollowerModeDuration = nil
+			if current.FollowerMode {
+				current.FollowerModeDuration = twitch.Pointer(*state.FollowersOnly)
+			}
+		}
+		if state.UniqueChat != nil {
+			current.UniqueChatMode = *state.UniqueChat
+		}
+		if state.Slow != nil {
+			current.SlowMode = *state.Slow > 0
+			current.SlowModeWaitTime = nil
+			if current.SlowMode {
+				current.SlowModeWaitTime = twitch.Pointer(*state.Slow)
+			}
+		}
+		if state.SubsOnly != nil {
+			current.SubscriberMode = *state.SubsOnly
+		}
+		return true
+	})
+}
+
+// ObserveEvent applies a channel.chat_settings.update notification, register it with
+// eventsub.Dispatcher.OnChannelChatSettingsUpdate. The non-moderator chat delay is kept, as notifications do not
+// include it.
+func (s *RoomState) ObserveEvent(_ context.Context, event *eventsub.ChannelChatSettingsUpdateEvent) {
+	settings := event.ChatSettings()
+	s.update(event.BroadcasterUserLogin, event.BroadcasterUserID, func(r *room) bool {
+		settings.NonModeratorChatDelay = r.settings.NonModeratorChatDelay
+		settings.NonModeratorChatDelayDuration = r.settings.NonModeratorChatDelayDuration
+		r.settings = *settings
+		return true
+	})
+}
+
+// update applies the change to the room of the channel, and notifies the OnChange handler when the settings changed.
+// apply returns false to leave the room unchanged.
+func (s *RoomState) update(channel, broadcasterID string, apply func(r *room) bool) {
+	channel = normalizeChannel(channel)
+	if channel == "" {
+		return
+	}
+
+	s.notifyMu.Lock()
+	defer s.notifyMu.Unlock()
+
+	s.mu.Lock()
+	r, ok := s.rooms[channel]
+	if !ok {
+		r = &room{}
+	}
+
+	var previous *helix.ChatSettings
+	if ok {
+		previous = copyChatSettings(&r.settings)
+	}
+	if !apply(r) {
+		s.mu.Unlock()
+		return
+	}
+	s.rooms[channel] = r
+	r.updates++
+	if broadcasterID != "" {
+		r.broadcasterID = broadcasterID
+	}
+
+	changed := previous == nil || !equalChatSettings(previous, &r.settings)
+	change := &RoomStateChange{
+		Channel:       channel,
+		BroadcasterID: r.broadcasterID,
+		Previous:      previous,
+		Settings:      copyChatSettings(&r.settings),
+	}
+	s.mu.Unlock()
+
+	if changed && s.onChange != nil {
+		s.onChange(change)
+	}
+}
+
+// copyChatSettings returns a deep copy of the settings, so callers cannot modify the tracked settings
+func copyChatSettings(settings *helix.ChatSettings) *helix.ChatSettings {
+	c := *settings
+	c.FollowerModeDuration = copyInt(settings.FollowerModeDuration)
+	c.NonModeratorChatDelayDuration = copyInt(settings.NonModeratorChatDelayDuration)
+	c.SlowModeWaitTime = copyInt(settings.SlowModeWaitTime)
+	return &c
+}
+
+// equalChatSettings returns whether both settings are the same, comparing durations by value
+func equalChatSettings(a, b *helix.ChatSettings) bool {
+	return a.EmoteMode == b.EmoteMode &&
+		a.FollowerMode == b.FollowerMode &&
+		a.NonModeratorChatDelay == b.NonModeratorChatDelay &&
+		a.SlowMode == b.SlowMode &&
+		a.SubscriberMode == b.SubscriberMode &&
+		a.UniqueChatMode == b.UniqueChatMode &&
+		equalInt(a.FollowerModeDuration, b.FollowerModeDuration) &&
+		equalInt(a.NonModeratorChatDelayDuration, b.NonModeratorChatDelayDuration) &&
+		equalInt(a.SlowModeWaitTime, b.SlowModeWaitTime)
+}
+
+func copyInt(v *int) *int {
+	if v == nil {
+		return nil
+	}
+	return twitch.Pointer(*v)
+}
+
+func equalInt(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
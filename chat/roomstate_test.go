@@ -0,0 +1,167 @@
+package chat
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/aidenwallis/go-twitch-client"
+	"github.com/aidenwallis/go-twitch-client/eventsub"
+	"github.com/aidenwallis/go-twitch-client/helix"
+	"github.com/aidenwallis/go-twitch-client/internal/testutils"
+	"github.com/aidenwallis/go-twitch-client/internal/testutils/assert"
+	"github.com/aidenwallis/go-twitch-client/irc"
+)
+
+func TestRoomState(t *testing.T) {
+	client := helix.NewClient(&helix.ClientOptions{
+		ClientID: "clientID",
+		Transport: testutils.Middleware(func(req *http.Request) *testutils.Response {
+			assert.Equal(t, "1", req.URL.Query().Get("broadcaster_id"))
+			assert.Equal(t, "2", req.URL.Query().Get("moderator_id"))
+			return testutils.JSONResponse(t, http.StatusOK, &helix.GetChatSettingsResponse{
+				Data: []*helix.ChatSettings{{
+					SlowMode:                      true,
+					SlowModeWaitTime:              twitch.Pointer(30),
+					NonModeratorChatDelay:         true,
+					NonModeratorChatDelayDuration: twitch.Pointer(4),
+				}},
+			})
+		}),
+	})
+
+	s := NewRoomState(&RoomStateOptions{Helix: client, ModeratorID: "2"})
+	var changes []*RoomStateChange
+	s.OnChange(func(change *RoomStateChange) {
+		changes = append(changes, change)
+	})
+
+	assert.NoError(t, s.Load(context.Background(), "Forsen", "1"))
+	assert.Equal(t, 1, len(changes))
+	assert.Equal(t, true, changes[0].Previous == nil)
+	assert.Equal(t, "forsen", changes[0].Channel)
+	assert.Equal(t, "1", changes[0].BroadcasterID)
+	assert.Equal(t, 30, *changes[0].Settings.SlowModeWaitTime)
+
+	observe := func(line string) {
+		msg, err := irc.Parse(line)
+		assert.NoError(t, err)
+		s.Observe(msg)
+	}
+
+	// ROOMSTATE deltas only change the included settings
+	observe("@emote-only=1;room-id=1 :tmi.twitch.tv ROOMSTATE #forsen")
+	assert.Equal(t, 2, len(changes))
+	assert.Equal(t, false, changes[1].Previous.EmoteMode)
+	assert.Equal(t, true, changes[1].Settings.EmoteMode)
+	assert.Equal(t, true, changes[1].Settings.SlowMode)
+
+	observe("@slow=0;followers-only=10 :tmi.twitch.tv ROOMSTATE #forsen")
+	settings, ok := s.Settings("#FORSEN")
+	assert.Equal(t, true, ok)
+	assert.Equal(t, false, settings.SlowMode)
+	assert.Equal(t, true, settings.SlowModeWaitTime == nil)
+	assert.Equal(t, true, settings.FollowerMode)
+	assert.Equal(t, 10, *settings.FollowerModeDuration)
+
+	// unchanged settings and other messages do not notify
+	observe("@emote-only=1 :tmi.twitch.tv ROOMSTATE #forsen")
+	observe(":forsen!forsen@forsen PRIVMSG #forsen :hi")
+	assert.Equal(t, 3, len(changes))
+
+	// EventSub notifications replace the settings, apart from the chat delay they do not include
+	s.ObserveEvent(context.Background(), &eventsub.ChannelChatSettingsUpdateEvent{
+		Broadcaster:    eventsub.Broadcaster{BroadcasterUserID: "1", BroadcasterUserLogin: "forsen"},
+		SubscriberMode: true,
+	})
+	assert.Equal(t, 4, len(changes))
+	settings = changes[3].Settings
+	assert.Equal(t, false, settings.EmoteMode)
+	assert.Equal(t, false, settings.FollowerMode)
+	assert.Equal(t, true, settings.SubscriberMode)
+	assert.Equal(t, true, settings.NonModeratorChatDelay)
+	assert.Equal(t, 4, *settings.NonModeratorChatDelayDuration)
+
+	// returned settings are copies
+	*settings.NonModeratorChatDelayDuration = 0
+	settings, _ = s.Settings("forsen")
+	assert.Equal(t, 4, *settings.NonModeratorChatDelayDuration)
+
+	// channels are tracked from their first ROOMSTATE
+	observe("@emote-only=0;followers-only=-1;r9k=1;room-id=3;slow=0;subs-only=0 :tmi.twitch.tv ROOMSTATE #nymn")
+	assert.Equal(t, 5, len(changes))
+	assert.Equal(t, "3", changes[4].BroadcasterID)
+	assert.Equal(t, true, changes[4].Settings.UniqueChatMode)
+	assert.Equal(t, "forsen,nymn", strings.Join(s.Channels(), ","))
+
+	s.Remove("nymn")
+	_, ok = s.Settings("nymn")
+	assert.Equal(t, false, ok)
+}
+
+func TestRoomStateMissingHelix(t *testing.T) {
+	assert.ErrorIs(t, NewRoomState(nil).Load(context.Background(), "forsen", "1"), ErrMissingHelix)
+}
+
+func TestRoomStateNotifyOrder(t *testing.T) {
+	s := NewRoomState(nil)
+	var last *helix.ChatSettings
+	changes := 0
+	s.OnChange(func(change *RoomStateChange) {
+		// every change follows the previously notified settings
+		assert.Equal(t, last == nil, change.Previous == nil)
+		if last != nil {
+			assert.Equal(t, true, equalChatSettings(last, change.Previous))
+		}
+		last = change.Settings
+		changes++
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				s.SetChatSettings("forsen", "1", &helix.ChatSettings{SlowMode: true, SlowModeWaitTime: twitch.Pointer(i*100 + j)})
+			}
+		}(i)
+	}
+	wg.Wait()
+	assert.Equal(t, 400, changes)
+
+	settings, _ := s.Settings("forsen")
+	assert.Equal(t, true, equalChatSettings(last, settings))
+}
+
+func TestRoomStateStaleLoad(t *testing.T) {
+	var s *RoomState
+	client := helix.NewClient(&helix.ClientOptions{
+		ClientID: "clientID",
+		Transport: testutils.Middleware(func(req *http.Request) *testutils.Response {
+			// a ROOMSTATE arrives while the request is in flight
+			msg, err := irc.Parse("@emote-only=1;followers-only=-1;r9k=0;room-id=1;slow=0;subs-only=0 :tmi.twitch.tv ROOMSTATE #forsen")
+			assert.NoError(t, err)
+			s.Observe(msg)
+
+			return testutils.JSONResponse(t, http.StatusOK, &helix.GetChatSettingsResponse{
+				Data: []*helix.ChatSettings{{SlowMode: true, SlowModeWaitTime: twitch.Pointer(30)}},
+			})
+		}),
+	})
+
+	s = NewRoomState(&RoomStateOptions{Helix: client})
+	changes := 0
+	s.OnChange(func(change *RoomStateChange) {
+		changes++
+	})
+
+	assert.NoError(t, s.Load(context.Background(), "forsen", "1"))
+	assert.Equal(t, 1, changes)
+	settings, ok := s.Settings("forsen")
+	assert.Equal(t, true, ok)
+	assert.Equal(t, true, settings.EmoteMode)
+	assert.Equal(t, false, settings.SlowMode)
+}
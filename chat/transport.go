@@ -0,0 +1,144 @@
+package chat
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aidenwallis/go-twitch-client/internal/websocket"
+)
+
+// writeTimeout is how long a single line may take to write before the connection is considered dead
+const writeTimeout = 10 * time.Second
+
+// transport is a connection to the chat server that sends and receives single lines, without the trailing CRLF.
+type transport interface {
+	readLine() (string, error)
+	writeLine(line string) error
+	setReadDeadline(t time.Time) error
+	close() error
+}
+
+// dial connects to the address, which is a tcp://, tls://, ws:// or wss:// URL.
+func dial(ctx context.Context, address string, tlsConfig *tls.Config) (transport, error) {
+	u, err := url.Parse(address)
+	if err != nil {
+		return nil, err
+	}
+
+	switch u.Scheme {
+	case "tcp":
+		conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", u.Host)
+		if err != nil {
+			return nil, err
+		}
+		return newLineTransport(conn), nil
+
+	case "tls":
+		config := &tls.Config{}
+		if tlsConfig != nil {
+			config = tlsConfig.Clone()
+		}
+		if config.ServerName == "" {
+			config.ServerName = u.Hostname()
+		}
+		conn, err := (&tls.Dialer{Config: config}).DialContext(ctx, "tcp", u.Host)
+		if err != nil {
+			return nil, err
+		}
+		return newLineTransport(conn), nil
+
+	case "ws", "wss":
+		conn, err := (&websocket.Dialer{TLSConfig: tlsConfig}).Dial(ctx, address, nil)
+		if err != nil {
+			return nil, err
+		}
+		return &webSocketTransport{conn: conn}, nil
+
+	default:
+		return nil, fmt.Errorf("chat: unsupported address scheme %q", u.Scheme)
+	}
+}
+
+// lineTransport is a transport over a plain TCP or TLS connection
+type lineTransport struct {
+	conn   net.Conn
+	reader *bufio.Reader
+
+	writeMu sync.Mutex
+}
+
+func newLineTransport(conn net.Conn) *lineTransport {
+	return &lineTransport{conn: conn, reader: bufio.NewReader(conn)}
+}
+
+func (t *lineTransport) readLine() (string, error) {
+	line, err := t.reader.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+func (t *lineTransport) writeLine(line string) error {
+	t.writeMu.Lock()
+	defer t.writeMu.Unlock()
+
+	_ = t.conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+	_, err := t.conn.Write([]byte(line + "\r\n"))
+	return err
+}
+
+func (t *lineTransport) setReadDeadline(deadline time.Time) error {
+	return t.conn.SetReadDeadline(deadline)
+}
+
+func (t *lineTransport) close() error {
+	return t.conn.Close()
+}
+
+// webSocketTransport is a transport over a WebSocket connection, where a single message may contain multiple lines
+type webSocketTransport struct {
+	conn    *websocket.Conn
+	pending []string
+}
+
+func (t *webSocketTransport) readLine() (string, error) {
+	for len(t.pending) == 0 {
+		messageType, data, err := t.conn.ReadMessage()
+		if err != nil {
+			return "", err
+		}
+		if messageType != websocket.TextMessage {
+			continue
+		}
+
+		for _, line := range strings.Split(string(data), "\n") {
+			if line = strings.TrimRight(line, "\r"); line != "" {
+				t.pending = append(t.pending, line)
+			}
+		}
+	}
+
+	line := t.pending[0]
+	t.pending = t.pending[1:]
+	return line, nil
+}
+
+func (t *webSocketTransport) writeLine(line string) error {
+	return t.conn.WriteMessage(websocket.TextMessage, []byte(line))
+}
+
+func (t *webSocketTransport) setReadDeadline(deadline time.Time) error {
+	return t.conn.SetReadDeadline(deadline)
+}
+
+func (t *webSocketTransport) close() error {
+	return t.conn.Close()
+}
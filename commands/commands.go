@@ -0,0 +1,396 @@
+// Package commands implements a chat command router for bots, with prefix commands, aliases, argument parsing,
+// permission levels derived from badges, cooldowns, and threaded replies.
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/aidenwallis/go-twitch-client/irc"
+)
+
+// DefaultPrefix is the command prefix used when none is configured
+const DefaultPrefix = "!"
+
+// minCooldownSweep is how many cooldowns are tracked before expired ones are removed.
+const minCooldownSweep = 1024
+
+var (
+	// ErrInvalidCommand is returned when registering a command without a name or handler, or with a name containing
+	// whitespace.
+	ErrInvalidCommand = errors.New("commands: command requires a name and handler")
+
+	// ErrDuplicateCommand is returned when registering a command whose name or alias is already registered.
+	ErrDuplicateCommand = errors.New("commands: command name is already registered")
+)
+
+// Permission is the permission level of a chat user, higher levels include every lower level
+type Permission int
+
+// Permission levels, from lowest to highest.
+const (
+	PermissionEveryone Permission = iota
+	PermissionSubscriber
+	PermissionVIP
+	PermissionModerator
+	PermissionBroadcaster
+)
+
+// String returns the name of the permission level.
+func (p Permission) String() string {
+	switch p {
+	case PermissionEveryone:
+		return "everyone"
+	case PermissionSubscriber:
+		return "subscriber"
+	case PermissionVIP:
+		return "vip"
+	case PermissionModerator:
+		return "moderator"
+	case PermissionBroadcaster:
+		return "broadcaster"
+	}
+	return fmt.Sprintf("Permission(%d)", int(p))
+}
+
+// PermissionOf returns the highest permission level of the user, derived from their badges and tags.
+func PermissionOf(user *irc.User) Permission {
+	switch {
+	case user.IsBroadcaster():
+		return PermissionBroadcaster
+	case user.Mod || user.HasBadge("moderator"):
+		return PermissionModerator
+	case user.IsVIP():
+		return PermissionVIP
+	case user.Subscriber || user.HasBadge("subscriber") || user.HasBadge("founder"):
+		return PermissionSubscriber
+	}
+	return PermissionEveryone
+}
+
+// Handler handles an invocation of a command
+type Handler func(ctx context.Context, inv *Invocation) error
+
+// Command is a chat command
+type Command struct {
+	// Name is the name the command is invoked with, after the prefix. Names are case insensitive.
+	Name string
+
+	// Aliases (optional) are other names the command can be invoked with.
+	Aliases []string
+
+	// Description (optional) describes the command, such as for a help command.
+	Description string
+
+	// Permission (optional) is the minimum permission level needed to invoke the command, defaults to
+	// PermissionEveryone.
+	Permission Permission
+
+	// Cooldown (optional) is how long the command cannot be invoked in a channel after it was invoked there.
+	Cooldown time.Duration
+
+	// UserCooldown (optional) is how long a user cannot invoke the command in a channel after they invoked it there.
+	UserCooldown time.Duration
+
+	// Handler is called when the command is invoked.
+	Handler Handler
+}
+
+// Invocation is a command invoked by a chat message
+type Invocation struct {
+	// Message is the message that invoked the command.
+	Message *irc.PrivateMessage
+
+	// Command is the invoked command.
+	Command *Command
+
+	// Name is the lowercased name or alias the command was invoked with.
+	Name string
+
+	// Args are the arguments after the command name, split on whitespace. Double quotes group words into a single
+	// argument.
+	Args []string
+
+	// RawArgs is the text after the command name.
+	RawArgs string
+
+	// Permission is the permission level of the user who invoked the command.
+	Permission Permission
+
+	router *Router
+}
+
+// Reply sends a reply to the message that invoked the command, threaded to it in the chat UI.
+func (i *Invocation) Reply(ctx context.Context, text string) error {
+	return i.router.send(ctx, &OutgoingMessage{
+		Channel:              i.Message.Channel,
+		BroadcasterID:        i.Message.RoomID,
+		Text:                 text,
+		ReplyParentMessageID: i.Message.ID,
+	})
+}
+
+// Say sends a message to the channel the command was invoked in.
+func (i *Invocation) Say(ctx context.Context, text string) error {
+	return i.router.send(ctx, &OutgoingMessage{
+		Channel:       i.Message.Channel,
+		BroadcasterID: i.Message.RoomID,
+		Text:          text,
+	})
+}
+
+// RouterOptions defines all options the router supports.
+type RouterOptions struct {
+	// Prefix (optional) is the prefix commands are invoked with, defaults to DefaultPrefix.
+	Prefix string
+
+	// Sender (optional) sends the replies of commands, replying fails with ErrMissingSender without one.
+	Sender Sender
+}
+
+// Router routes chat messages to the commands they invoke.
+//
+// Messages from users below the permission level of a command, or sent while the command is on cooldown, are ignored.
+type Router struct {
+	prefix string
+	sender Sender
+	now    func() time.Time
+
+	mu        sync.Mutex
+	commands  []*Command
+	names     map[string]*Command
+	cooldowns map[cooldownKey]time.Time
+	nextSweep int
+
+	onError func(err error)
+}
+
+// cooldownKey identifies the cooldown of a command in a channel, user is empty for the channel-wide cooldown
+type cooldownKey struct {
+	channel string
+	command string
+	user    string
+}
+
+// NewRouter creates a new instance of Router
+func NewRouter(options *RouterOptions) *Router {
+	if options == nil {
+		options = &RouterOptions{}
+	}
+
+	r := &Router{
+		prefix:    DefaultPrefix,
+		sender:    options.Sender,
+		now:       time.Now,
+		names:     map[string]*Command{},
+		cooldowns: map[cooldownKey]time.Time{},
+		nextSweep: minCooldownSweep,
+	}
+	if options.Prefix != "" {
+		r.prefix = options.Prefix
+	}
+	return r
+}
+
+// OnError sets the handler for errors returned by commands invoked through HandleMessage.
+//
+// Handlers must be registered before handling messages.
+func (r *Router) OnError(handler func(err error)) {
+	r.onError = handler
+}
+
+// Register registers the commands, none are registered when any of them is invalid or uses a name that is taken.
+func (r *Router) Register(commands ...*Command) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	names := map[string]*Command{}
+	for _, cmd := range commands {
+		if cmd == nil || cmd.Handler == nil {
+			return ErrInvalidCommand
+		}
+		for _, name := range append([]string{cmd.Name}, cmd.Aliases...) {
+			name = strings.ToLower(name)
+			if name == "" || strings.IndexFunc(name, unicode.IsSpace) >= 0 {
+				return fmt.Errorf("%w: %q", ErrInvalidCommand, name)
+			}
+			if _, ok := r.names[name]; ok {
+				return fmt.Errorf("%w: %s", ErrDuplicateCommand, name)
+			}
+			if other, ok := names[name]; ok && other != cmd {
+				return fmt.Errorf("%w: %s", ErrDuplicateCommand, name)
+			}
+			names[name] = cmd
+		}
+	}
+
+	for name, cmd := range names {
+		r.names[name] = cmd
+	}
+	r.commands = append(r.commands, commands...)
+	return nil
+}
+
+// Commands returns the registered commands, in the order they were registered.
+func (r *Router) Commands() []*Command {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	commands := make([]*Command, len(r.commands))
+	copy(commands, r.commands)
+	return commands
+}
+
+// HandleMessage handles a message received from chat, ignoring everything but PRIVMSGs. It can be passed to
+// chat.Client.OnMessage, errors returned by commands are passed to the OnError handler.
+func (r *Router) HandleMessage(ctx context.Context, msg *irc.Message) {
+	if msg.Command != irc.CommandPrivmsg {
+		return
+	}
+
+	privmsg, err := msg.PrivateMessage()
+	if err == nil {
+		err = r.Handle(ctx, privmsg)
+	}
+	if err != nil && r.onError != nil {
+		r.onError(err)
+	}
+}
+
+// Handle invokes the command the message invokes, if any, returning the error of the command.
+func (r *Router) Handle(ctx context.Context, msg *irc.PrivateMessage) error {
+	inv := r.parse(msg)
+	if inv == nil || inv.Permission < inv.Command.Permission || !r.startCooldown(msg, inv.Command) {
+		return nil
+	}
+	return inv.Command.Handler(ctx, inv)
+}
+
+// parse returns the invocation of the message, nil when it does not invoke a registered command.
+func (r *Router) parse(msg *irc.PrivateMessage) *Invocation {
+	text := msg.Text
+	if msg.Reply != nil {
+		// replies start with a mention of the parent message sender
+		mention := "@" + msg.Reply.UserLogin + " "
+		if len(text) >= len(mention) && strings.EqualFold(text[:len(mention)], mention) {
+			text = text[len(mention):]
+		}
+	}
+
+	// some clients append U+E0000 to send the same message twice in a row
+	text = strings.TrimFunc(text, func(r rune) bool {
+		return unicode.IsSpace(r) || r == '\U000E0000'
+	})
+	if !strings.HasPrefix(text, r.prefix) {
+		return nil
+	}
+
+	name, rawArgs := text[len(r.prefix):], ""
+	if i := strings.IndexFunc(name, unicode.IsSpace); i >= 0 {
+		name, rawArgs = name[:i], strings.TrimLeftFunc(name[i:], unicode.IsSpace)
+	}
+	name = strings.ToLower(name)
+
+	r.mu.Lock()
+	cmd := r.names[name]
+	r.mu.Unlock()
+	if cmd == nil {
+		return nil
+	}
+
+	return &Invocation{
+		Message:    msg,
+		Command:    cmd,
+		Name:       name,
+		Args:       ParseArgs(rawArgs),
+		RawArgs:    rawArgs,
+		Permission: PermissionOf(&msg.User),
+		router:     r,
+	}
+}
+
+// startCooldown starts the cooldowns of the command, returning false when it is still on cooldown.
+func (r *Router) startCooldown(msg *irc.PrivateMessage, cmd *Command) bool {
+	if cmd.Cooldown <= 0 && cmd.UserCooldown <= 0 {
+		return true
+	}
+
+	channel := msg.RoomID
+	if channel == "" {
+		channel = msg.Channel
+	}
+	global := cooldownKey{channel: channel, command: cmd.Name}
+	user := cooldownKey{channel: channel, command: cmd.Name, user: msg.User.ID}
+	if user.user == "" {
+		user.user = msg.User.Login
+	}
+
+	now := r.now()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if now.Before(r.cooldowns[global]) || now.Before(r.cooldowns[user]) {
+		return false
+	}
+	if cmd.Cooldown > 0 {
+		r.cooldowns[global] = now.Add(cmd.Cooldown)
+	}
+	if cmd.UserCooldown > 0 {
+		r.cooldowns[user] = now.Add(cmd.UserCooldown)
+	}
+
+	if len(r.cooldowns) >= r.nextSweep {
+		for key, until := range r.cooldowns {
+			if !now.Before(until) {
+				delete(r.cooldowns, key)
+			}
+		}
+		r.nextSweep = 2 * len(r.cooldowns)
+		if r.nextSweep < minCooldownSweep {
+			r.nextSweep = minCooldownSweep
+		}
+	}
+	return true
+}
+
+func (r *Router) send(ctx context.Context, msg *OutgoingMessage) error {
+	if r.sender == nil {
+		return ErrMissingSender
+	}
+	return r.sender.Send(ctx, msg)
+}
+
+// ParseArgs splits the text into arguments on whitespace, double quotes group words into a single argument.
+func ParseArgs(text string) []string {
+	var (
+		args           []string
+		b              strings.Builder
+		inArg, inQuote bool
+	)
+	for _, r := range text {
+		switch {
+		case r == '"':
+			inQuote = !inQuote
+			inArg = true
+		case unicode.IsSpace(r) && !inQuote:
+			if inArg {
+				args = append(args, b.String())
+				b.Reset()
+				inArg = false
+			}
+		default:
+			b.WriteRune(r)
+			inArg = true
+		}
+	}
+	if inArg {
+		args = append(args, b.String())
+	}
+	return args
+}
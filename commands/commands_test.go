@@ -0,0 +1,199 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aidenwallis/go-twitch-client/internal/testutils/assert"
+	"github.com/aidenwallis/go-twitch-client/irc"
+)
+
+// recordingSender records the messages sent through it
+type recordingSender struct {
+	sent []*OutgoingMessage
+}
+
+func (s *recordingSender) Send(_ context.Context, msg *OutgoingMessage) error {
+	s.sent = append(s.sent, msg)
+	return nil
+}
+
+func privmsg(t *testing.T, line string) *irc.PrivateMessage {
+	msg, err := irc.Parse(line)
+	assert.NoError(t, err)
+	p, err := msg.PrivateMessage()
+	assert.NoError(t, err)
+	return p
+}
+
+func TestRouter(t *testing.T) {
+	ctx := context.Background()
+	sender := &recordingSender{}
+	r := NewRouter(&RouterOptions{Sender: sender})
+
+	var invocations []*Invocation
+	assert.NoError(t, r.Register(&Command{
+		Name:    "Echo",
+		Aliases: []string{"say"},
+		Handler: func(ctx context.Context, inv *Invocation) error {
+			invocations = append(invocations, inv)
+			return inv.Reply(ctx, inv.RawArgs)
+		},
+	}))
+
+	assert.NoError(t, r.Handle(ctx, privmsg(t, `@id=m1;room-id=1 :forsen!forsen@forsen PRIVMSG #forsen :!ECHO hello  "big world" `+"\U000E0000")))
+	assert.Equal(t, 1, len(invocations))
+	assert.Equal(t, "echo", invocations[0].Name)
+	assert.Equal(t, `hello  "big world"`, invocations[0].RawArgs)
+	assert.Equal(t, "hello|big world", strings.Join(invocations[0].Args, "|"))
+	assert.Equal(t, PermissionEveryone, invocations[0].Permission)
+
+	assert.Equal(t, 1, len(sender.sent))
+	assert.Equal(t, "forsen", sender.sent[0].Channel)
+	assert.Equal(t, "1", sender.sent[0].BroadcasterID)
+	assert.Equal(t, "m1", sender.sent[0].ReplyParentMessageID)
+
+	// aliases, and commands in replies, which start with a mention of the parent sender
+	assert.NoError(t, r.Handle(ctx, privmsg(t, `@reply-parent-msg-id=m0;reply-parent-user-login=pajlada :forsen!forsen@forsen PRIVMSG #forsen :@Pajlada !say hi`)))
+	assert.Equal(t, 2, len(invocations))
+	assert.Equal(t, "say", invocations[1].Name)
+	assert.Equal(t, "hi", invocations[1].RawArgs)
+
+	// other messages are ignored
+	for _, text := range []string{"echo hi", "! echo", "!echoo", "hi !echo", ""} {
+		assert.NoError(t, r.Handle(ctx, privmsg(t, ":forsen!forsen@forsen PRIVMSG #forsen :"+text)))
+	}
+	assert.Equal(t, 2, len(invocations))
+}
+
+func TestRouterPermissions(t *testing.T) {
+	ctx := context.Background()
+	r := NewRouter(&RouterOptions{Prefix: "?"})
+
+	calls := 0
+	assert.NoError(t, r.Register(&Command{
+		Name:       "ban",
+		Permission: PermissionModerator,
+		Handler: func(context.Context, *Invocation) error {
+			calls++
+			return nil
+		},
+	}))
+
+	for _, tags := range []string{"badges=", "badges=subscriber/12", "badges=vip/1", "badges=founder/0"} {
+		assert.NoError(t, r.Handle(ctx, privmsg(t, "@"+tags+" :a!a@a PRIVMSG #forsen :?ban")))
+	}
+	assert.Equal(t, 0, calls)
+
+	for _, tags := range []string{"mod=1", "badges=moderator/1", "badges=broadcaster/1"} {
+		assert.NoError(t, r.Handle(ctx, privmsg(t, "@"+tags+" :a!a@a PRIVMSG #forsen :?ban")))
+	}
+	assert.Equal(t, 3, calls)
+}
+
+func TestRouterCooldowns(t *testing.T) {
+	ctx := context.Background()
+	r := NewRouter(nil)
+	now := time.Unix(0, 0)
+	r.now = func() time.Time { return now }
+
+	calls := map[string]int{}
+	assert.NoError(t, r.Register(&Command{
+		Name:         "ping",
+		Cooldown:     5 * time.Second,
+		UserCooldown: 30 * time.Second,
+		Handler: func(_ context.Context, inv *Invocation) error {
+			calls[inv.Message.User.ID+"@"+inv.Message.RoomID]++
+			return nil
+		},
+	}))
+
+	handle := func(userID, roomID string) {
+		assert.NoError(t, r.Handle(ctx, privmsg(t, "@user-id="+userID+";room-id="+roomID+" :a!a@a PRIVMSG #a :!ping")))
+	}
+
+	handle("1", "100")
+	handle("2", "100") // channel cooldown
+	handle("2", "200") // other channels have their own cooldown
+	assert.Equal(t, 1, calls["1@100"])
+	assert.Equal(t, 0, calls["2@100"])
+	assert.Equal(t, 1, calls["2@200"])
+
+	now = now.Add(10 * time.Second)
+	handle("1", "100") // user cooldown
+	handle("2", "100")
+	assert.Equal(t, 1, calls["1@100"])
+	assert.Equal(t, 1, calls["2@100"])
+
+	now = now.Add(time.Minute)
+	handle("1", "100")
+	assert.Equal(t, 2, calls["1@100"])
+}
+
+func TestRouterRegister(t *testing.T) {
+	handler := func(context.Context, *Invocation) error { return nil }
+	r := NewRouter(nil)
+
+	assert.NoError(t, r.Register(&Command{Name: "a", Aliases: []string{"b"}, Handler: handler}))
+	assert.ErrorIs(t, r.Register(&Command{Name: "B", Handler: handler}), ErrDuplicateCommand)
+	assert.ErrorIs(t, r.Register(&Command{Name: "c", Handler: handler}, &Command{Name: "c", Handler: handler}), ErrDuplicateCommand)
+	assert.ErrorIs(t, r.Register(&Command{Name: "c"}), ErrInvalidCommand)
+	assert.ErrorIs(t, r.Register(&Command{Name: "c d", Handler: handler}), ErrInvalidCommand)
+	assert.ErrorIs(t, r.Register(&Command{Handler: handler}), ErrInvalidCommand)
+
+	// failed registrations register nothing
+	assert.Equal(t, 1, len(r.Commands()))
+	assert.NoError(t, r.Register(&Command{Name: "c", Handler: handler}))
+}
+
+func TestRouterHandleMessage(t *testing.T) {
+	r := NewRouter(nil)
+	failure := errors.New("failure")
+	assert.NoError(t, r.Register(&Command{
+		Name: "fail",
+		Handler: func(ctx context.Context, inv *Invocation) error {
+			return failure
+		},
+	}, &Command{
+		Name: "reply",
+		Handler: func(ctx context.Context, inv *Invocation) error {
+			return inv.Say(ctx, "hi")
+		},
+	}))
+
+	var errs []error
+	r.OnError(func(err error) {
+		errs = append(errs, err)
+	})
+
+	for _, line := range []string{":a!a@a PRIVMSG #a :!fail", ":a!a@a PRIVMSG #a :!reply", ":a!a@a NOTICE #a :!fail"} {
+		msg, err := irc.Parse(line)
+		assert.NoError(t, err)
+		r.HandleMessage(context.Background(), msg)
+	}
+	assert.Equal(t, 2, len(errs))
+	assert.ErrorIs(t, errs[0], failure)
+	assert.ErrorIs(t, errs[1], ErrMissingSender)
+}
+
+func TestParseArgs(t *testing.T) {
+	tests := map[string]string{
+		"":                      "",
+		"  a   b ":              "a|b",
+		`"a b" c`:               "a b|c",
+		`a"b c"d`:               "ab cd",
+		`"" x`:                  "|x",
+		`"unterminated quote  `: "unterminated quote  ",
+	}
+	for in, expected := range tests {
+		assert.Equal(t, expected, strings.Join(ParseArgs(in), "|"))
+	}
+}
+
+func TestPermissionString(t *testing.T) {
+	assert.Equal(t, "vip", PermissionVIP.String())
+	assert.Equal(t, "Permission(9)", Permission(9).String())
+}
@@ -0,0 +1,146 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aidenwallis/go-twitch-client/chat"
+	"github.com/aidenwallis/go-twitch-client/helix"
+)
+
+var (
+	// ErrMissingSender is returned when replying to a command of a router without a sender.
+	ErrMissingSender = errors.New("commands: router has no sender")
+
+	// ErrMessageDropped is returned by HelixSender when Twitch accepted the request, but did not send the message,
+	// such as when it is a duplicate or caught by AutoMod.
+	ErrMessageDropped = errors.New("commands: message was dropped")
+)
+
+// OutgoingMessage is a message sent by a command
+type OutgoingMessage struct {
+	// Channel is the login of the channel to send the message to.
+	Channel string
+
+	// BroadcasterID is the user ID of the channel to send the message to.
+	BroadcasterID string
+
+	// Text is the message text.
+	Text string
+
+	// ReplyParentMessageID is the ID of the message this message replies to, empty when it is not a reply.
+	ReplyParentMessageID string
+}
+
+// Sender sends the messages of commands to chat
+type Sender interface {
+	Send(ctx context.Context, msg *OutgoingMessage) error
+}
+
+// ChatSender sends messages through a chat connection, as the user the client is logged in as
+type ChatSender struct {
+	client *chat.Client
+}
+
+var _ Sender = (*ChatSender)(nil)
+
+// NewChatSender creates a new instance of ChatSender
+func NewChatSender(client *chat.Client) *ChatSender {
+	return &ChatSender{client: client}
+}
+
+// Send sends the message, blocking while the rate limit of the client is exceeded.
+func (s *ChatSender) Send(ctx context.Context, msg *OutgoingMessage) error {
+	if msg.ReplyParentMessageID != "" {
+		return s.client.Reply(ctx, msg.Channel, msg.ReplyParentMessageID, msg.Text)
+	}
+	return s.client.Say(ctx, msg.Channel, msg.Text)
+}
+
+// HelixSenderOptions defines all options the Helix sender supports.
+type HelixSenderOptions struct {
+	// Helix is used to send the messages.
+	Helix helix.Client
+
+	// SenderID (optional) is the user ID of the bot account, it may be left empty when
+	// helix.ClientOptions.ResolveActingUser is enabled and the client sends with a user access token.
+	SenderID string
+
+	// MessagePreparer (optional) prepares the text of every message, which may then send several messages.
+	MessagePreparer *chat.MessagePreparer
+}
+
+// HelixSender sends messages through the Helix Send Chat Message endpoint
+type HelixSender struct {
+	helix    helix.Client
+	senderID string
+	preparer *chat.MessagePreparer
+}
+
+var _ Sender = (*HelixSender)(nil)
+
+// NewHelixSender creates a new instance of HelixSender
+func NewHelixSender(options *HelixSenderOptions) *HelixSender {
+	if options == nil {
+		options = &HelixSenderOptions{}
+	}
+
+	return &HelixSender{
+		helix:    options.Helix,
+		senderID: options.SenderID,
+		preparer: options.MessagePreparer,
+	}
+}
+
+// Send sends the message, ErrMessageDropped is returned when Twitch does not send it. With a MessagePreparer, every
+// prepared message is sent in order.
+func (s *HelixSender) Send(ctx context.Context, msg *OutgoingMessage) error {
+	if s.helix == nil {
+		return chat.ErrMissingHelix
+	}
+
+	texts := []string{msg.Text}
+	if s.preparer != nil {
+		channel := msg.Channel
+		if channel == "" {
+			channel = msg.BroadcasterID
+		}
+
+		var err error
+		if texts, err = s.preparer.Prepare(channel, msg.Text); err != nil {
+			return err
+		}
+	}
+
+	for _, text := range texts {
+		if err := s.send(ctx, msg, text); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// send sends a single message with the text.
+func (s *HelixSender) send(ctx context.Context, msg *OutgoingMessage, text string) error {
+	resp, err := s.helix.SendChatMessage(ctx, &helix.SendChatMessageRequest{
+		BroadcasterID:        msg.BroadcasterID,
+		SenderID:             s.senderID,
+		Message:              text,
+		ReplyParentMessageID: msg.ReplyParentMessageID,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, sent := range resp.Data {
+		if sent.IsSent {
+			continue
+		}
+		if sent.DropReason != nil {
+			return fmt.Errorf("%w: %s (%s)", ErrMessageDropped, sent.DropReason.Message, sent.DropReason.Code)
+		}
+		return ErrMessageDropped
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package commands
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/aidenwallis/go-twitch-client/chat"
+	"github.com/aidenwallis/go-twitch-client/helix"
+	"github.com/aidenwallis/go-twitch-client/internal/testutils"
+	"github.com/aidenwallis/go-twitch-client/internal/testutils/assert"
+)
+
+func TestHelixSender(t *testing.T) {
+	sent := true
+	client := helix.NewClient(&helix.ClientOptions{
+		ClientID: "clientID",
+		Transport: testutils.Middleware(func(req *http.Request) *testutils.Response {
+			assert.Equal(t, `{"broadcaster_id":"1","sender_id":"2","message":"hi","reply_parent_message_id":"m1"}`, testutils.DecodeRawBody(t, req))
+
+			message := &helix.SentChatMessage{MessageID: "m2", IsSent: sent}
+			if !sent {
+				message.DropReason = &helix.ChatMessageDropReason{Code: "msg_duplicate", Message: "duplicate"}
+			}
+			return testutils.JSONResponse(t, http.StatusOK, &helix.SendChatMessageResponse{Data: []*helix.SentChatMessage{message}})
+		}),
+	})
+
+	s := NewHelixSender(&HelixSenderOptions{Helix: client, SenderID: "2"})
+	msg := &OutgoingMessage{Channel: "forsen", BroadcasterID: "1", Text: "hi", ReplyParentMessageID: "m1"}
+	assert.NoError(t, s.Send(context.Background(), msg))
+
+	sent = false
+	err := s.Send(context.Background(), msg)
+	assert.ErrorIs(t, err, ErrMessageDropped)
+	assert.Equal(t, "commands: message was dropped: duplicate (msg_duplicate)", err.Error())
+
+	assert.ErrorIs(t, NewHelixSender(nil).Send(context.Background(), msg), chat.ErrMissingHelix)
+}
+
+func TestChatSender(t *testing.T) {
+	// the chat sender fails without a connection, rather than blocking
+	s := NewChatSender(chat.NewClient(nil))
+	assert.ErrorIs(t, s.Send(context.Background(), &OutgoingMessage{Channel: "forsen", Text: "hi"}), chat.ErrNotConnected)
+	assert.ErrorIs(t, s.Send(context.Background(), &OutgoingMessage{Channel: "forsen", Text: "hi", ReplyParentMessageID: "m1"}), chat.ErrNotConnected)
+}
+
+func TestHelixSenderMessagePreparer(t *testing.T) {
+	var messages []string
+	client := helix.NewClient(&helix.ClientOptions{
+		ClientID: "clientID",
+		Transport: testutils.Middleware(func(req *http.Request) *testutils.Response {
+			messages = append(messages, testutils.DecodeRawBody(t, req))
+			return testutils.JSONResponse(t, http.StatusOK, &helix.SendChatMessageResponse{Data: []*helix.SentChatMessage{{IsSent: true}}})
+		}),
+	})
+
+	s := NewHelixSender(&HelixSenderOptions{
+		Helix:           client,
+		SenderID:        "2",
+		MessagePreparer: chat.NewMessagePreparer(&chat.MessagePreparerOptions{MaxLength: 5}),
+	})
+	assert.NoError(t, s.Send(context.Background(), &OutgoingMessage{BroadcasterID: "1", Text: "hello world"}))
+	assert.Equal(t, 2, len(messages))
+	assert.Equal(t, `{"broadcaster_id":"1","sender_id":"2","message":"world"}`, messages[1])
+
+	assert.ErrorIs(t, s.Send(context.Background(), &OutgoingMessage{BroadcasterID: "1", Text: "\n"}), helix.ErrEmptyChatMessage)
+	assert.Equal(t, 2, len(messages))
+}
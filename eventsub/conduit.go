@@ -0,0 +1,121 @@
+package eventsub
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/aidenwallis/go-twitch-client/helix"
+)
+
+// ErrNoConduitSessions is returned by RebalanceConduitShards when no sessions were given, conduits require at least
+// one shard.
+var ErrNoConduitSessions = errors.New("eventsub: rebalancing a conduit requires at least one session")
+
+// RebalanceResult is the outcome of RebalanceConduitShards
+type RebalanceResult struct {
+	// ShardCount is the number of shards of the conduit after rebalancing.
+	ShardCount int
+
+	// Updated contains the shards that were assigned a new session.
+	Updated []*helix.ConduitShard
+
+	// Errors contains the shards that could not be assigned a new session, and why. The remaining shards are still
+	// updated, so rebalancing can simply be run again.
+	Errors []*helix.ConduitShardError
+}
+
+// RebalanceConduitShards resizes the conduit to one shard per WebSocket session, and assigns the sessions to its
+// shards. Sessions that are already connected to a shard that is kept stay on it, so only shards whose session went
+// away, or that are new, are updated.
+//
+// Call it whenever worker sessions come and go. The helix client must use an app access token.
+func RebalanceConduitShards(ctx context.Context, client helix.Client, conduitID string, sessionIDs []string) (*RebalanceResult, error) {
+	if len(sessionIDs) == 0 {
+		return nil, ErrNoConduitSessions
+	}
+
+	shards, err := conduitShards(ctx, client, conduitID)
+	if err != nil {
+		return nil, err
+	}
+
+	shardCount := len(sessionIDs)
+	live := make(map[string]bool, len(sessionIDs))
+	for _, id := range sessionIDs {
+		live[id] = true
+	}
+
+	// keep sessions on the shards they are connected to, as long as the shard is kept
+	assigned := make(map[string]bool, len(sessionIDs))
+	occupied := make([]bool, shardCount)
+	for _, shard := range shards {
+		index, err := strconv.Atoi(shard.ID)
+		if err != nil || index < 0 || index >= shardCount || occupied[index] {
+			continue
+		}
+
+		sessionID := shard.Transport.SessionID
+		if shard.Status != helix.EventSubStatusEnabled || shard.Transport.Method != helix.EventSubTransportWebSocket ||
+			!live[sessionID] || assigned[sessionID] {
+			continue
+		}
+		occupied[index] = true
+		assigned[sessionID] = true
+	}
+
+	var updates []*helix.ConduitShardUpdate
+	index := 0
+	for _, sessionID := range sessionIDs {
+		if assigned[sessionID] {
+			continue
+		}
+		assigned[sessionID] = true
+
+		for occupied[index] {
+			index++
+		}
+		occupied[index] = true
+		updates = append(updates, &helix.ConduitShardUpdate{
+			ID:        strconv.Itoa(index),
+			Transport: helix.WebSocketTransport(sessionID),
+		})
+	}
+
+	if len(shards) != shardCount {
+		if _, err := client.UpdateConduits(ctx, &helix.UpdateConduitsRequest{ID: conduitID, ShardCount: shardCount}); err != nil {
+			return nil, err
+		}
+	}
+
+	result := &RebalanceResult{ShardCount: shardCount}
+	if len(updates) == 0 {
+		return result, nil
+	}
+
+	resp, err := client.UpdateConduitShards(ctx, &helix.UpdateConduitShardsRequest{ConduitID: conduitID, Shards: updates})
+	if err != nil {
+		return nil, err
+	}
+	result.Updated = resp.Data
+	result.Errors = resp.Errors
+	return result, nil
+}
+
+// conduitShards returns every shard of the conduit, following pagination.
+func conduitShards(ctx context.Context, client helix.Client, conduitID string) ([]*helix.ConduitShard, error) {
+	var shards []*helix.ConduitShard
+	cursor := ""
+	for {
+		resp, err := client.GetConduitShards(ctx, &helix.GetConduitShardsRequest{ConduitID: conduitID, After: cursor})
+		if err != nil {
+			return nil, err
+		}
+
+		shards = append(shards, resp.Data...)
+		if resp.Pagination.Cursor == "" || len(resp.Data) == 0 {
+			return shards, nil
+		}
+		cursor = resp.Pagination.Cursor
+	}
+}
@@ -0,0 +1,152 @@
+package eventsub
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/aidenwallis/go-twitch-client/helix"
+	"github.com/aidenwallis/go-twitch-client/internal/testutils"
+	"github.com/aidenwallis/go-twitch-client/internal/testutils/assert"
+)
+
+// conduitAPI fakes the conduit endpoints, serving the shards over two pages
+type conduitAPI struct {
+	t       *testing.T
+	shards  []*helix.ConduitShard
+	resized int
+	updates []*helix.ConduitShardUpdate
+	errors  []*helix.ConduitShardError
+}
+
+func (a *conduitAPI) client() helix.Client {
+	return helix.NewClient(&helix.ClientOptions{
+		ClientID: "clientID",
+		Transport: testutils.Middleware(func(req *http.Request) *testutils.Response {
+			switch req.Method + " " + testutils.WithoutQuery(req.URL) {
+			case "GET https://api.twitch.tv/helix/eventsub/conduits/shards":
+				assert.Equal(a.t, "conduit", req.URL.Query().Get("conduit_id"))
+				if len(a.shards) <= 1 {
+					return testutils.JSONResponse(a.t, http.StatusOK, &helix.GetConduitShardsResponse{Data: a.shards})
+				}
+				if req.URL.Query().Get("after") == "" {
+					return testutils.JSONResponse(a.t, http.StatusOK, &helix.GetConduitShardsResponse{
+						Data:       a.shards[:1],
+						Pagination: helix.Pagination{Cursor: "page"},
+					})
+				}
+				assert.Equal(a.t, "page", req.URL.Query().Get("after"))
+				return testutils.JSONResponse(a.t, http.StatusOK, &helix.GetConduitShardsResponse{Data: a.shards[1:]})
+
+			case "PATCH https://api.twitch.tv/helix/eventsub/conduits":
+				var body struct {
+					ShardCount int `json:"shard_count"`
+				}
+				assert.NoError(a.t, json.Unmarshal([]byte(testutils.DecodeRawBody(a.t, req)), &body))
+				a.resized = body.ShardCount
+				return testutils.JSONResponse(a.t, http.StatusOK, &helix.UpdateConduitsResponse{})
+
+			case "PATCH https://api.twitch.tv/helix/eventsub/conduits/shards":
+				var body struct {
+					Shards []*helix.ConduitShardUpdate `json:"shards"`
+				}
+				assert.NoError(a.t, json.Unmarshal([]byte(testutils.DecodeRawBody(a.t, req)), &body))
+				a.updates = body.Shards
+
+				resp := &helix.UpdateConduitShardsResponse{Errors: a.errors}
+				for _, update := range body.Shards {
+					resp.Data = append(resp.Data, &helix.ConduitShard{ID: update.ID, Status: helix.EventSubStatusEnabled, Transport: *update.Transport})
+				}
+				return testutils.JSONResponse(a.t, http.StatusAccepted, resp)
+			}
+
+			a.t.Errorf("unexpected request %s %s", req.Method, req.URL)
+			return testutils.EmptyResponse(http.StatusNotFound)
+		}),
+		AccessTokenLoader: func(ctx context.Context) (string, error) {
+			return "abc123", nil
+		},
+	})
+}
+
+func shard(id, status, sessionID string) *helix.ConduitShard {
+	return &helix.ConduitShard{ID: id, Status: status, Transport: *helix.WebSocketTransport(sessionID)}
+}
+
+func TestRebalanceConduitShards(t *testing.T) {
+	ctx := context.Background()
+
+	existing := func() []*helix.ConduitShard {
+		return []*helix.ConduitShard{
+			shard("0", helix.EventSubStatusEnabled, "a"),
+			shard("1", helix.EventSubStatusWebSocketDisconnected, "b"),
+			shard("2", helix.EventSubStatusEnabled, "c"),
+		}
+	}
+
+	t.Run("replaces dead sessions", func(t *testing.T) {
+		api := &conduitAPI{t: t, shards: existing()}
+
+		result, err := RebalanceConduitShards(ctx, api.client(), "conduit", []string{"a", "c", "d"})
+		assert.NoError(t, err)
+		assert.Equal(t, 3, result.ShardCount)
+		assert.Equal(t, 0, api.resized)
+		assert.Equal(t, 1, len(api.updates))
+		assert.Equal(t, "1", api.updates[0].ID)
+		assert.Equal(t, "d", api.updates[0].Transport.SessionID)
+		assert.Equal(t, 1, len(result.Updated))
+	})
+
+	t.Run("shrinks conduit", func(t *testing.T) {
+		api := &conduitAPI{t: t, shards: existing()}
+
+		result, err := RebalanceConduitShards(ctx, api.client(), "conduit", []string{"c", "e"})
+		assert.NoError(t, err)
+		assert.Equal(t, 2, result.ShardCount)
+		assert.Equal(t, 2, api.resized)
+		assert.Equal(t, 2, len(api.updates))
+		assert.Equal(t, "0", api.updates[0].ID)
+		assert.Equal(t, "c", api.updates[0].Transport.SessionID)
+		assert.Equal(t, "1", api.updates[1].ID)
+		assert.Equal(t, "e", api.updates[1].Transport.SessionID)
+	})
+
+	t.Run("grows conduit", func(t *testing.T) {
+		api := &conduitAPI{t: t, shards: existing()}
+
+		_, err := RebalanceConduitShards(ctx, api.client(), "conduit", []string{"a", "b", "c", "d"})
+		assert.NoError(t, err)
+		assert.Equal(t, 4, api.resized)
+		assert.Equal(t, 2, len(api.updates))
+		assert.Equal(t, "1", api.updates[0].ID)
+		assert.Equal(t, "b", api.updates[0].Transport.SessionID)
+		assert.Equal(t, "3", api.updates[1].ID)
+		assert.Equal(t, "d", api.updates[1].Transport.SessionID)
+	})
+
+	t.Run("balanced", func(t *testing.T) {
+		api := &conduitAPI{t: t, shards: existing()[:1]}
+
+		result, err := RebalanceConduitShards(ctx, api.client(), "conduit", []string{"a"})
+		assert.NoError(t, err)
+		assert.Equal(t, 0, len(api.updates))
+		assert.Equal(t, 0, len(result.Updated))
+	})
+
+	t.Run("partial failure", func(t *testing.T) {
+		api := &conduitAPI{t: t, shards: existing(), errors: []*helix.ConduitShardError{
+			{ID: "1", Message: "The websocket session does not exist.", Code: "websocket_session_not_found"},
+		}}
+
+		result, err := RebalanceConduitShards(ctx, api.client(), "conduit", []string{"a", "c", "d"})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, len(result.Errors))
+		assert.Equal(t, "websocket_session_not_found", result.Errors[0].Code)
+	})
+
+	t.Run("no sessions", func(t *testing.T) {
+		_, err := RebalanceConduitShards(ctx, (&conduitAPI{t: t}).client(), "conduit", nil)
+		assert.ErrorIs(t, err, ErrNoConduitSessions)
+	})
+}
@@ -0,0 +1,191 @@
+package eventsub
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/aidenwallis/go-twitch-client/helix"
+)
+
+// Dispatcher decodes notifications into their typed event payloads, and routes them to the handler registered for
+// their subscription type and version. Its Handle method can be registered as a NotificationHandler, for example
+// with WebhookHandler.OnNotification.
+//
+// Handlers must be registered before the Dispatcher starts handling notifications.
+type Dispatcher struct {
+	handlers map[dispatchKey]NotificationHandler
+	raw      NotificationHandler
+}
+
+// dispatchKey identifies a subscription type at a specific version
+type dispatchKey struct {
+	subscriptionType string
+	version          string
+}
+
+// NewDispatcher creates a new instance of Dispatcher
+func NewDispatcher() *Dispatcher {
+	return &Dispatcher{handlers: map[dispatchKey]NotificationHandler{}}
+}
+
+// Handle decodes the notification event and dispatches it to the handler registered for its subscription type and
+// version. Notifications without a registered handler are passed to the raw handler, if one is set, otherwise they
+// are dropped. An error is returned if the event could not be decoded.
+func (d *Dispatcher) Handle(ctx context.Context, notification *Notification) error {
+	if notification.Subscription != nil {
+		key := dispatchKey{notification.Subscription.Type, notification.Subscription.Version}
+		if handler, ok := d.handlers[key]; ok {
+			return handler(ctx, notification)
+		}
+	}
+
+	if d.raw != nil {
+		return d.raw(ctx, notification)
+	}
+	return nil
+}
+
+// OnRaw sets the fallback handler, called with notifications for subscription types and versions that have no
+// typed handler registered.
+func (d *Dispatcher) OnRaw(handler NotificationHandler) {
+	d.raw = handler
+}
+
+// on registers a typed handler for the subscription type and version of condition.
+func on[T any](d *Dispatcher, condition helix.EventSubCondition, handler func(ctx context.Context, event *T)) {
+	d.handlers[dispatchKey{condition.EventSubType(), condition.EventSubVersion()}] = func(ctx context.Context, notification *Notification) error {
+		event := new(T)
+		if err := json.Unmarshal(notification.Event, event); err != nil {
+			return err
+		}
+		handler(ctx, event)
+		return nil
+	}
+}
+
+// OnChannelUpdate registers the handler for channel.update version 2 notifications.
+func (d *Dispatcher) OnChannelUpdate(handler func(ctx context.Context, event *ChannelUpdateEvent)) {
+	on(d, &helix.ChannelUpdateCondition{}, handler)
+}
+
+// OnChannelFollow registers the handler for channel.follow version 2 notifications.
+func (d *Dispatcher) OnChannelFollow(handler func(ctx context.Context, event *ChannelFollowEvent)) {
+	on(d, &helix.ChannelFollowCondition{}, handler)
+}
+
+// OnChannelSubscribe registers the handler for channel.subscribe version 1 notifications.
+func (d *Dispatcher) OnChannelSubscribe(handler func(ctx context.Context, event *ChannelSubscribeEvent)) {
+	on(d, &helix.ChannelSubscribeCondition{}, handler)
+}
+
+// OnChannelSubscriptionGift registers the handler for channel.subscription.gift version 1 notifications.
+func (d *Dispatcher) OnChannelSubscriptionGift(handler func(ctx context.Context, event *ChannelSubscriptionGiftEvent)) {
+	on(d, &helix.ChannelSubscriptionGiftCondition{}, handler)
+}
+
+// OnChannelSubscriptionMessage registers the handler for channel.subscription.message version 1 notifications.
+func (d *Dispatcher) OnChannelSubscriptionMessage(handler func(ctx context.Context, event *ChannelSubscriptionMessageEvent)) {
+	on(d, &helix.ChannelSubscriptionMessageCondition{}, handler)
+}
+
+// OnChannelCheer registers the handler for channel.cheer version 1 notifications.
+func (d *Dispatcher) OnChannelCheer(handler func(ctx context.Context, event *ChannelCheerEvent)) {
+	on(d, &helix.ChannelCheerCondition{}, handler)
+}
+
+// OnChannelRaid registers the handler for channel.raid version 1 notifications.
+func (d *Dispatcher) OnChannelRaid(handler func(ctx context.Context, event *ChannelRaidEvent)) {
+	on(d, &helix.ChannelRaidCondition{}, handler)
+}
+
+// OnChannelBan registers the handler for channel.ban version 1 notifications.
+func (d *Dispatcher) OnChannelBan(handler func(ctx context.Context, event *ChannelBanEvent)) {
+	on(d, &helix.ChannelBanCondition{}, handler)
+}
+
+// OnChannelUnban registers the handler for channel.unban version 1 notifications.
+func (d *Dispatcher) OnChannelUnban(handler func(ctx context.Context, event *ChannelUnbanEvent)) {
+	on(d, &helix.ChannelUnbanCondition{}, handler)
+}
+
+// OnStreamOnline registers the handler for stream.online version 1 notifications.
+func (d *Dispatcher) OnStreamOnline(handler func(ctx context.Context, event *StreamOnlineEvent)) {
+	on(d, &helix.StreamOnlineCondition{}, handler)
+}
+
+// OnStreamOffline registers the handler for stream.offline version 1 notifications.
+func (d *Dispatcher) OnStreamOffline(handler func(ctx context.Context, event *StreamOfflineEvent)) {
+	on(d, &helix.StreamOfflineCondition{}, handler)
+}
+
+// OnChannelPointsCustomRewardRedemptionAdd registers the handler for
+// channel.channel_points_custom_reward_redemption.add version 1 notifications.
+func (d *Dispatcher) OnChannelPointsCustomRewardRedemptionAdd(handler func(ctx context.Context, event *ChannelPointsCustomRewardRedemptionEvent)) {
+	on(d, &helix.ChannelPointsCustomRewardRedemptionAddCondition{}, handler)
+}
+
+// OnChannelPointsCustomRewardRedemptionUpdate registers the handler for
+// channel.channel_points_custom_reward_redemption.update version 1 notifications.
+func (d *Dispatcher) OnChannelPointsCustomRewardRedemptionUpdate(handler func(ctx context.Context, event *ChannelPointsCustomRewardRedemptionEvent)) {
+	on(d, &helix.ChannelPointsCustomRewardRedemptionUpdateCondition{}, handler)
+}
+
+// OnChannelPollBegin registers the handler for channel.poll.begin version 1 notifications.
+func (d *Dispatcher) OnChannelPollBegin(handler func(ctx context.Context, event *ChannelPollEvent)) {
+	on(d, &helix.ChannelPollBeginCondition{}, handler)
+}
+
+// OnChannelPollProgress registers the handler for channel.poll.progress version 1 notifications.
+func (d *Dispatcher) OnChannelPollProgress(handler func(ctx context.Context, event *ChannelPollEvent)) {
+	on(d, &helix.ChannelPollProgressCondition{}, handler)
+}
+
+// OnChannelPollEnd registers the handler for channel.poll.end version 1 notifications.
+func (d *Dispatcher) OnChannelPollEnd(handler func(ctx context.Context, event *ChannelPollEvent)) {
+	on(d, &helix.ChannelPollEndCondition{}, handler)
+}
+
+// OnChannelPredictionBegin registers the handler for channel.prediction.begin version 1 notifications.
+func (d *Dispatcher) OnChannelPredictionBegin(handler func(ctx context.Context, event *ChannelPredictionEvent)) {
+	on(d, &helix.ChannelPredictionBeginCondition{}, handler)
+}
+
+// OnChannelPredictionProgress registers the handler for channel.prediction.progress version 1 notifications.
+func (d *Dispatcher) OnChannelPredictionProgress(handler func(ctx context.Context, event *ChannelPredictionEvent)) {
+	on(d, &helix.ChannelPredictionProgressCondition{}, handler)
+}
+
+// OnChannelPredictionLock registers the handler for channel.prediction.lock version 1 notifications.
+func (d *Dispatcher) OnChannelPredictionLock(handler func(ctx context.Context, event *ChannelPredictionEvent)) {
+	on(d, &helix.ChannelPredictionLockCondition{}, handler)
+}
+
+// OnChannelPredictionEnd registers the handler for channel.prediction.end version 1 notifications.
+func (d *Dispatcher) OnChannelPredictionEnd(handler func(ctx context.Context, event *ChannelPredictionEvent)) {
+	on(d, &helix.ChannelPredictionEndCondition{}, handler)
+}
+
+// OnChannelHypeTrainBegin registers the handler for channel.hype_train.begin version 1 notifications.
+func (d *Dispatcher) OnChannelHypeTrainBegin(handler func(ctx context.Context, event *ChannelHypeTrainEvent)) {
+	on(d, &helix.ChannelHypeTrainBeginCondition{}, handler)
+}
+
+// OnChannelHypeTrainProgress registers the handler for channel.hype_train.progress version 1 notifications.
+func (d *Dispatcher) OnChannelHypeTrainProgress(handler func(ctx context.Context, event *ChannelHypeTrainEvent)) {
+	on(d, &helix.ChannelHypeTrainProgressCondition{}, handler)
+}
+
+// OnChannelHypeTrainEnd registers the handler for channel.hype_train.end version 1 notifications.
+func (d *Dispatcher) OnChannelHypeTrainEnd(handler func(ctx context.Context, event *ChannelHypeTrainEvent)) {
+	on(d, &helix.ChannelHypeTrainEndCondition{}, handler)
+}
+
+// OnChannelChatMessage registers the handler for channel.chat.message version 1 notifications.
+func (d *Dispatcher) OnChannelChatMessage(handler func(ctx context.Context, event *ChannelChatMessageEvent)) {
+	on(d, &helix.ChannelChatMessageCondition{}, handler)
+}
+
+// OnChannelChatSettingsUpdate registers the handler for channel.chat_settings.update version 1 notifications.
+func (d *Dispatcher) OnChannelChatSettingsUpdate(handler func(ctx context.Context, event *ChannelChatSettingsUpdateEvent)) {
+	on(d, &helix.ChannelChatSettingsUpdateCondition{}, handler)
+}
@@ -0,0 +1,130 @@
+package eventsub
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/aidenwallis/go-twitch-client/helix"
+	"github.com/aidenwallis/go-twitch-client/internal/testutils/assert"
+)
+
+func notification(subscriptionType, version, event string) *Notification {
+	return &Notification{
+		MessageID:    "msg",
+		MessageType:  MessageTypeNotification,
+		Subscription: &helix.EventSubSubscription{Type: subscriptionType, Version: version},
+		Event:        json.RawMessage(event),
+	}
+}
+
+func TestDispatcher(t *testing.T) {
+	t.Run("typed handler", func(t *testing.T) {
+		var got *ChannelFollowEvent
+		d := NewDispatcher()
+		d.OnChannelFollow(func(ctx context.Context, event *ChannelFollowEvent) {
+			got = event
+		})
+
+		err := d.Handle(context.Background(), notification(helix.EventSubTypeChannelFollow, "2", `{"user_id":"1","user_login":"forsen","user_name":"Forsen","broadcaster_user_id":"2","broadcaster_user_login":"xqc","broadcaster_user_name":"xQc","followed_at":"2022-01-01T00:00:00Z"}`))
+		assert.NoError(t, err)
+		assert.Equal(t, "1", got.UserID)
+		assert.Equal(t, "forsen", got.UserLogin)
+		assert.Equal(t, "xQc", got.BroadcasterUserName)
+		assert.Equal(t, 2022, got.FollowedAt.Year())
+	})
+
+	t.Run("routes on version", func(t *testing.T) {
+		typed, raw := 0, 0
+		d := NewDispatcher()
+		d.OnChannelFollow(func(ctx context.Context, event *ChannelFollowEvent) {
+			typed++
+		})
+		d.OnRaw(func(ctx context.Context, n *Notification) error {
+			raw++
+			assert.Equal(t, "1", n.Subscription.Version)
+			return nil
+		})
+
+		assert.NoError(t, d.Handle(context.Background(), notification(helix.EventSubTypeChannelFollow, "1", `{}`)))
+		assert.Equal(t, 0, typed)
+		assert.Equal(t, 1, raw)
+	})
+
+	t.Run("unknown type without raw handler", func(t *testing.T) {
+		assert.NoError(t, NewDispatcher().Handle(context.Background(), notification("channel.unknown", "1", `{}`)))
+	})
+
+	t.Run("malformed event", func(t *testing.T) {
+		d := NewDispatcher()
+		d.OnStreamOnline(func(ctx context.Context, event *StreamOnlineEvent) {
+			t.Fatal("handler should not be called")
+		})
+		assert.Equal(t, true, d.Handle(context.Background(), notification(helix.EventSubTypeStreamOnline, "1", `[]`)) != nil)
+	})
+
+	t.Run("webhook handler", func(t *testing.T) {
+		var got *StreamOnlineEvent
+		d := NewDispatcher()
+		d.OnStreamOnline(func(ctx context.Context, event *StreamOnlineEvent) {
+			got = event
+		})
+
+		h := NewWebhookHandler(&WebhookHandlerOptions{Secret: fakeSecret})
+		h.OnNotification(d.Handle)
+		serve(h, webhookRequest(fakeSecret, MessageTypeNotification, "msg", time.Now(), notificationBody))
+		assert.Equal(t, "1", got.ID)
+		assert.Equal(t, "1", got.BroadcasterUserID)
+	})
+}
+
+func TestEventConversions(t *testing.T) {
+	t.Run("channel update", func(t *testing.T) {
+		d := NewDispatcher()
+		var channel *helix.Channel
+		d.OnChannelUpdate(func(ctx context.Context, event *ChannelUpdateEvent) {
+			channel = event.Channel()
+		})
+
+		assert.NoError(t, d.Handle(context.Background(), notification(helix.EventSubTypeChannelUpdate, "2", `{"broadcaster_user_id":"1","broadcaster_user_login":"forsen","broadcaster_user_name":"Forsen","title":"title","language":"en","category_id":"2","category_name":"Minecraft","content_classification_labels":["MatureGame"]}`)))
+		assert.Equal(t, "1", channel.BroadcasterID)
+		assert.Equal(t, "forsen", channel.BroadcasterLogin)
+		assert.Equal(t, "Forsen", channel.BroadcasterName)
+		assert.Equal(t, "title", channel.Title)
+		assert.Equal(t, "en", channel.BroadcasterLanguage)
+		assert.Equal(t, "2", channel.GameID)
+		assert.Equal(t, "Minecraft", channel.GameName)
+	})
+
+	t.Run("chat settings update", func(t *testing.T) {
+		d := NewDispatcher()
+		var settings *helix.ChatSettings
+		d.OnChannelChatSettingsUpdate(func(ctx context.Context, event *ChannelChatSettingsUpdateEvent) {
+			settings = event.ChatSettings()
+		})
+
+		assert.NoError(t, d.Handle(context.Background(), notification(helix.EventSubTypeChannelChatSettingsUpdate, "1", `{"broadcaster_user_id":"1","emote_mode":true,"follower_mode":false,"follower_mode_duration_minutes":null,"slow_mode":true,"slow_mode_wait_time_seconds":10,"subscriber_mode":false,"unique_chat_mode":false}`)))
+		assert.Equal(t, true, settings.EmoteMode)
+		assert.Equal(t, true, settings.SlowMode)
+		assert.Equal(t, false, settings.FollowerMode)
+		assert.Equal(t, 10, *settings.SlowModeWaitTime)
+		assert.Equal(t, true, settings.FollowerModeDuration == nil)
+	})
+
+	t.Run("chat message", func(t *testing.T) {
+		d := NewDispatcher()
+		var got *ChannelChatMessageEvent
+		d.OnChannelChatMessage(func(ctx context.Context, event *ChannelChatMessageEvent) {
+			got = event
+		})
+
+		assert.NoError(t, d.Handle(context.Background(), notification(helix.EventSubTypeChannelChatMessage, "1", `{"broadcaster_user_id":"1","chatter_user_id":"2","chatter_user_login":"forsen","message_id":"abc","message":{"text":"Kappa hi","fragments":[{"type":"emote","text":"Kappa","emote":{"id":"25","emote_set_id":"0","format":["static"]}},{"type":"text","text":" hi"}]},"message_type":"text","badges":[{"set_id":"moderator","id":"1","info":""}],"cheer":null,"color":"#FF0000","reply":null}`)))
+		assert.Equal(t, "forsen", got.ChatterUserLogin)
+		assert.Equal(t, 2, len(got.Message.Fragments))
+		assert.Equal(t, "25", got.Message.Fragments[0].Emote.ID)
+		assert.Equal(t, "moderator", got.Badges[0].SetID)
+		assert.Equal(t, true, got.Cheer == nil)
+		assert.Equal(t, true, got.Reply == nil)
+	})
+}
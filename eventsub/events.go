@@ -0,0 +1,637 @@
+package eventsub
+
+import (
+	"time"
+
+	"github.com/aidenwallis/go-twitch-client/helix"
+)
+
+// Broadcaster identifies the broadcaster whose channel an event occurred in
+type Broadcaster struct {
+	// BroadcasterUserID is the broadcaster’s user ID.
+	BroadcasterUserID string `json:"broadcaster_user_id"`
+
+	// BroadcasterUserLogin is the broadcaster’s user login.
+	BroadcasterUserLogin string `json:"broadcaster_user_login"`
+
+	// BroadcasterUserName is the broadcaster’s user display name.
+	BroadcasterUserName string `json:"broadcaster_user_name"`
+}
+
+// User identifies the user that caused an event
+type User struct {
+	// UserID is the user’s ID.
+	UserID string `json:"user_id"`
+
+	// UserLogin is the user’s login.
+	UserLogin string `json:"user_login"`
+
+	// UserName is the user’s display name.
+	UserName string `json:"user_name"`
+}
+
+// Moderator identifies the moderator that caused an event
+type Moderator struct {
+	// ModeratorUserID is the user ID of the moderator.
+	ModeratorUserID string `json:"moderator_user_id"`
+
+	// ModeratorUserLogin is the login of the moderator.
+	ModeratorUserLogin string `json:"moderator_user_login"`
+
+	// ModeratorUserName is the display name of the moderator.
+	ModeratorUserName string `json:"moderator_user_name"`
+}
+
+// ChannelUpdateEvent is the event of channel.update version 2
+type ChannelUpdateEvent struct {
+	Broadcaster
+
+	// Title is the channel’s stream title.
+	Title string `json:"title"`
+
+	// Language is the channel’s broadcast language.
+	Language string `json:"language"`
+
+	// CategoryID is the channel’s category ID.
+	CategoryID string `json:"category_id"`
+
+	// CategoryName is the category name.
+	CategoryName string `json:"category_name"`
+
+	// ContentClassificationLabels are the IDs of the content classification labels applied to the channel.
+	ContentClassificationLabels []string `json:"content_classification_labels"`
+}
+
+// Channel returns the updated channel information, in the shape returned by helix.GetChannelInformation.
+func (e *ChannelUpdateEvent) Channel() *helix.Channel {
+	return &helix.Channel{
+		BroadcasterID:       e.BroadcasterUserID,
+		BroadcasterLogin:    e.BroadcasterUserLogin,
+		BroadcasterName:     e.BroadcasterUserName,
+		GameName:            e.CategoryName,
+		GameID:              e.CategoryID,
+		BroadcasterLanguage: e.Language,
+		Title:               e.Title,
+	}
+}
+
+// ChannelFollowEvent is the event of channel.follow version 2
+type ChannelFollowEvent struct {
+	Broadcaster
+	User
+
+	// FollowedAt is the time the follow occurred.
+	FollowedAt time.Time `json:"followed_at"`
+}
+
+// ChannelSubscribeEvent is the event of channel.subscribe version 1
+type ChannelSubscribeEvent struct {
+	Broadcaster
+	User
+
+	// Tier of the subscription. Valid values are 1000, 2000, and 3000.
+	Tier string `json:"tier"`
+
+	// IsGift is whether the subscription is a gift.
+	IsGift bool `json:"is_gift"`
+}
+
+// ChannelSubscriptionGiftEvent is the event of channel.subscription.gift version 1
+type ChannelSubscriptionGiftEvent struct {
+	Broadcaster
+
+	// User is the user who sent the subscription gift, empty if it was an anonymous subscription gift.
+	User
+
+	// Total is the number of subscriptions in the subscription gift.
+	Total int `json:"total"`
+
+	// Tier of subscriptions in the subscription gift.
+	Tier string `json:"tier"`
+
+	// CumulativeTotal is the number of subscriptions gifted by this user in the channel. Nil for anonymous gifts
+	// or if the gifter has opted out of sharing this information.
+	CumulativeTotal *int `json:"cumulative_total"`
+
+	// IsAnonymous is whether the subscription gift was anonymous.
+	IsAnonymous bool `json:"is_anonymous"`
+}
+
+// ChannelSubscriptionMessageEvent is the event of channel.subscription.message version 1
+type ChannelSubscriptionMessageEvent struct {
+	Broadcaster
+	User
+
+	// Tier of the user’s subscription.
+	Tier string `json:"tier"`
+
+	// Message is the message sent with the resubscription.
+	Message SubscriptionMessage `json:"message"`
+
+	// CumulativeMonths is the total number of months the user has been subscribed to the channel.
+	CumulativeMonths int `json:"cumulative_months"`
+
+	// StreakMonths is the number of consecutive months the user’s current subscription has been active. Nil if
+	// the user has opted out of sharing this information.
+	StreakMonths *int `json:"streak_months"`
+
+	// DurationMonths is the month duration of the subscription.
+	DurationMonths int `json:"duration_months"`
+}
+
+// SubscriptionMessage is a chat message sent alongside a resubscription
+type SubscriptionMessage struct {
+	// Text of the resubscription chat message.
+	Text string `json:"text"`
+
+	// Emotes contains the positions of the emotes in the message text.
+	Emotes []*MessageEmote `json:"emotes"`
+}
+
+// MessageEmote is the position of an emote in a message
+type MessageEmote struct {
+	// Begin is the index of where the emote starts in the text.
+	Begin int `json:"begin"`
+
+	// End is the index of where the emote ends in the text.
+	End int `json:"end"`
+
+	// ID is the emote ID.
+	ID string `json:"id"`
+}
+
+// ChannelCheerEvent is the event of channel.cheer version 1
+type ChannelCheerEvent struct {
+	Broadcaster
+
+	// User is the user who cheered, empty if IsAnonymous is true.
+	User
+
+	// IsAnonymous is whether the user cheered anonymously or not.
+	IsAnonymous bool `json:"is_anonymous"`
+
+	// Message is the message sent with the cheer.
+	Message string `json:"message"`
+
+	// Bits is the number of bits cheered.
+	Bits int `json:"bits"`
+}
+
+// ChannelRaidEvent is the event of channel.raid version 1
+type ChannelRaidEvent struct {
+	// FromBroadcasterUserID is the broadcaster ID that created the raid.
+	FromBroadcasterUserID string `json:"from_broadcaster_user_id"`
+
+	// FromBroadcasterUserLogin is the broadcaster login that created the raid.
+	FromBroadcasterUserLogin string `json:"from_broadcaster_user_login"`
+
+	// FromBroadcasterUserName is the broadcaster display name that created the raid.
+	FromBroadcasterUserName string `json:"from_broadcaster_user_name"`
+
+	// ToBroadcasterUserID is the broadcaster ID that received the raid.
+	ToBroadcasterUserID string `json:"to_broadcaster_user_id"`
+
+	// ToBroadcasterUserLogin is the broadcaster login that received the raid.
+	ToBroadcasterUserLogin string `json:"to_broadcaster_user_login"`
+
+	// ToBroadcasterUserName is the broadcaster display name that received the raid.
+	ToBroadcasterUserName string `json:"to_broadcaster_user_name"`
+
+	// Viewers is the number of viewers in the raid.
+	Viewers int `json:"viewers"`
+}
+
+// ChannelBanEvent is the event of channel.ban version 1
+type ChannelBanEvent struct {
+	Broadcaster
+	User
+	Moderator
+
+	// Reason is the reason behind the ban.
+	Reason string `json:"reason"`
+
+	// BannedAt is the time the user was banned or put in a timeout.
+	BannedAt time.Time `json:"banned_at"`
+
+	// EndsAt is the time the timeout ends, nil if the user was banned instead of put in a timeout.
+	EndsAt *time.Time `json:"ends_at"`
+
+	// IsPermanent indicates whether the ban is permanent (true) or a timeout (false).
+	IsPermanent bool `json:"is_permanent"`
+}
+
+// ChannelUnbanEvent is the event of channel.unban version 1
+type ChannelUnbanEvent struct {
+	Broadcaster
+	User
+	Moderator
+}
+
+// StreamOnlineEvent is the event of stream.online version 1
+type StreamOnlineEvent struct {
+	Broadcaster
+
+	// ID is the ID of the stream.
+	ID string `json:"id"`
+
+	// Type is the stream type. Valid values are: live, playlist, watch_party, premiere, rerun.
+	Type string `json:"type"`
+
+	// StartedAt is the timestamp at which the stream went online.
+	StartedAt time.Time `json:"started_at"`
+}
+
+// StreamOfflineEvent is the event of stream.offline version 1
+type StreamOfflineEvent struct {
+	Broadcaster
+}
+
+// ChannelPointsCustomRewardRedemptionEvent is the event of channel.channel_points_custom_reward_redemption.add and
+// channel.channel_points_custom_reward_redemption.update version 1
+type ChannelPointsCustomRewardRedemptionEvent struct {
+	Broadcaster
+	User
+
+	// ID is the redemption identifier.
+	ID string `json:"id"`
+
+	// UserInput is the user input provided, empty if not provided.
+	UserInput string `json:"user_input"`
+
+	// Status of the redemption. Possible values are unknown, unfulfilled, fulfilled, and canceled.
+	Status string `json:"status"`
+
+	// Reward is basic information about the reward that was redeemed, at the time it was redeemed.
+	Reward ChannelPointsReward `json:"reward"`
+
+	// RedeemedAt is the time the reward was redeemed.
+	RedeemedAt time.Time `json:"redeemed_at"`
+}
+
+// ChannelPointsReward is a channel points custom reward
+type ChannelPointsReward struct {
+	// ID is the reward identifier.
+	ID string `json:"id"`
+
+	// Title is the reward name.
+	Title string `json:"title"`
+
+	// Cost is the reward cost.
+	Cost int `json:"cost"`
+
+	// Prompt is the reward description.
+	Prompt string `json:"prompt"`
+}
+
+// ChannelPollEvent is the event of channel.poll.begin, channel.poll.progress and channel.poll.end version 1
+type ChannelPollEvent struct {
+	Broadcaster
+
+	// ID of the poll.
+	ID string `json:"id"`
+
+	// Title is the question displayed for the poll.
+	Title string `json:"title"`
+
+	// Choices are the choices of the poll, votes are only included in progress and end events.
+	Choices []*PollChoice `json:"choices"`
+
+	// BitsVoting is the Bits voting settings for the poll.
+	BitsVoting PollVoting `json:"bits_voting"`
+
+	// ChannelPointsVoting is the Channel Points voting settings for the poll.
+	ChannelPointsVoting PollVoting `json:"channel_points_voting"`
+
+	// StartedAt is the time the poll started.
+	StartedAt time.Time `json:"started_at"`
+
+	// EndsAt is the time the poll will end, only set for begin and progress events.
+	EndsAt *time.Time `json:"ends_at,omitempty"`
+
+	// Status of the poll, only set for end events. Valid values are completed, archived and terminated.
+	Status string `json:"status,omitempty"`
+
+	// EndedAt is the time the poll ended, only set for end events.
+	EndedAt *time.Time `json:"ended_at,omitempty"`
+}
+
+// PollChoice is a single choice of a poll
+type PollChoice struct {
+	// ID of the poll choice.
+	ID string `json:"id"`
+
+	// Title is the text displayed for the choice.
+	Title string `json:"title"`
+
+	// ChannelPointsVotes is the number of votes received via Channel Points.
+	ChannelPointsVotes int `json:"channel_points_votes"`
+
+	// Votes is the total number of votes received for the choice across all methods of voting.
+	Votes int `json:"votes"`
+}
+
+// PollVoting is the voting settings of a poll
+type PollVoting struct {
+	// IsEnabled indicates if the voting method is enabled.
+	IsEnabled bool `json:"is_enabled"`
+
+	// AmountPerVote is the number needed to cast an additional vote.
+	AmountPerVote int `json:"amount_per_vote"`
+}
+
+// ChannelPredictionEvent is the event of channel.prediction.begin, channel.prediction.progress, channel.prediction.lock
+// and channel.prediction.end version 1
+type ChannelPredictionEvent struct {
+	Broadcaster
+
+	// ID is the prediction ID.
+	ID string `json:"id"`
+
+	// Title of the prediction.
+	Title string `json:"title"`
+
+	// Outcomes are the outcomes of the prediction.
+	Outcomes []*PredictionOutcome `json:"outcomes"`
+
+	// StartedAt is the time the prediction started.
+	StartedAt time.Time `json:"started_at"`
+
+	// LocksAt is the time the prediction will automatically lock, only set for begin and progress events.
+	LocksAt *time.Time `json:"locks_at,omitempty"`
+
+	// LockedAt is the time the prediction was locked, only set for lock events.
+	LockedAt *time.Time `json:"locked_at,omitempty"`
+
+	// WinningOutcomeID is the ID of the winning outcome, only set for end events that resolved.
+	WinningOutcomeID string `json:"winning_outcome_id,omitempty"`
+
+	// Status of the prediction, only set for end events. Valid values are resolved and canceled.
+	Status string `json:"status,omitempty"`
+
+	// EndedAt is the time the prediction ended, only set for end events.
+	EndedAt *time.Time `json:"ended_at,omitempty"`
+}
+
+// PredictionOutcome is a single outcome of a prediction
+type PredictionOutcome struct {
+	// ID is the outcome ID.
+	ID string `json:"id"`
+
+	// Title is the outcome title.
+	Title string `json:"title"`
+
+	// Color is the color for the outcome. Valid values are pink and blue.
+	Color string `json:"color"`
+
+	// Users is the number of users who used Channel Points on this outcome.
+	Users int `json:"users"`
+
+	// ChannelPoints is the total number of Channel Points used on this outcome.
+	ChannelPoints int `json:"channel_points"`
+
+	// TopPredictors are up to 10 of the users who used the most Channel Points on this outcome.
+	TopPredictors []*PredictionPredictor `json:"top_predictors"`
+}
+
+// PredictionPredictor is a user that used Channel Points on a prediction outcome
+type PredictionPredictor struct {
+	User
+
+	// ChannelPointsWon is the number of Channel Points won, nil until the prediction is resolved.
+	ChannelPointsWon *int `json:"channel_points_won"`
+
+	// ChannelPointsUsed is the number of Channel Points used to participate in the prediction.
+	ChannelPointsUsed int `json:"channel_points_used"`
+}
+
+// ChannelHypeTrainEvent is the event of channel.hype_train.begin, channel.hype_train.progress and channel.hype_train.end
+// version 1
+type ChannelHypeTrainEvent struct {
+	Broadcaster
+
+	// ID is the Hype Train ID.
+	ID string `json:"id"`
+
+	// Total is the total points contributed to the Hype Train.
+	Total int `json:"total"`
+
+	// Progress is the number of points contributed to the Hype Train at the current level, not set for end events.
+	Progress int `json:"progress,omitempty"`
+
+	// Goal is the number of points required to reach the next level, not set for end events.
+	Goal int `json:"goal,omitempty"`
+
+	// TopContributions are the contributors with the most points contributed.
+	TopContributions []*HypeTrainContribution `json:"top_contributions"`
+
+	// LastContribution is the most recent contribution, not set for end events.
+	LastContribution *HypeTrainContribution `json:"last_contribution,omitempty"`
+
+	// Level is the current level of the Hype Train.
+	Level int `json:"level"`
+
+	// StartedAt is the time when the Hype Train started.
+	StartedAt time.Time `json:"started_at"`
+
+	// ExpiresAt is the time when the Hype Train expires, not set for end events.
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+
+	// EndedAt is the time when the Hype Train ended, only set for end events.
+	EndedAt *time.Time `json:"ended_at,omitempty"`
+
+	// CooldownEndsAt is the time when the Hype Train cooldown ends, only set for end events.
+	CooldownEndsAt *time.Time `json:"cooldown_ends_at,omitempty"`
+}
+
+// HypeTrainContribution is a contribution to a Hype Train
+type HypeTrainContribution struct {
+	User
+
+	// Type of contribution. Valid values include bits, subscription, other.
+	Type string `json:"type"`
+
+	// Total is the total amount contributed.
+	Total int `json:"total"`
+}
+
+// ChannelChatMessageEvent is the event of channel.chat.message version 1
+type ChannelChatMessageEvent struct {
+	Broadcaster
+
+	// ChatterUserID is the user ID of the user that sent the message.
+	ChatterUserID string `json:"chatter_user_id"`
+
+	// ChatterUserLogin is the user login of the user that sent the message.
+	ChatterUserLogin string `json:"chatter_user_login"`
+
+	// ChatterUserName is the user name of the user that sent the message.
+	ChatterUserName string `json:"chatter_user_name"`
+
+	// MessageID is a UUID that identifies the message.
+	MessageID string `json:"message_id"`
+
+	// Message is the structured chat message.
+	Message ChatMessage `json:"message"`
+
+	// MessageType is the type of message. Possible values include text, channel_points_highlighted,
+	// channel_points_sub_only, user_intro.
+	MessageType string `json:"message_type"`
+
+	// Badges are the chat badges of the chatter.
+	Badges []*ChatMessageBadge `json:"badges"`
+
+	// Cheer is the metadata of the cheer, nil if the message isn't a cheer.
+	Cheer *ChatMessageCheer `json:"cheer"`
+
+	// Color is the color of the user’s name in the chat room, a hexadecimal RGB color code.
+	Color string `json:"color"`
+
+	// Reply is the metadata of the reply, nil if the message isn't a reply.
+	Reply *ChatMessageReply `json:"reply"`
+
+	// ChannelPointsCustomRewardID is the ID of a channel points custom reward that was redeemed.
+	ChannelPointsCustomRewardID string `json:"channel_points_custom_reward_id"`
+}
+
+// ChatMessage is a structured chat message
+type ChatMessage struct {
+	// Text is the chat message in plain text.
+	Text string `json:"text"`
+
+	// Fragments are the ordered list of chat message fragments.
+	Fragments []*ChatMessageFragment `json:"fragments"`
+}
+
+// ChatMessageFragment is a single fragment of a chat message
+type ChatMessageFragment struct {
+	// Type of message fragment. Possible values are text, cheermote, emote and mention.
+	Type string `json:"type"`
+
+	// Text is the message text in the fragment.
+	Text string `json:"text"`
+
+	// Cheermote is the metadata pertaining to the cheermote, only set for cheermote fragments.
+	Cheermote *ChatMessageCheermote `json:"cheermote"`
+
+	// Emote is the metadata pertaining to the emote, only set for emote fragments.
+	Emote *ChatMessageEmote `json:"emote"`
+
+	// Mention is the metadata pertaining to the mention, only set for mention fragments.
+	Mention *User `json:"mention"`
+}
+
+// ChatMessageCheermote is a cheermote in a chat message
+type ChatMessageCheermote struct {
+	// Prefix is the name portion of the Cheermote string that you use in chat to cheer Bits.
+	Prefix string `json:"prefix"`
+
+	// Bits is the amount of bits cheered.
+	Bits int `json:"bits"`
+
+	// Tier is the tier level of the cheermote.
+	Tier int `json:"tier"`
+}
+
+// ChatMessageEmote is an emote in a chat message
+type ChatMessageEmote struct {
+	// ID is an ID that uniquely identifies this emote.
+	ID string `json:"id"`
+
+	// EmoteSetID is an ID that identifies the emote set that the emote belongs to.
+	EmoteSetID string `json:"emote_set_id"`
+
+	// OwnerID is the ID of the broadcaster who owns the emote.
+	OwnerID string `json:"owner_id"`
+
+	// Format are the formats that the emote is available in, static and/or animated.
+	Format []string `json:"format"`
+}
+
+// ChatMessageBadge is a chat badge of a chatter
+type ChatMessageBadge struct {
+	// SetID is an ID that identifies this set of chat badges. For example, Bits or Subscriber.
+	SetID string `json:"set_id"`
+
+	// ID is an ID that identifies this version of the badge.
+	ID string `json:"id"`
+
+	// Info contains metadata related to the chat badges in the badges tag, such as the number of months subscribed.
+	Info string `json:"info"`
+}
+
+// ChatMessageCheer is the cheer metadata of a chat message
+type ChatMessageCheer struct {
+	// Bits is the amount of Bits the user cheered.
+	Bits int `json:"bits"`
+}
+
+// ChatMessageReply is the reply metadata of a chat message
+type ChatMessageReply struct {
+	// ParentMessageID is an ID that uniquely identifies the parent message that this message is replying to.
+	ParentMessageID string `json:"parent_message_id"`
+
+	// ParentMessageBody is the message body of the parent message.
+	ParentMessageBody string `json:"parent_message_body"`
+
+	// ParentUserID is the user ID of the sender of the parent message.
+	ParentUserID string `json:"parent_user_id"`
+
+	// ParentUserName is the user name of the sender of the parent message.
+	ParentUserName string `json:"parent_user_name"`
+
+	// ParentUserLogin is the user login of the sender of the parent message.
+	ParentUserLogin string `json:"parent_user_login"`
+
+	// ThreadMessageID is an ID that identifies the parent message of the reply thread.
+	ThreadMessageID string `json:"thread_message_id"`
+
+	// ThreadUserID is the user ID of the sender of the thread’s parent message.
+	ThreadUserID string `json:"thread_user_id"`
+
+	// ThreadUserName is the user name of the sender of the thread’s parent message.
+	ThreadUserName string `json:"thread_user_name"`
+
+	// ThreadUserLogin is the user login of the sender of the thread’s parent message.
+	ThreadUserLogin string `json:"thread_user_login"`
+}
+
+// ChannelChatSettingsUpdateEvent is the event of channel.chat_settings.update version 1
+type ChannelChatSettingsUpdateEvent struct {
+	Broadcaster
+
+	// EmoteMode is whether chat messages must contain only emotes.
+	EmoteMode bool `json:"emote_mode"`
+
+	// FollowerMode is whether the broadcaster restricts the chat room to followers only.
+	FollowerMode bool `json:"follower_mode"`
+
+	// FollowerModeDurationMinutes is the length of time, in minutes, that the followers must have followed the broadcaster
+	// to participate in the chat room. Nil if FollowerMode is false.
+	FollowerModeDurationMinutes *int `json:"follower_mode_duration_minutes"`
+
+	// SlowMode is whether the broadcaster limits how often users in the chat room are allowed to send messages.
+	SlowMode bool `json:"slow_mode"`
+
+	// SlowModeWaitTimeSeconds is the amount of time, in seconds, that users need to wait between sending messages.
+	// Nil if SlowMode is false.
+	SlowModeWaitTimeSeconds *int `json:"slow_mode_wait_time_seconds"`
+
+	// SubscriberMode is whether only users that subscribe to the broadcaster’s channel may talk in the chat room.
+	SubscriberMode bool `json:"subscriber_mode"`
+
+	// UniqueChatMode is whether the broadcaster requires users to post only unique messages in the chat room.
+	UniqueChatMode bool `json:"unique_chat_mode"`
+}
+
+// ChatSettings returns the updated chat settings, in the shape returned by helix.GetChatSettings.
+func (e *ChannelChatSettingsUpdateEvent) ChatSettings() *helix.ChatSettings {
+	return &helix.ChatSettings{
+		EmoteMode:            e.EmoteMode,
+		FollowerMode:         e.FollowerMode,
+		SlowMode:             e.SlowMode,
+		SubscriberMode:       e.SubscriberMode,
+		UniqueChatMode:       e.UniqueChatMode,
+		FollowerModeDuration: e.FollowerModeDurationMinutes,
+		SlowModeWaitTime:     e.SlowModeWaitTimeSeconds,
+	}
+}
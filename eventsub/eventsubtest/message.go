@@ -0,0 +1,82 @@
+// Package eventsubtest provides utilities for testing EventSub consumers: forging signed webhook requests, and a
+// local server speaking the EventSub WebSocket protocol.
+package eventsubtest
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"github.com/aidenwallis/go-twitch-client/helix"
+)
+
+// MessageOptions controls the metadata of forged messages, every field is optional.
+type MessageOptions struct {
+	// MessageID is the message ID, defaults to a random ID. Reuse an ID to test deduplication of resent messages.
+	MessageID string
+
+	// Timestamp is the time the message claims to be sent at, defaults to now. Use an old timestamp to test the
+	// rejection of stale messages.
+	Timestamp time.Time
+
+	// SubscriptionID is the ID of the subscription the message is for, defaults to a random ID.
+	SubscriptionID string
+}
+
+func (o *MessageOptions) messageID() string {
+	if o != nil && o.MessageID != "" {
+		return o.MessageID
+	}
+	return randomID()
+}
+
+func (o *MessageOptions) timestamp() time.Time {
+	if o != nil && !o.Timestamp.IsZero() {
+		return o.Timestamp
+	}
+	return time.Now()
+}
+
+// Subscription builds the subscription that Twitch would send in messages for the condition.
+func Subscription(condition helix.EventSubCondition, status string, transport *helix.EventSubTransport, options *MessageOptions) (*helix.EventSubSubscription, error) {
+	bs, err := json.Marshal(condition)
+	if err != nil {
+		return nil, err
+	}
+
+	values := map[string]string{}
+	if err := json.Unmarshal(bs, &values); err != nil {
+		return nil, err
+	}
+
+	id := randomID()
+	if options != nil && options.SubscriptionID != "" {
+		id = options.SubscriptionID
+	}
+
+	subscription := &helix.EventSubSubscription{
+		ID:        id,
+		Status:    status,
+		Type:      condition.EventSubType(),
+		Version:   condition.EventSubVersion(),
+		Condition: values,
+		CreatedAt: time.Now().UTC(),
+	}
+	if transport != nil {
+		subscription.Transport = *transport
+		subscription.Transport.Secret = ""
+	}
+	return subscription, nil
+}
+
+// randomID returns a random ID formatted as a UUID, like the message and subscription IDs Twitch uses.
+func randomID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+
+	s := hex.EncodeToString(b[:])
+	return s[0:8] + "-" + s[8:12] + "-" + s[12:16] + "-" + s[16:20] + "-" + s[20:]
+}
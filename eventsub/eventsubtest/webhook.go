@@ -0,0 +1,137 @@
+package eventsubtest
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/aidenwallis/go-twitch-client/eventsub"
+	"github.com/aidenwallis/go-twitch-client/helix"
+)
+
+// DefaultCallback is the callback URL forged requests are sent to when Webhook.Callback is empty
+const DefaultCallback = "http://localhost/webhook"
+
+// Webhook forges EventSub webhook requests, signed the way Twitch signs them.
+type Webhook struct {
+	// Secret is the secret requests are signed with, it must match the secret of the handler under test.
+	Secret string
+
+	// Callback (optional) is the URL requests are sent to, defaults to DefaultCallback. Set it to the URL of the
+	// server under test to use Post.
+	Callback string
+
+	// Client (optional) is the HTTP client used by Post, defaults to http.DefaultClient.
+	Client *http.Client
+}
+
+// Notification forges a notification request for the condition, with event as the event payload. The event can be
+// any value that marshals to JSON, such as one of the typed eventsub events.
+func (w *Webhook) Notification(condition helix.EventSubCondition, event interface{}, options *MessageOptions) (*http.Request, error) {
+	subscription, err := Subscription(condition, helix.EventSubStatusEnabled, w.transport(), options)
+	if err != nil {
+		return nil, err
+	}
+
+	bs, err := json.Marshal(event)
+	if err != nil {
+		return nil, err
+	}
+
+	return w.request(eventsub.MessageTypeNotification, subscription, map[string]interface{}{
+		"subscription": subscription,
+		"event":        json.RawMessage(bs),
+	}, options)
+}
+
+// Challenge forges a webhook callback verification request for the condition, the handler under test is expected
+// to respond with the challenge.
+func (w *Webhook) Challenge(condition helix.EventSubCondition, challenge string, options *MessageOptions) (*http.Request, error) {
+	subscription, err := Subscription(condition, helix.EventSubStatusWebhookCallbackVerificationPending, w.transport(), options)
+	if err != nil {
+		return nil, err
+	}
+
+	return w.request(eventsub.MessageTypeWebhookCallbackVerification, subscription, map[string]interface{}{
+		"subscription": subscription,
+		"challenge":    challenge,
+	}, options)
+}
+
+// Revocation forges a revocation request for the condition, status is the reason the subscription was revoked.
+func (w *Webhook) Revocation(condition helix.EventSubCondition, status string, options *MessageOptions) (*http.Request, error) {
+	subscription, err := Subscription(condition, status, w.transport(), options)
+	if err != nil {
+		return nil, err
+	}
+
+	return w.request(eventsub.MessageTypeRevocation, subscription, map[string]interface{}{
+		"subscription": subscription,
+	}, options)
+}
+
+// Post sends the forged request to its URL.
+func (w *Webhook) Post(req *http.Request) (*http.Response, error) {
+	client := w.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return client.Do(req)
+}
+
+// Serve sends the forged request to the handler, and returns the recorded response.
+func Serve(handler http.Handler, req *http.Request) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	return w
+}
+
+func (w *Webhook) callback() string {
+	if w.Callback != "" {
+		return w.Callback
+	}
+	return DefaultCallback
+}
+
+func (w *Webhook) transport() *helix.EventSubTransport {
+	return helix.WebhookTransport(w.callback(), "")
+}
+
+func (w *Webhook) request(messageType string, subscription *helix.EventSubSubscription, body interface{}, options *MessageOptions) (*http.Request, error) {
+	bs, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, w.callback(), bytes.NewReader(bs))
+	if err != nil {
+		return nil, err
+	}
+
+	messageID := options.messageID()
+	timestamp := options.timestamp().UTC().Format(time.RFC3339Nano)
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(eventsub.HeaderMessageID, messageID)
+	req.Header.Set(eventsub.HeaderMessageRetry, "0")
+	req.Header.Set(eventsub.HeaderMessageType, messageType)
+	req.Header.Set(eventsub.HeaderMessageTimestamp, timestamp)
+	req.Header.Set(eventsub.HeaderMessageSignature, Sign(w.Secret, messageID, timestamp, bs))
+	req.Header.Set(eventsub.HeaderSubscriptionType, subscription.Type)
+	req.Header.Set(eventsub.HeaderSubscriptionVersion, subscription.Version)
+	return req, nil
+}
+
+// Sign returns the signature header value Twitch sends for the message.
+func Sign(secret, messageID, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(messageID))
+	mac.Write([]byte(timestamp))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
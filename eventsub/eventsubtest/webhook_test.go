@@ -0,0 +1,108 @@
+package eventsubtest
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/aidenwallis/go-twitch-client/eventsub"
+	"github.com/aidenwallis/go-twitch-client/helix"
+	"github.com/aidenwallis/go-twitch-client/internal/testutils/assert"
+)
+
+const fakeSecret = "supersecret"
+
+var follow = &helix.ChannelFollowCondition{BroadcasterUserID: "1", ModeratorUserID: "1"}
+
+func TestWebhookNotification(t *testing.T) {
+	var got *eventsub.ChannelFollowEvent
+	d := eventsub.NewDispatcher()
+	d.OnChannelFollow(func(ctx context.Context, event *eventsub.ChannelFollowEvent) {
+		got = event
+	})
+
+	h := eventsub.NewWebhookHandler(&eventsub.WebhookHandlerOptions{Secret: fakeSecret})
+	h.OnNotification(d.Handle)
+
+	w := &Webhook{Secret: fakeSecret}
+	event := &eventsub.ChannelFollowEvent{
+		Broadcaster: eventsub.Broadcaster{BroadcasterUserID: "1"},
+		User:        eventsub.User{UserID: "2", UserLogin: "forsen"},
+	}
+
+	req, err := w.Notification(follow, event, &MessageOptions{MessageID: "msg"})
+	assert.NoError(t, err)
+	assert.Equal(t, "msg", req.Header.Get(eventsub.HeaderMessageID))
+	assert.Equal(t, helix.EventSubTypeChannelFollow, req.Header.Get(eventsub.HeaderSubscriptionType))
+	assert.Equal(t, "2", req.Header.Get(eventsub.HeaderSubscriptionVersion))
+
+	assert.Equal(t, http.StatusNoContent, Serve(h, req).Code)
+	assert.Equal(t, "forsen", got.UserLogin)
+
+	// resending the same message ID is deduplicated
+	got = nil
+	req, err = w.Notification(follow, event, &MessageOptions{MessageID: "msg"})
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, Serve(h, req).Code)
+	assert.Equal(t, true, got == nil)
+}
+
+func TestWebhookRejections(t *testing.T) {
+	h := eventsub.NewWebhookHandler(&eventsub.WebhookHandlerOptions{Secret: fakeSecret})
+
+	req, err := (&Webhook{Secret: "wrongsecret"}).Notification(follow, struct{}{}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusForbidden, Serve(h, req).Code)
+
+	req, err = (&Webhook{Secret: fakeSecret}).Notification(follow, struct{}{}, &MessageOptions{Timestamp: time.Now().Add(-time.Hour)})
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, Serve(h, req).Code)
+}
+
+func TestWebhookChallenge(t *testing.T) {
+	h := eventsub.NewWebhookHandler(&eventsub.WebhookHandlerOptions{Secret: fakeSecret})
+
+	req, err := (&Webhook{Secret: fakeSecret}).Challenge(follow, "pogchamp", nil)
+	assert.NoError(t, err)
+
+	w := Serve(h, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "pogchamp", w.Body.String())
+}
+
+func TestWebhookRevocation(t *testing.T) {
+	var revoked *helix.EventSubSubscription
+	h := eventsub.NewWebhookHandler(&eventsub.WebhookHandlerOptions{Secret: fakeSecret})
+	h.OnRevocation(func(ctx context.Context, subscription *helix.EventSubSubscription) {
+		revoked = subscription
+	})
+
+	req, err := (&Webhook{Secret: fakeSecret}).Revocation(follow, helix.EventSubStatusUserRemoved, &MessageOptions{SubscriptionID: "sub"})
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, Serve(h, req).Code)
+	assert.Equal(t, "sub", revoked.ID)
+	assert.Equal(t, helix.EventSubStatusUserRemoved, revoked.Status)
+	assert.Equal(t, "1", revoked.Condition["moderator_user_id"])
+	assert.Equal(t, helix.EventSubTransportWebhook, revoked.Transport.Method)
+}
+
+func TestWebhookPost(t *testing.T) {
+	srv := httptest.NewServer(eventsub.NewWebhookHandler(&eventsub.WebhookHandlerOptions{Secret: fakeSecret}))
+	defer srv.Close()
+
+	w := &Webhook{Secret: fakeSecret, Callback: srv.URL + "/webhook"}
+	req, err := w.Challenge(follow, "pogchamp", nil)
+	assert.NoError(t, err)
+
+	resp, err := w.Post(req)
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "pogchamp", string(body))
+}
@@ -0,0 +1,241 @@
+package eventsubtest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aidenwallis/go-twitch-client/eventsub"
+	"github.com/aidenwallis/go-twitch-client/helix"
+	"github.com/aidenwallis/go-twitch-client/internal/websocket"
+)
+
+// defaultKeepaliveTimeoutSeconds is the keepalive timeout Twitch uses when the client does not request one
+const defaultKeepaliveTimeoutSeconds = 10
+
+// closeReconnectGraceTimeExpired is the close code Twitch uses to close the old connection after a reconnect
+const closeReconnectGraceTimeExpired = 4004
+
+// Server is a local server speaking the EventSub WebSocket protocol. Every connection is welcomed with a new session,
+// which tests can then send notifications, revocations, keepalives and reconnects on.
+type Server struct {
+	// URL is the ws:// URL of the server, pass it as the WebSocket client URL.
+	URL string
+
+	srv       *httptest.Server
+	sessions  chan *Session
+	done      chan struct{} // closed by Close, so unaccepted sessions are dropped
+	closeOnce sync.Once
+
+	// conns are tracked separately, as hijacked connections are not closed by the HTTP server
+	mu    sync.Mutex
+	conns []*websocket.Conn
+}
+
+// NewServer starts a new Server, it must be closed by calling Close.
+func NewServer() *Server {
+	s := &Server{sessions: make(chan *Session, 16), done: make(chan struct{})}
+	s.srv = httptest.NewServer(http.HandlerFunc(s.serve))
+	s.URL = "ws" + strings.TrimPrefix(s.srv.URL, "http")
+	return s
+}
+
+// Close shuts down the server, closing every connection.
+func (s *Server) Close() {
+	s.closeOnce.Do(func() {
+		close(s.done)
+	})
+
+	s.mu.Lock()
+	for _, conn := range s.conns {
+		_ = conn.Close()
+	}
+	s.conns = nil
+	s.mu.Unlock()
+
+	s.srv.Close()
+}
+
+// Accept waits for the next client connection, and returns its welcomed session.
+func (s *Server) Accept(ctx context.Context) (*Session, error) {
+	select {
+	case session := <-s.sessions:
+		return session, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+	conn, err := websocket.Upgrade(w, r)
+	if err != nil {
+		return
+	}
+
+	s.mu.Lock()
+	s.conns = append(s.conns, conn)
+	s.mu.Unlock()
+
+	keepalive := defaultKeepaliveTimeoutSeconds
+	if v, err := strconv.Atoi(r.URL.Query().Get("keepalive_timeout_seconds")); err == nil && v > 0 {
+		keepalive = v
+	}
+
+	session := &Session{
+		ID:                      randomID(),
+		KeepaliveTimeoutSeconds: keepalive,
+		server:                  s,
+		conn:                    conn,
+		done:                    make(chan struct{}),
+	}
+
+	if err := session.welcome(); err != nil {
+		_ = conn.Close()
+		return
+	}
+
+	// read until the client goes away, pings are answered by ReadMessage
+	go func() {
+		defer close(session.done)
+		defer conn.Close()
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	// sessions wait to be accepted until the server is closed
+	select {
+	case s.sessions <- session:
+	case <-s.done:
+		_ = conn.Close()
+	}
+}
+
+// Session is a welcomed EventSub WebSocket session of a connected client
+type Session struct {
+	// ID is the session ID, as sent in the welcome message.
+	ID string
+
+	// KeepaliveTimeoutSeconds is the keepalive timeout sent in the welcome message, the client requested timeout
+	// or 10 seconds.
+	KeepaliveTimeoutSeconds int
+
+	server *Server
+	conn   *websocket.Conn
+	done   chan struct{}
+
+	closeOnce sync.Once
+}
+
+// Done is closed once the client disconnects.
+func (s *Session) Done() <-chan struct{} {
+	return s.done
+}
+
+// Notification sends a notification for the condition, with event as the event payload.
+func (s *Session) Notification(condition helix.EventSubCondition, event interface{}, options *MessageOptions) error {
+	subscription, err := Subscription(condition, helix.EventSubStatusEnabled, helix.WebSocketTransport(s.ID), options)
+	if err != nil {
+		return err
+	}
+
+	bs, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	return s.send(eventsub.MessageTypeNotification, map[string]interface{}{
+		"subscription": subscription,
+		"event":        json.RawMessage(bs),
+	}, subscription, options)
+}
+
+// Revocation sends a revocation for the condition, status is the reason the subscription was revoked.
+func (s *Session) Revocation(condition helix.EventSubCondition, status string, options *MessageOptions) error {
+	subscription, err := Subscription(condition, status, helix.WebSocketTransport(s.ID), options)
+	if err != nil {
+		return err
+	}
+
+	return s.send(eventsub.MessageTypeRevocation, map[string]interface{}{
+		"subscription": subscription,
+	}, subscription, options)
+}
+
+// Keepalive sends a keepalive message.
+func (s *Session) Keepalive() error {
+	return s.send(eventsub.MessageTypeSessionKeepalive, map[string]interface{}{}, nil, nil)
+}
+
+// Reconnect sends a reconnect message pointing the client back at the server, and waits for the client to connect and
+// be welcomed. The new session is returned, while this session stays open so tests can still send messages on it,
+// which the client must not lose. Call Close afterwards to close it, the way Twitch does.
+func (s *Session) Reconnect(ctx context.Context) (*Session, error) {
+	err := s.send(eventsub.MessageTypeSessionReconnect, map[string]interface{}{
+		"session": map[string]interface{}{
+			"id":                        s.ID,
+			"status":                    "reconnecting",
+			"keepalive_timeout_seconds": nil,
+			"reconnect_url":             s.server.URL,
+			"connected_at":              time.Now().UTC(),
+		},
+	}, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	next, err := s.server.Accept(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return next, nil
+}
+
+// Close closes the session, the way Twitch closes the old session after a reconnect.
+func (s *Session) Close() error {
+	var err error
+	s.closeOnce.Do(func() {
+		err = s.conn.CloseWithReason(closeReconnectGraceTimeExpired, "client reconnected")
+	})
+	return err
+}
+
+func (s *Session) welcome() error {
+	return s.send(eventsub.MessageTypeSessionWelcome, map[string]interface{}{
+		"session": map[string]interface{}{
+			"id":                        s.ID,
+			"status":                    "connected",
+			"keepalive_timeout_seconds": s.KeepaliveTimeoutSeconds,
+			"reconnect_url":             nil,
+			"connected_at":              time.Now().UTC(),
+		},
+	}, nil, nil)
+}
+
+func (s *Session) send(messageType string, payload interface{}, subscription *helix.EventSubSubscription, options *MessageOptions) error {
+	metadata := map[string]interface{}{
+		"message_id":        options.messageID(),
+		"message_type":      messageType,
+		"message_timestamp": options.timestamp().UTC().Format(time.RFC3339Nano),
+	}
+	if subscription != nil {
+		metadata["subscription_type"] = subscription.Type
+		metadata["subscription_version"] = subscription.Version
+	}
+
+	bs, err := json.Marshal(map[string]interface{}{
+		"metadata": metadata,
+		"payload":  payload,
+	})
+	if err != nil {
+		return err
+	}
+	return s.conn.WriteMessage(websocket.TextMessage, bs)
+}
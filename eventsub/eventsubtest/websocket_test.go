@@ -0,0 +1,93 @@
+package eventsubtest
+
+import (
+	"context"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/aidenwallis/go-twitch-client/eventsub"
+	"github.com/aidenwallis/go-twitch-client/helix"
+	"github.com/aidenwallis/go-twitch-client/internal/testutils/assert"
+	"github.com/aidenwallis/go-twitch-client/internal/websocket"
+)
+
+func TestServer(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	notifications := make(chan *eventsub.Notification, 10)
+	revocations := make(chan *helix.EventSubSubscription, 1)
+
+	c := eventsub.NewWebSocketClient(&eventsub.WebSocketClientOptions{URL: srv.URL, KeepaliveTimeout: 30 * time.Second})
+	c.OnNotification(func(ctx context.Context, n *eventsub.Notification) error {
+		notifications <- n
+		return nil
+	})
+	c.OnRevocation(func(ctx context.Context, subscription *helix.EventSubSubscription) {
+		revocations <- subscription
+	})
+
+	done := make(chan error, 1)
+	go func() {
+		done <- c.Connect(ctx)
+	}()
+
+	session, err := srv.Accept(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 30, session.KeepaliveTimeoutSeconds)
+
+	online := &helix.StreamOnlineCondition{BroadcasterUserID: "1"}
+	assert.NoError(t, session.Keepalive())
+	assert.NoError(t, session.Notification(online, &eventsub.StreamOnlineEvent{ID: "1"}, &MessageOptions{MessageID: "1"}))
+
+	n := <-notifications
+	assert.Equal(t, "1", n.MessageID)
+	assert.Equal(t, helix.EventSubTypeStreamOnline, n.Subscription.Type)
+	assert.Equal(t, session.ID, n.Subscription.Transport.SessionID)
+	assert.Equal(t, session.ID, c.SessionID())
+
+	// messages sent on the old session after the reconnect are not lost
+	next, err := session.Reconnect(ctx)
+	assert.NoError(t, err)
+	assert.NoError(t, session.Notification(online, &eventsub.StreamOnlineEvent{ID: "2"}, &MessageOptions{MessageID: "2"}))
+	assert.NoError(t, session.Close())
+	assert.NoError(t, next.Notification(online, &eventsub.StreamOnlineEvent{ID: "3"}, &MessageOptions{MessageID: "3"}))
+
+	assert.Equal(t, "2", (<-notifications).MessageID)
+	assert.Equal(t, "3", (<-notifications).MessageID)
+	assert.Equal(t, next.ID, c.SessionID())
+
+	assert.NoError(t, next.Revocation(online, helix.EventSubStatusAuthorizationRevoked, nil))
+	assert.Equal(t, helix.EventSubStatusAuthorizationRevoked, (<-revocations).Status)
+
+	cancel()
+	assert.ErrorIs(t, <-done, context.Canceled)
+	<-next.Done()
+}
+
+func TestServerCloseUnaccepted(t *testing.T) {
+	before := runtime.NumGoroutine()
+	srv := NewServer()
+
+	// more sessions than are buffered are never accepted
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	for i := 0; i < 20; i++ {
+		conn, err := websocket.Dial(ctx, srv.URL, nil)
+		assert.NoError(t, err)
+		_, _, err = conn.ReadMessage()
+		assert.NoError(t, err)
+		defer conn.Close()
+	}
+	srv.Close()
+
+	// the handlers of unaccepted sessions return once the server is closed
+	for runtime.NumGoroutine() > before && ctx.Err() == nil {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.NoError(t, ctx.Err())
+}
@@ -0,0 +1,55 @@
+// Package eventsub implements receiving EventSub notifications over the webhook and WebSocket transports.
+//
+// See: https://dev.twitch.tv/docs/eventsub
+package eventsub
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/aidenwallis/go-twitch-client/helix"
+)
+
+const (
+	// MessageTypeNotification is sent when an event the subscription is for occurs.
+	MessageTypeNotification = "notification"
+
+	// MessageTypeWebhookCallbackVerification is sent to verify the callback of a new webhook subscription.
+	MessageTypeWebhookCallbackVerification = "webhook_callback_verification"
+
+	// MessageTypeRevocation is sent when Twitch revokes a subscription.
+	MessageTypeRevocation = "revocation"
+)
+
+// Notification is a verified EventSub notification
+type Notification struct {
+	// MessageID is an ID that uniquely identifies the message. Twitch may resend a message, using the same ID.
+	MessageID string
+
+	// MessageType is the type of the message, for notifications this is always MessageTypeNotification.
+	MessageType string
+
+	// Timestamp is the time Twitch sent the message.
+	Timestamp time.Time
+
+	// Subscription is the subscription the notification was sent for.
+	Subscription *helix.EventSubSubscription
+
+	// Event is the raw JSON event payload, its structure depends on the subscription type and version.
+	Event json.RawMessage
+}
+
+// NotificationHandler handles a verified notification. Returning an error marks the notification as unprocessed,
+// so that it is handled again if Twitch resends it.
+type NotificationHandler func(ctx context.Context, notification *Notification) error
+
+// RevocationHandler handles a subscription being revoked by Twitch. Subscription.Status contains the reason.
+type RevocationHandler func(ctx context.Context, subscription *helix.EventSubSubscription)
+
+// payload is the body of webhook messages, and the payload of WebSocket messages
+type payload struct {
+	Subscription *helix.EventSubSubscription `json:"subscription"`
+	Event        json.RawMessage             `json:"event,omitempty"`
+	Challenge    string                      `json:"challenge,omitempty"`
+}
@@ -0,0 +1,88 @@
+package eventsub
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	// defaultProcessAttempts is how many times a failing handler is attempted by default.
+	defaultProcessAttempts = 3
+
+	// defaultProcessBackoff is how long is waited before the first retry by default, it doubles on every retry.
+	defaultProcessBackoff = 100 * time.Millisecond
+)
+
+// ProcessorOptions defines all options the processing wrapper supports.
+type ProcessorOptions struct {
+	// Store (optional) records the processed message IDs, defaults to a new MemoryMessageStore. Use a durable store,
+	// such as a FileMessageStore, for deduplication to survive restarts.
+	Store MessageStore
+
+	// TTL (optional) is how long processed message IDs are remembered, defaults to 10 minutes which is the age
+	// after which webhook messages are rejected as stale.
+	TTL time.Duration
+
+	// MaxAttempts (optional) is how many times the handler is attempted before giving up, defaults to 3.
+	MaxAttempts int
+
+	// Backoff (optional) is how long is waited before retrying the handler for the first time, it doubles on every
+	// following retry. Defaults to 100 milliseconds.
+	Backoff time.Duration
+}
+
+// Process wraps the handler with at-least-once processing: notifications whose message ID was already processed are
+// acknowledged without calling the handler, failing handlers are retried with exponential backoff, and the message
+// ID is only recorded once the handler succeeds.
+//
+// When every attempt fails the last error is returned, so that the transport leaves the message unacknowledged.
+// For webhooks, this makes Twitch resend the message later.
+func Process(handler NotificationHandler, options *ProcessorOptions) NotificationHandler {
+	if options == nil {
+		options = &ProcessorOptions{}
+	}
+
+	store := options.Store
+	if store == nil {
+		store = NewMemoryMessageStore()
+	}
+	ttl := options.TTL
+	if ttl <= 0 {
+		ttl = defaultMaxMessageAge
+	}
+	attempts := options.MaxAttempts
+	if attempts <= 0 {
+		attempts = defaultProcessAttempts
+	}
+	backoff := options.Backoff
+	if backoff <= 0 {
+		backoff = defaultProcessBackoff
+	}
+
+	return func(ctx context.Context, notification *Notification) error {
+		seen, err := store.Seen(ctx, notification.MessageID)
+		if err != nil || seen {
+			return err
+		}
+
+		wait := backoff
+		for attempt := 1; ; attempt++ {
+			err = handler(ctx, notification)
+			if err == nil {
+				return store.Mark(ctx, notification.MessageID, ttl)
+			}
+			if attempt >= attempts {
+				return err
+			}
+
+			timer := time.NewTimer(wait)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return ctx.Err()
+			case <-timer.C:
+			}
+			wait *= 2
+		}
+	}
+}
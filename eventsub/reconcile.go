@@ -0,0 +1,293 @@
+package eventsub
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aidenwallis/go-twitch-client/helix"
+)
+
+// defaultReconcileInterval is how often Reconciler.Run reconciles subscriptions by default
+const defaultReconcileInterval = 5 * time.Minute
+
+// DesiredSubscription is a subscription that the Reconciler ensures exists
+type DesiredSubscription struct {
+	// Condition is the typed condition of the subscription, it also defines the subscription type and version.
+	Condition helix.EventSubCondition
+
+	// Transport is the transport notifications are delivered over.
+	Transport *helix.EventSubTransport
+}
+
+// ReconcilerOptions defines all options the reconciler supports.
+type ReconcilerOptions struct {
+	// Helix is used to list, create and delete subscriptions.
+	Helix helix.Client
+
+	// Subscriptions is the desired set of subscriptions, it can be replaced later through Reconciler.SetSubscriptions.
+	Subscriptions []*DesiredSubscription
+
+	// Managed (optional) limits which existing subscriptions the reconciler manages, subscriptions it returns false
+	// for are never deleted. Defaults to managing every subscription of the client ID.
+	Managed func(subscription *helix.EventSubSubscription) bool
+
+	// DryRun (optional) computes the report without creating or deleting any subscriptions.
+	DryRun bool
+
+	// Interval (optional) is how often Run reconciles, defaults to 5 minutes.
+	Interval time.Duration
+}
+
+// ReconcileReport is the diff between the desired and actual subscriptions, and what was done about it
+type ReconcileReport struct {
+	// DryRun is whether the changes were only computed, and not applied.
+	DryRun bool
+
+	// Created contains the desired subscriptions that were missing, or failed, and were created.
+	Created []*DesiredSubscription
+
+	// Deleted contains the subscriptions that were stale, duplicated or failed, and were deleted.
+	Deleted []*helix.EventSubSubscription
+
+	// Kept contains the subscriptions that matched a desired subscription, and were left alone.
+	Kept []*helix.EventSubSubscription
+
+	// Errors contains the creations and deletions that failed, the others are still applied. Desired subscriptions
+	// that are nil, or have no condition or transport, are reported here and otherwise ignored.
+	Errors []error
+}
+
+// Reconciler keeps the EventSub subscriptions of a client ID in line with a desired set.
+//
+// Subscriptions that are missing are created, and subscriptions that are not desired, duplicated, or in a failed
+// state are deleted. Failed subscriptions that are desired are created again.
+type Reconciler struct {
+	helix    helix.Client
+	managed  func(subscription *helix.EventSubSubscription) bool
+	dryRun   bool
+	interval time.Duration
+	trigger  chan struct{}
+
+	// reconcileMu serializes reconciles, so that on demand reconciles don't race the periodic ones
+	reconcileMu sync.Mutex
+
+	mu            sync.Mutex
+	subscriptions []*DesiredSubscription
+
+	onReport func(report *ReconcileReport, err error)
+}
+
+// NewReconciler creates a new instance of Reconciler
+func NewReconciler(options *ReconcilerOptions) *Reconciler {
+	r := &Reconciler{
+		helix:         options.Helix,
+		managed:       options.Managed,
+		dryRun:        options.DryRun,
+		interval:      defaultReconcileInterval,
+		trigger:       make(chan struct{}, 1),
+		subscriptions: options.Subscriptions,
+	}
+	if options.Interval > 0 {
+		r.interval = options.Interval
+	}
+	return r
+}
+
+// SetSubscriptions replaces the desired set of subscriptions, it takes effect on the next reconcile.
+func (r *Reconciler) SetSubscriptions(subscriptions []*DesiredSubscription) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.subscriptions = subscriptions
+}
+
+// OnReport sets the handler called with the outcome of every reconcile made by Run.
+//
+// Handlers must be registered before calling Run.
+func (r *Reconciler) OnReport(handler func(report *ReconcileReport, err error)) {
+	r.onReport = handler
+}
+
+// Trigger makes a running Run reconcile immediately, instead of waiting for the next interval.
+func (r *Reconciler) Trigger() {
+	select {
+	case r.trigger <- struct{}{}:
+	default:
+	}
+}
+
+// Run reconciles immediately, then every interval or whenever Trigger is called, until ctx is cancelled.
+func (r *Reconciler) Run(ctx context.Context) error {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		report, err := r.Reconcile(ctx)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if r.onReport != nil {
+			r.onReport(report, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		case <-r.trigger:
+		}
+	}
+}
+
+// Reconcile lists the actual subscriptions, and creates and deletes subscriptions until they match the desired set.
+// An error is only returned when the subscriptions could not be listed, failed changes are reported in
+// ReconcileReport.Errors.
+func (r *Reconciler) Reconcile(ctx context.Context) (*ReconcileReport, error) {
+	r.reconcileMu.Lock()
+	defer r.reconcileMu.Unlock()
+
+	r.mu.Lock()
+	desired := r.subscriptions
+	r.mu.Unlock()
+
+	actual, err := r.list(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ReconcileReport{DryRun: r.dryRun}
+
+	wanted := make(map[string]*DesiredSubscription, len(desired))
+	var order []string
+	for i, subscription := range desired {
+		key, err := desiredKey(subscription)
+		if err != nil {
+			report.Errors = append(report.Errors, fmt.Errorf("eventsub: desired subscription %d: %w", i, err))
+			continue
+		}
+		if _, ok := wanted[key]; !ok {
+			order = append(order, key)
+		}
+		wanted[key] = subscription
+	}
+
+	satisfied := make(map[string]bool, len(wanted))
+	for _, subscription := range actual {
+		if r.managed != nil && !r.managed(subscription) {
+			continue
+		}
+
+		key := subscriptionKey(subscription.Type, subscription.Version, subscription.Condition, &subscription.Transport)
+		if _, ok := wanted[key]; ok && !satisfied[key] && healthy(subscription.Status) {
+			satisfied[key] = true
+			report.Kept = append(report.Kept, subscription)
+			continue
+		}
+
+		report.Deleted = append(report.Deleted, subscription)
+	}
+
+	for _, key := range order {
+		if !satisfied[key] {
+			report.Created = append(report.Created, wanted[key])
+		}
+	}
+
+	if r.dryRun {
+		return report, nil
+	}
+
+	for _, subscription := range report.Deleted {
+		if err := r.helix.DeleteEventSubSubscription(ctx, &helix.DeleteEventSubSubscriptionRequest{ID: subscription.ID}); err != nil {
+			report.Errors = append(report.Errors, fmt.Errorf("eventsub: deleting %s subscription %s: %w", subscription.Type, subscription.ID, err))
+		}
+	}
+
+	for _, subscription := range report.Created {
+		_, err := r.helix.CreateEventSubSubscription(ctx, &helix.CreateEventSubSubscriptionRequest{
+			Condition: subscription.Condition,
+			Transport: subscription.Transport,
+		})
+		if err != nil {
+			report.Errors = append(report.Errors, fmt.Errorf("eventsub: creating %s subscription: %w", subscription.Condition.EventSubType(), err))
+		}
+	}
+
+	return report, nil
+}
+
+// list returns every subscription of the client ID, following pagination.
+func (r *Reconciler) list(ctx context.Context) ([]*helix.EventSubSubscription, error) {
+	var subscriptions []*helix.EventSubSubscription
+	cursor := ""
+	for {
+		resp, err := r.helix.GetEventSubSubscriptions(ctx, &helix.GetEventSubSubscriptionsRequest{After: cursor})
+		if err != nil {
+			return nil, err
+		}
+
+		subscriptions = append(subscriptions, resp.Data...)
+		if resp.Pagination.Cursor == "" || len(resp.Data) == 0 {
+			return subscriptions, nil
+		}
+		cursor = resp.Pagination.Cursor
+	}
+}
+
+// healthy returns whether a subscription with the status delivers, or is about to deliver, notifications.
+func healthy(status string) bool {
+	return status == helix.EventSubStatusEnabled || status == helix.EventSubStatusWebhookCallbackVerificationPending
+}
+
+// desiredKey returns the subscriptionKey of a desired subscription, helix.ErrMissingEventSubCondition is returned when
+// it is nil, or has no condition or transport.
+func desiredKey(subscription *DesiredSubscription) (string, error) {
+	if subscription == nil || subscription.Condition == nil || subscription.Transport == nil {
+		return "", helix.ErrMissingEventSubCondition
+	}
+
+	bs, err := json.Marshal(subscription.Condition)
+	if err != nil {
+		return "", err
+	}
+
+	condition := map[string]string{}
+	if err := json.Unmarshal(bs, &condition); err != nil {
+		return "", err
+	}
+
+	return subscriptionKey(
+		subscription.Condition.EventSubType(),
+		subscription.Condition.EventSubVersion(),
+		condition,
+		subscription.Transport,
+	), nil
+}
+
+// subscriptionKey identifies a subscription by its type, version, condition and transport destination. Empty
+// condition values are dropped, as Twitch omits them from returned subscriptions.
+func subscriptionKey(subscriptionType, version string, condition map[string]string, transport *helix.EventSubTransport) string {
+	var b strings.Builder
+	b.WriteString(subscriptionType + "\x00" + version)
+
+	keys := make([]string, 0, len(condition))
+	for k, v := range condition {
+		if v != "" {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		b.WriteString("\x00" + k + "=" + condition[k])
+	}
+
+	if transport != nil {
+		b.WriteString("\x00" + transport.Method + "\x00" + transport.Callback + transport.SessionID + transport.ConduitID)
+	}
+	return b.String()
+}
@@ -0,0 +1,223 @@
+package eventsub
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aidenwallis/go-twitch-client/helix"
+	"github.com/aidenwallis/go-twitch-client/internal/testutils"
+	"github.com/aidenwallis/go-twitch-client/internal/testutils/assert"
+)
+
+// subscriptionsAPI fakes the subscription endpoints, serving one subscription per page
+type subscriptionsAPI struct {
+	t             *testing.T
+	mu            sync.Mutex
+	subscriptions []*helix.EventSubSubscription
+	created       []string
+	deleted       []string
+	failDelete    string
+}
+
+func (a *subscriptionsAPI) client() helix.Client {
+	return helix.NewClient(&helix.ClientOptions{
+		ClientID: "clientID",
+		Transport: testutils.Middleware(func(req *http.Request) *testutils.Response {
+			a.mu.Lock()
+			defer a.mu.Unlock()
+
+			switch req.Method {
+			case http.MethodGet:
+				page := 0
+				if after := req.URL.Query().Get("after"); after != "" {
+					assert.NoError(a.t, json.Unmarshal([]byte(after), &page))
+				}
+
+				resp := &helix.GetEventSubSubscriptionsResponse{}
+				if page < len(a.subscriptions) {
+					resp.Data = a.subscriptions[page : page+1]
+					if page+1 < len(a.subscriptions) {
+						bs, _ := json.Marshal(page + 1)
+						resp.Pagination.Cursor = string(bs)
+					}
+				}
+				return testutils.JSONResponse(a.t, http.StatusOK, resp)
+
+			case http.MethodDelete:
+				id := req.URL.Query().Get("id")
+				if id == a.failDelete {
+					return testutils.EmptyResponse(http.StatusInternalServerError)
+				}
+				a.deleted = append(a.deleted, id)
+				return testutils.EmptyResponse(http.StatusNoContent)
+
+			case http.MethodPost:
+				a.created = append(a.created, testutils.DecodeRawBody(a.t, req))
+				return testutils.JSONResponse(a.t, http.StatusAccepted, &helix.CreateEventSubSubscriptionResponse{})
+			}
+
+			a.t.Errorf("unexpected request %s %s", req.Method, req.URL)
+			return testutils.EmptyResponse(http.StatusNotFound)
+		}),
+		AccessTokenLoader: func(ctx context.Context) (string, error) {
+			return "abc123", nil
+		},
+	})
+}
+
+const callback = "https://example.com/webhook"
+
+func subscription(id, status, subscriptionType, broadcasterID string) *helix.EventSubSubscription {
+	return &helix.EventSubSubscription{
+		ID:        id,
+		Status:    status,
+		Type:      subscriptionType,
+		Version:   "1",
+		Condition: map[string]string{"broadcaster_user_id": broadcasterID},
+		Transport: helix.EventSubTransport{Method: helix.EventSubTransportWebhook, Callback: callback},
+	}
+}
+
+func desired() []*DesiredSubscription {
+	transport := helix.WebhookTransport(callback, "supersecret")
+	return []*DesiredSubscription{
+		{Condition: &helix.StreamOnlineCondition{BroadcasterUserID: "1"}, Transport: transport},
+		{Condition: &helix.StreamOfflineCondition{BroadcasterUserID: "1"}, Transport: transport},
+		{Condition: &helix.ChannelRaidCondition{ToBroadcasterUserID: "1"}, Transport: transport},
+		{Condition: &helix.StreamOnlineCondition{BroadcasterUserID: "2"}, Transport: transport},
+	}
+}
+
+func actual() []*helix.EventSubSubscription {
+	raid := subscription("raid", helix.EventSubStatusEnabled, helix.EventSubTypeChannelRaid, "")
+	raid.Condition = map[string]string{"from_broadcaster_user_id": "", "to_broadcaster_user_id": "1"}
+
+	return []*helix.EventSubSubscription{
+		subscription("online", helix.EventSubStatusEnabled, helix.EventSubTypeStreamOnline, "1"),
+		subscription("online-duplicate", helix.EventSubStatusEnabled, helix.EventSubTypeStreamOnline, "1"),
+		subscription("offline-failed", helix.EventSubStatusWebhookCallbackVerificationFailed, helix.EventSubTypeStreamOffline, "1"),
+		subscription("orphan", helix.EventSubStatusEnabled, helix.EventSubTypeStreamOnline, "3"),
+		raid,
+	}
+}
+
+func TestReconciler(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("reconcile", func(t *testing.T) {
+		api := &subscriptionsAPI{t: t, subscriptions: actual()}
+		r := NewReconciler(&ReconcilerOptions{Helix: api.client(), Subscriptions: desired()})
+
+		report, err := r.Reconcile(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, false, report.DryRun)
+		assert.Equal(t, 0, len(report.Errors))
+
+		assert.Equal(t, 2, len(report.Kept))
+		assert.Equal(t, "online", report.Kept[0].ID)
+		assert.Equal(t, "raid", report.Kept[1].ID)
+
+		assert.Equal(t, 3, len(api.deleted))
+		assert.Equal(t, "online-duplicate", api.deleted[0])
+		assert.Equal(t, "offline-failed", api.deleted[1])
+		assert.Equal(t, "orphan", api.deleted[2])
+
+		assert.Equal(t, 2, len(api.created))
+		assert.Equal(t, `{"type":"stream.offline","version":"1","condition":{"broadcaster_user_id":"1"},"transport":{"method":"webhook","callback":"https://example.com/webhook","secret":"supersecret"}}`, api.created[0])
+		assert.Equal(t, `{"type":"stream.online","version":"1","condition":{"broadcaster_user_id":"2"},"transport":{"method":"webhook","callback":"https://example.com/webhook","secret":"supersecret"}}`, api.created[1])
+	})
+
+	t.Run("dry run", func(t *testing.T) {
+		api := &subscriptionsAPI{t: t, subscriptions: actual()}
+		r := NewReconciler(&ReconcilerOptions{Helix: api.client(), Subscriptions: desired(), DryRun: true})
+
+		report, err := r.Reconcile(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, true, report.DryRun)
+		assert.Equal(t, 2, len(report.Created))
+		assert.Equal(t, 3, len(report.Deleted))
+		assert.Equal(t, 0, len(api.created))
+		assert.Equal(t, 0, len(api.deleted))
+	})
+
+	t.Run("managed filter", func(t *testing.T) {
+		api := &subscriptionsAPI{t: t, subscriptions: actual()}
+		r := NewReconciler(&ReconcilerOptions{
+			Helix:         api.client(),
+			Subscriptions: desired(),
+			DryRun:        true,
+			Managed: func(subscription *helix.EventSubSubscription) bool {
+				return subscription.ID != "orphan"
+			},
+		})
+
+		report, err := r.Reconcile(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, len(report.Deleted))
+	})
+
+	t.Run("partial failure", func(t *testing.T) {
+		api := &subscriptionsAPI{t: t, subscriptions: actual(), failDelete: "orphan"}
+		r := NewReconciler(&ReconcilerOptions{Helix: api.client(), Subscriptions: desired()})
+
+		report, err := r.Reconcile(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, len(report.Errors))
+		assert.Equal(t, 2, len(api.deleted))
+		assert.Equal(t, 2, len(api.created))
+	})
+
+	t.Run("invalid desired subscriptions", func(t *testing.T) {
+		api := &subscriptionsAPI{t: t, subscriptions: actual()}
+		subscriptions := append(desired(),
+			nil,
+			&DesiredSubscription{Transport: helix.WebhookTransport(callback, "supersecret")},
+			&DesiredSubscription{Condition: &helix.StreamOnlineCondition{BroadcasterUserID: "4"}},
+		)
+		r := NewReconciler(&ReconcilerOptions{Helix: api.client(), Subscriptions: subscriptions})
+
+		report, err := r.Reconcile(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, len(report.Errors))
+		for _, err := range report.Errors {
+			assert.ErrorIs(t, err, helix.ErrMissingEventSubCondition)
+		}
+		assert.Equal(t, "eventsub: desired subscription 4: "+helix.ErrMissingEventSubCondition.Error(), report.Errors[0].Error())
+
+		// the valid subscriptions are still reconciled
+		assert.Equal(t, 2, len(report.Kept))
+		assert.Equal(t, 2, len(api.created))
+	})
+
+	t.Run("run", func(t *testing.T) {
+		api := &subscriptionsAPI{t: t}
+		r := NewReconciler(&ReconcilerOptions{Helix: api.client(), Interval: time.Hour})
+
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		reports := make(chan *ReconcileReport)
+		r.OnReport(func(report *ReconcileReport, err error) {
+			assert.NoError(t, err)
+			reports <- report
+		})
+
+		done := make(chan error)
+		go func() {
+			done <- r.Run(ctx)
+		}()
+
+		assert.Equal(t, 0, len((<-reports).Created))
+
+		r.SetSubscriptions(desired()[:1])
+		r.Trigger()
+		assert.Equal(t, 1, len((<-reports).Created))
+
+		cancel()
+		assert.ErrorIs(t, <-done, context.Canceled)
+	})
+}
@@ -0,0 +1,248 @@
+package eventsub
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MessageStore records the IDs of processed messages, so that messages resent by Twitch are only processed once.
+//
+// Implementations must be safe for concurrent use. Sharing a durable implementation between replicas, or across
+// restarts, makes deduplication hold for all of them.
+type MessageStore interface {
+	// Seen returns whether the message ID was recorded as processed, and has not expired.
+	Seen(ctx context.Context, messageID string) (bool, error)
+
+	// Mark records the message ID as processed until the ttl elapses.
+	Mark(ctx context.Context, messageID string, ttl time.Duration) error
+}
+
+// memorySweepInterval is how often expired entries are swept from the in-memory store
+const memorySweepInterval = time.Minute
+
+// MemoryMessageStore is a MessageStore that keeps message IDs in memory, it does not survive restarts.
+type MemoryMessageStore struct {
+	mu        sync.Mutex
+	entries   map[string]time.Time
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// NewMemoryMessageStore creates a new instance of MemoryMessageStore
+func NewMemoryMessageStore() *MemoryMessageStore {
+	return &MemoryMessageStore{entries: map[string]time.Time{}, now: time.Now}
+}
+
+// Seen implements MessageStore
+func (s *MemoryMessageStore) Seen(ctx context.Context, messageID string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	expiresAt, ok := s.entries[messageID]
+	return ok && s.now().Before(expiresAt), nil
+}
+
+// Mark implements MessageStore. Expired entries are periodically swept as new ones are added.
+func (s *MemoryMessageStore) Mark(ctx context.Context, messageID string, ttl time.Duration) error {
+	s.mark(messageID, s.now().Add(ttl))
+	return nil
+}
+
+func (s *MemoryMessageStore) mark(messageID string, expiresAt time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if now.Sub(s.lastSweep) >= memorySweepInterval {
+		for k, v := range s.entries {
+			if !now.Before(v) {
+				delete(s.entries, k)
+			}
+		}
+		s.lastSweep = now
+	}
+	s.entries[messageID] = expiresAt
+}
+
+// size returns the number of entries, including expired entries that have not been swept yet.
+func (s *MemoryMessageStore) size() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.entries)
+}
+
+// live returns the entries that have not expired.
+func (s *MemoryMessageStore) live() map[string]time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	live := make(map[string]time.Time, len(s.entries))
+	for k, v := range s.entries {
+		if now.Before(v) {
+			live[k] = v
+		}
+	}
+	return live
+}
+
+// minFileCompactLines is the number of appended lines after which the file store may be compacted
+const minFileCompactLines = 1024
+
+// errInvalidMessageID is returned when a message ID can't be stored in a file store
+var errInvalidMessageID = errors.New("eventsub: message IDs may not contain line breaks")
+
+// FileMessageStore is a MessageStore that persists message IDs to a file, so that deduplication survives restarts.
+//
+// Every processed message is appended to the file and synced, and the file is compacted once it mostly contains
+// expired entries. A file must only be opened by one FileMessageStore at a time.
+type FileMessageStore struct {
+	path   string
+	memory *MemoryMessageStore
+
+	mu    sync.Mutex
+	file  *os.File
+	lines int
+}
+
+// NewFileMessageStore opens the file store at path, creating it if it does not exist, and loads the message IDs
+// that have not expired.
+func NewFileMessageStore(path string) (*FileMessageStore, error) {
+	s := &FileMessageStore{path: path, memory: NewMemoryMessageStore()}
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+	if err := s.compact(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Seen implements MessageStore
+func (s *FileMessageStore) Seen(ctx context.Context, messageID string) (bool, error) {
+	return s.memory.Seen(ctx, messageID)
+}
+
+// Mark implements MessageStore. The message ID is synced to disk before Mark returns.
+func (s *FileMessageStore) Mark(ctx context.Context, messageID string, ttl time.Duration) error {
+	if strings.ContainsAny(messageID, "\r\n") {
+		return errInvalidMessageID
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.file == nil {
+		return os.ErrClosed
+	}
+
+	expiresAt := s.memory.now().Add(ttl)
+	if _, err := fmt.Fprintf(s.file, "%d %s\n", expiresAt.UnixNano(), messageID); err != nil {
+		return err
+	}
+	if err := s.file.Sync(); err != nil {
+		return err
+	}
+	s.memory.mark(messageID, expiresAt)
+	s.lines++
+
+	if s.lines >= minFileCompactLines && s.lines > 2*s.memory.size() {
+		return s.compactLocked()
+	}
+	return nil
+}
+
+// Close closes the underlying file.
+func (s *FileMessageStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.file == nil {
+		return nil
+	}
+	err := s.file.Close()
+	s.file = nil
+	return err
+}
+
+// load reads the entries of the file into memory, malformed lines are skipped.
+func (s *FileMessageStore) load() error {
+	f, err := os.Open(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		expiresAt, messageID, ok := strings.Cut(scanner.Text(), " ")
+		if !ok || messageID == "" {
+			continue
+		}
+		nanos, err := strconv.ParseInt(expiresAt, 10, 64)
+		if err != nil {
+			continue
+		}
+		s.memory.mark(messageID, time.Unix(0, nanos))
+	}
+	return scanner.Err()
+}
+
+func (s *FileMessageStore) compact() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.compactLocked()
+}
+
+// compactLocked rewrites the file with only the entries that have not expired, then reopens it for appending.
+func (s *FileMessageStore) compactLocked() error {
+	live := s.memory.live()
+
+	tmp := s.path + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+
+	w := bufio.NewWriter(f)
+	for messageID, expiresAt := range live {
+		fmt.Fprintf(w, "%d %s\n", expiresAt.UnixNano(), messageID)
+	}
+	if err := w.Flush(); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(s.path, os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return err
+	}
+	if s.file != nil {
+		_ = s.file.Close()
+	}
+	s.file = file
+	s.lines = len(live)
+	return nil
+}
@@ -0,0 +1,169 @@
+package eventsub
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aidenwallis/go-twitch-client/internal/testutils/assert"
+)
+
+func TestMemoryMessageStore(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	s := NewMemoryMessageStore()
+	s.now = func() time.Time { return now }
+
+	assert.NoError(t, s.Mark(ctx, "a", time.Minute))
+	seen, err := s.Seen(ctx, "a")
+	assert.NoError(t, err)
+	assert.Equal(t, true, seen)
+
+	now = now.Add(time.Minute)
+	seen, _ = s.Seen(ctx, "a")
+	assert.Equal(t, false, seen)
+
+	// expired entries are swept
+	now = now.Add(memorySweepInterval)
+	assert.NoError(t, s.Mark(ctx, "b", time.Minute))
+	assert.Equal(t, 1, s.size())
+}
+
+func TestFileMessageStore(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "messages")
+
+	s, err := NewFileMessageStore(path)
+	assert.NoError(t, err)
+	assert.NoError(t, s.Mark(ctx, "a", time.Hour))
+	assert.NoError(t, s.Mark(ctx, "expired", -time.Second))
+	assert.ErrorIs(t, s.Mark(ctx, "new\nline", time.Hour), errInvalidMessageID)
+	assert.NoError(t, s.Close())
+	assert.ErrorIs(t, s.Mark(ctx, "b", time.Hour), os.ErrClosed)
+
+	// message IDs survive a restart, expired ones are compacted away
+	s, err = NewFileMessageStore(path)
+	assert.NoError(t, err)
+	defer s.Close()
+
+	seen, err := s.Seen(ctx, "a")
+	assert.NoError(t, err)
+	assert.Equal(t, true, seen)
+
+	seen, _ = s.Seen(ctx, "expired")
+	assert.Equal(t, false, seen)
+
+	bs, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, strings.Count(string(bs), "\n"))
+}
+
+func TestFileMessageStoreCompaction(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "messages")
+
+	s, err := NewFileMessageStore(path)
+	assert.NoError(t, err)
+	defer s.Close()
+
+	for i := 0; i < minFileCompactLines; i++ {
+		assert.NoError(t, s.Mark(ctx, "same", time.Hour))
+	}
+
+	bs, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "same", strings.Fields(string(bs))[1])
+	assert.Equal(t, 1, strings.Count(string(bs), "\n"))
+}
+
+// failingStore is a MessageStore whose operations always fail
+type failingStore struct{}
+
+var errStore = errors.New("store unavailable")
+
+func (failingStore) Seen(ctx context.Context, messageID string) (bool, error) {
+	return false, errStore
+}
+
+func (failingStore) Mark(ctx context.Context, messageID string, ttl time.Duration) error {
+	return errStore
+}
+
+func TestWebhookHandlerMessageStore(t *testing.T) {
+	store := NewMemoryMessageStore()
+	assert.NoError(t, store.Mark(context.Background(), "processed", time.Minute))
+
+	h := NewWebhookHandler(&WebhookHandlerOptions{Secret: fakeSecret, MessageStore: store})
+	h.OnNotification(func(ctx context.Context, n *Notification) error {
+		t.Error("notification should not be dispatched")
+		return nil
+	})
+	w := serve(h, webhookRequest(fakeSecret, MessageTypeNotification, "processed", time.Now(), notificationBody))
+	assert.Equal(t, http.StatusNoContent, w.Code)
+
+	h = NewWebhookHandler(&WebhookHandlerOptions{Secret: fakeSecret, MessageStore: failingStore{}})
+	w = serve(h, webhookRequest(fakeSecret, MessageTypeNotification, "msg", time.Now(), notificationBody))
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+func TestProcess(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("retries until success", func(t *testing.T) {
+		calls := 0
+		handler := Process(func(ctx context.Context, n *Notification) error {
+			calls++
+			if calls < 3 {
+				return errors.New("failed")
+			}
+			return nil
+		}, &ProcessorOptions{Backoff: time.Millisecond})
+
+		assert.NoError(t, handler(ctx, &Notification{MessageID: "msg"}))
+		assert.Equal(t, 3, calls)
+
+		// processed messages are acknowledged without calling the handler
+		assert.NoError(t, handler(ctx, &Notification{MessageID: "msg"}))
+		assert.Equal(t, 3, calls)
+	})
+
+	t.Run("gives up", func(t *testing.T) {
+		failed := errors.New("failed")
+		calls := 0
+		store := NewMemoryMessageStore()
+		handler := Process(func(ctx context.Context, n *Notification) error {
+			calls++
+			return failed
+		}, &ProcessorOptions{Store: store, MaxAttempts: 2, Backoff: time.Millisecond})
+
+		assert.ErrorIs(t, handler(ctx, &Notification{MessageID: "msg"}), failed)
+		assert.Equal(t, 2, calls)
+
+		seen, _ := store.Seen(ctx, "msg")
+		assert.Equal(t, false, seen)
+	})
+
+	t.Run("cancelled during backoff", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(ctx)
+		handler := Process(func(ctx context.Context, n *Notification) error {
+			cancel()
+			return errors.New("failed")
+		}, &ProcessorOptions{Backoff: time.Hour})
+
+		assert.ErrorIs(t, handler(ctx, &Notification{MessageID: "msg"}), context.Canceled)
+	})
+
+	t.Run("store error", func(t *testing.T) {
+		handler := Process(func(ctx context.Context, n *Notification) error {
+			t.Error("handler should not be called")
+			return nil
+		}, &ProcessorOptions{Store: failingStore{}})
+
+		assert.ErrorIs(t, handler(ctx, &Notification{MessageID: "msg"}), errStore)
+	})
+}
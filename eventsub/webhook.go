@@ -0,0 +1,213 @@
+package eventsub
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+)
+
+// Webhook request headers, see: https://dev.twitch.tv/docs/eventsub/handling-webhook-events#list-of-request-headers
+const (
+	// HeaderMessageID is an ID that uniquely identifies the message.
+	HeaderMessageID = "Twitch-Eventsub-Message-Id"
+
+	// HeaderMessageRetry is the number of times Twitch has tried to send the message.
+	HeaderMessageRetry = "Twitch-Eventsub-Message-Retry"
+
+	// HeaderMessageType is the type of the message, see the MessageType constants.
+	HeaderMessageType = "Twitch-Eventsub-Message-Type"
+
+	// HeaderMessageSignature is the HMAC-SHA256 signature of the message, used to verify Twitch sent it.
+	HeaderMessageSignature = "Twitch-Eventsub-Message-Signature"
+
+	// HeaderMessageTimestamp is the RFC3339 timestamp of when Twitch sent the message.
+	HeaderMessageTimestamp = "Twitch-Eventsub-Message-Timestamp"
+
+	// HeaderSubscriptionType is the subscription type the message is for.
+	HeaderSubscriptionType = "Twitch-Eventsub-Subscription-Type"
+
+	// HeaderSubscriptionVersion is the version of the subscription type the message is for.
+	HeaderSubscriptionVersion = "Twitch-Eventsub-Subscription-Version"
+)
+
+const (
+	// defaultMaxMessageAge is the age after which Twitch recommends rejecting messages, to prevent replay attacks.
+	defaultMaxMessageAge = 10 * time.Minute
+
+	// maxWebhookBodySize is the largest webhook body that is read.
+	maxWebhookBodySize = 1 << 20
+
+	signaturePrefix = "sha256="
+)
+
+// WebhookHandlerOptions defines all options the webhook handler supports.
+type WebhookHandlerOptions struct {
+	// Secret is the secret you passed in the webhook transport when creating subscriptions, it is used to
+	// verify that messages were sent by Twitch. It is required, without it every request is answered with a 500
+	// response, as any signature could be forged.
+	Secret string
+
+	// MaxMessageAge (optional) is the age after which messages are rejected as stale, preventing replay attacks.
+	// Defaults to 10 minutes. Processed message IDs are remembered for this long so that resent messages are
+	// only dispatched once.
+	MaxMessageAge time.Duration
+
+	// MessageStore (optional) records the processed message IDs, defaults to a new MemoryMessageStore. Use a durable
+	// store shared between replicas for deduplication to hold across restarts and replicas.
+	MessageStore MessageStore
+}
+
+// WebhookHandler is a http.Handler that receives EventSub webhook messages.
+//
+// It verifies the HMAC-SHA256 signature of every message, rejects stale messages, answers callback verification
+// challenges, and dispatches notifications and revocations to the registered handlers. Notifications are
+// deduplicated on their message ID, which is only recorded in the MessageStore once the handler succeeds.
+type WebhookHandler struct {
+	secret        []byte
+	maxMessageAge time.Duration
+	store         MessageStore
+	now           func() time.Time
+
+	onNotification NotificationHandler
+	onRevocation   RevocationHandler
+}
+
+// NewWebhookHandler creates a new instance of WebhookHandler
+func NewWebhookHandler(options *WebhookHandlerOptions) *WebhookHandler {
+	if options == nil {
+		options = &WebhookHandlerOptions{}
+	}
+
+	h := &WebhookHandler{
+		secret:        []byte(options.Secret),
+		maxMessageAge: defaultMaxMessageAge,
+		store:         options.MessageStore,
+		now:           time.Now,
+	}
+	if options.MaxMessageAge > 0 {
+		h.maxMessageAge = options.MaxMessageAge
+	}
+	if h.store == nil {
+		h.store = NewMemoryMessageStore()
+	}
+	return h
+}
+
+// OnNotification sets the handler that verified notifications are dispatched to. When the handler returns an error,
+// Twitch is sent a 500 response so that it resends the notification later.
+//
+// Handlers must be registered before the WebhookHandler starts serving requests.
+func (h *WebhookHandler) OnNotification(handler NotificationHandler) {
+	h.onNotification = handler
+}
+
+// OnRevocation sets the handler that revoked subscriptions are dispatched to.
+//
+// Handlers must be registered before the WebhookHandler starts serving requests.
+func (h *WebhookHandler) OnRevocation(handler RevocationHandler) {
+	h.onRevocation = handler
+}
+
+// ServeHTTP implements http.Handler
+func (h *WebhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	// an empty key would let anyone compute a valid signature
+	if len(h.secret) == 0 {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxWebhookBodySize))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	messageID := r.Header.Get(HeaderMessageID)
+	timestamp := r.Header.Get(HeaderMessageTimestamp)
+	if messageID == "" || !h.validSignature(messageID, timestamp, body, r.Header.Get(HeaderMessageSignature)) {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	sentAt, err := time.Parse(time.RFC3339Nano, timestamp)
+	if err != nil || h.now().Sub(sentAt) > h.maxMessageAge {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	var p payload
+	if err := json.Unmarshal(body, &p); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	switch r.Header.Get(HeaderMessageType) {
+	case MessageTypeWebhookCallbackVerification:
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(http.StatusOK)
+		_, _ = io.WriteString(w, p.Challenge)
+		return
+
+	case MessageTypeRevocation:
+		if h.onRevocation != nil && p.Subscription != nil {
+			h.onRevocation(r.Context(), p.Subscription)
+		}
+
+	case MessageTypeNotification:
+		seen, err := h.store.Seen(r.Context(), messageID)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		if seen {
+			break
+		}
+
+		if h.onNotification != nil {
+			err := h.onNotification(r.Context(), &Notification{
+				MessageID:    messageID,
+				MessageType:  MessageTypeNotification,
+				Timestamp:    sentAt,
+				Subscription: p.Subscription,
+				Event:        p.Event,
+			})
+			if err != nil {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+		}
+		if err := h.store.Mark(r.Context(), messageID, h.maxMessageAge); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// validSignature checks the message signature, which is the HMAC-SHA256 of the message ID, timestamp and body.
+func (h *WebhookHandler) validSignature(messageID, timestamp string, body []byte, signature string) bool {
+	if len(signature) <= len(signaturePrefix) || signature[:len(signaturePrefix)] != signaturePrefix {
+		return false
+	}
+
+	expected, err := hex.DecodeString(signature[len(signaturePrefix):])
+	if err != nil {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, h.secret)
+	mac.Write([]byte(messageID))
+	mac.Write([]byte(timestamp))
+	mac.Write(body)
+	return hmac.Equal(mac.Sum(nil), expected)
+}
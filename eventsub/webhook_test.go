@@ -0,0 +1,167 @@
+package eventsub
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aidenwallis/go-twitch-client/helix"
+	"github.com/aidenwallis/go-twitch-client/internal/testutils/assert"
+)
+
+const fakeSecret = "supersecret"
+
+func webhookRequest(secret, messageType, messageID string, sentAt time.Time, body string) *http.Request {
+	timestamp := sentAt.UTC().Format(time.RFC3339Nano)
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(messageID + timestamp + body))
+
+	req := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(body))
+	req.Header.Set(HeaderMessageID, messageID)
+	req.Header.Set(HeaderMessageType, messageType)
+	req.Header.Set(HeaderMessageTimestamp, timestamp)
+	req.Header.Set(HeaderMessageSignature, signaturePrefix+hex.EncodeToString(mac.Sum(nil)))
+	return req
+}
+
+const notificationBody = `{"subscription":{"id":"sub","status":"enabled","type":"stream.online","version":"1","condition":{"broadcaster_user_id":"1"},"transport":{"method":"webhook","callback":"https://example.com"},"created_at":"2022-01-01T00:00:00Z","cost":0},"event":{"id":"1","broadcaster_user_id":"1"}}`
+
+func serve(h http.Handler, req *http.Request) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	return w
+}
+
+func TestWebhookHandler(t *testing.T) {
+	t.Run("challenge", func(t *testing.T) {
+		h := NewWebhookHandler(&WebhookHandlerOptions{Secret: fakeSecret})
+		w := serve(h, webhookRequest(fakeSecret, MessageTypeWebhookCallbackVerification, "1", time.Now(), `{"challenge":"pogchamp","subscription":{"id":"sub"}}`))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "pogchamp", w.Body.String())
+		assert.Equal(t, "text/plain", w.Header().Get("Content-Type"))
+	})
+
+	t.Run("notification dispatch and dedupe", func(t *testing.T) {
+		calls := 0
+		h := NewWebhookHandler(&WebhookHandlerOptions{Secret: fakeSecret})
+		h.OnNotification(func(ctx context.Context, n *Notification) error {
+			calls++
+			assert.Equal(t, "msg", n.MessageID)
+			assert.Equal(t, helix.EventSubTypeStreamOnline, n.Subscription.Type)
+			assert.Equal(t, `{"id":"1","broadcaster_user_id":"1"}`, string(n.Event))
+			return nil
+		})
+
+		for i := 0; i < 2; i++ {
+			w := serve(h, webhookRequest(fakeSecret, MessageTypeNotification, "msg", time.Now(), notificationBody))
+			assert.Equal(t, http.StatusNoContent, w.Code)
+		}
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("handler error is retried", func(t *testing.T) {
+		calls := 0
+		h := NewWebhookHandler(&WebhookHandlerOptions{Secret: fakeSecret})
+		h.OnNotification(func(ctx context.Context, n *Notification) error {
+			calls++
+			if calls == 1 {
+				return errors.New("failed")
+			}
+			return nil
+		})
+
+		w := serve(h, webhookRequest(fakeSecret, MessageTypeNotification, "msg", time.Now(), notificationBody))
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+		w = serve(h, webhookRequest(fakeSecret, MessageTypeNotification, "msg", time.Now(), notificationBody))
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("revocation", func(t *testing.T) {
+		var revoked *helix.EventSubSubscription
+		h := NewWebhookHandler(&WebhookHandlerOptions{Secret: fakeSecret})
+		h.OnRevocation(func(ctx context.Context, sub *helix.EventSubSubscription) {
+			revoked = sub
+		})
+
+		w := serve(h, webhookRequest(fakeSecret, MessageTypeRevocation, "msg", time.Now(), `{"subscription":{"id":"sub","status":"authorization_revoked"}}`))
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		assert.Equal(t, helix.EventSubStatusAuthorizationRevoked, revoked.Status)
+	})
+
+	t.Run("rejections", func(t *testing.T) {
+		h := NewWebhookHandler(&WebhookHandlerOptions{Secret: fakeSecret})
+		h.OnNotification(func(ctx context.Context, n *Notification) error {
+			t.Error("notification should not be dispatched")
+			return nil
+		})
+
+		// invalid signature
+		w := serve(h, webhookRequest("wrongsecret", MessageTypeNotification, "msg", time.Now(), notificationBody))
+		assert.Equal(t, http.StatusForbidden, w.Code)
+
+		// malformed signature
+		req := webhookRequest(fakeSecret, MessageTypeNotification, "msg", time.Now(), notificationBody)
+		req.Header.Set(HeaderMessageSignature, "sha256=zz")
+		assert.Equal(t, http.StatusForbidden, serve(h, req).Code)
+
+		// stale
+		w = serve(h, webhookRequest(fakeSecret, MessageTypeNotification, "msg", time.Now().Add(-time.Hour), notificationBody))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		// invalid body
+		w = serve(h, webhookRequest(fakeSecret, MessageTypeNotification, "msg", time.Now(), `{`))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		// method
+		w = serve(h, httptest.NewRequest(http.MethodGet, "/webhook", nil))
+		assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+	})
+
+	t.Run("missing secret", func(t *testing.T) {
+		h := NewWebhookHandler(&WebhookHandlerOptions{})
+		h.OnNotification(func(ctx context.Context, n *Notification) error {
+			t.Error("notification should not be dispatched")
+			return nil
+		})
+
+		// a signature computed with an empty key is not accepted
+		w := serve(h, webhookRequest("", MessageTypeNotification, "msg", time.Now(), notificationBody))
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+		w = serve(NewWebhookHandler(nil), webhookRequest("", MessageTypeWebhookCallbackVerification, "1", time.Now(), `{"challenge":"pogchamp"}`))
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+
+	t.Run("http server", func(t *testing.T) {
+		received := make(chan *Notification, 1)
+		h := NewWebhookHandler(&WebhookHandlerOptions{Secret: fakeSecret})
+		h.OnNotification(func(ctx context.Context, n *Notification) error {
+			received <- n
+			return nil
+		})
+
+		srv := httptest.NewServer(h)
+		defer srv.Close()
+
+		req := webhookRequest(fakeSecret, MessageTypeNotification, "msg", time.Now(), notificationBody)
+		req.RequestURI = ""
+		req.URL, _ = req.URL.Parse(srv.URL + "/webhook")
+
+		resp, err := http.DefaultClient.Do(req)
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+		assert.Equal(t, "sub", (<-received).Subscription.ID)
+	})
+}
@@ -0,0 +1,423 @@
+package eventsub
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/aidenwallis/go-twitch-client/helix"
+	"github.com/aidenwallis/go-twitch-client/internal/websocket"
+)
+
+// DefaultWebSocketURL is the EventSub WebSocket server URL
+const DefaultWebSocketURL = "wss://eventsub.wss.twitch.tv/ws"
+
+// WebSocket message types, see: https://dev.twitch.tv/docs/eventsub/handling-websocket-events
+const (
+	// MessageTypeSessionWelcome is the first message sent after connecting, it contains the session ID.
+	MessageTypeSessionWelcome = "session_welcome"
+
+	// MessageTypeSessionKeepalive is sent when no event has been sent within the keepalive timeout.
+	MessageTypeSessionKeepalive = "session_keepalive"
+
+	// MessageTypeSessionReconnect is sent when the edge server is going away, clients must reconnect to the
+	// reconnect URL it contains.
+	MessageTypeSessionReconnect = "session_reconnect"
+)
+
+const (
+	// defaultKeepaliveGrace is how much longer than the keepalive timeout the client waits for a message, before
+	// it considers the connection dead.
+	defaultKeepaliveGrace = 5 * time.Second
+
+	// welcomeTimeout is how long the client waits for the session welcome message after connecting.
+	welcomeTimeout = 10 * time.Second
+
+	// webSocketMessageTTL is how long message IDs are remembered, to deduplicate messages resent across
+	// reconnects.
+	webSocketMessageTTL = 10 * time.Minute
+)
+
+var (
+	// ErrKeepaliveTimeout is returned by WebSocketClient.Connect when no message was received within the keepalive
+	// timeout, meaning the connection is dead.
+	ErrKeepaliveTimeout = errors.New("eventsub: no message received within the keepalive timeout")
+
+	// ErrNoWelcome is returned when the server does not send a session welcome message after connecting.
+	ErrNoWelcome = errors.New("eventsub: session welcome message not received")
+)
+
+// Session is an EventSub WebSocket session
+type Session struct {
+	// ID is the session ID, used as the WebSocket transport session ID when creating subscriptions.
+	ID string `json:"id"`
+
+	// Status of the session, connected or reconnecting.
+	Status string `json:"status"`
+
+	// ConnectedAt is the time the connection was created.
+	ConnectedAt time.Time `json:"connected_at"`
+
+	// KeepaliveTimeoutSeconds is the maximum number of seconds that may pass without a message being sent.
+	// Nil in reconnect messages.
+	KeepaliveTimeoutSeconds *int `json:"keepalive_timeout_seconds"`
+
+	// ReconnectURL is the URL to reconnect to, only set in reconnect messages.
+	ReconnectURL string `json:"reconnect_url"`
+}
+
+// WebSocketClientOptions defines all options the WebSocket client supports.
+type WebSocketClientOptions struct {
+	// URL (optional) is the EventSub WebSocket server URL, defaults to DefaultWebSocketURL.
+	URL string
+
+	// KeepaliveTimeout (optional) is requested as the session keepalive timeout, Twitch accepts 10 to 600 seconds.
+	// Defaults to the server default.
+	KeepaliveTimeout time.Duration
+
+	// Helix (optional) is used to create Subscriptions once the session ID is known. WebSocket subscriptions require
+	// a user access token, so this is typically a helix.Client.ForToken or helix.Client.ForUser view.
+	Helix helix.Client
+
+	// Subscriptions (optional) are created through Helix on every new session. Subscriptions are kept by Twitch
+	// when the client follows a session reconnect, so they are not created again.
+	Subscriptions []helix.EventSubCondition
+
+	// MessageStore (optional) records the processed message IDs, defaults to a new MemoryMessageStore.
+	MessageStore MessageStore
+}
+
+// WebSocketClient receives EventSub notifications over the WebSocket transport.
+//
+// It reads the session welcome, detects dead connections through the keepalive timeout, and follows session
+// reconnects to the new URL, only switching connections once the new session is welcomed so no events are lost.
+// Notifications are deduplicated on their message ID.
+type WebSocketClient struct {
+	url              string
+	keepaliveTimeout time.Duration
+	keepaliveGrace   time.Duration
+	helix            helix.Client
+	subscriptions    []helix.EventSubCondition
+	store            MessageStore
+	now              func() time.Time
+
+	mu      sync.Mutex
+	conn    *websocket.Conn
+	session *Session
+
+	onNotification NotificationHandler
+	onRevocation   RevocationHandler
+	onWelcome      func(ctx context.Context, session *Session)
+	onError        func(err error)
+}
+
+// webSocketMessage is a message sent by the EventSub WebSocket server
+type webSocketMessage struct {
+	Metadata struct {
+		MessageID        string    `json:"message_id"`
+		MessageType      string    `json:"message_type"`
+		MessageTimestamp time.Time `json:"message_timestamp"`
+	} `json:"metadata"`
+
+	Payload struct {
+		payload
+		Session *Session `json:"session"`
+	} `json:"payload"`
+}
+
+// NewWebSocketClient creates a new instance of WebSocketClient
+func NewWebSocketClient(options *WebSocketClientOptions) *WebSocketClient {
+	if options == nil {
+		options = &WebSocketClientOptions{}
+	}
+
+	c := &WebSocketClient{
+		url:              DefaultWebSocketURL,
+		keepaliveTimeout: options.KeepaliveTimeout,
+		keepaliveGrace:   defaultKeepaliveGrace,
+		helix:            options.Helix,
+		subscriptions:    options.Subscriptions,
+		store:            options.MessageStore,
+		now:              time.Now,
+	}
+	if options.URL != "" {
+		c.url = options.URL
+	}
+	if c.store == nil {
+		c.store = NewMemoryMessageStore()
+	}
+	return c
+}
+
+// OnNotification sets the handler that notifications are dispatched to. Twitch does not resend notifications over
+// WebSockets, so handler errors are only passed to the OnError handler.
+//
+// Handlers must be registered before calling Connect.
+func (c *WebSocketClient) OnNotification(handler NotificationHandler) {
+	c.onNotification = handler
+}
+
+// OnRevocation sets the handler that revoked subscriptions are dispatched to.
+//
+// Handlers must be registered before calling Connect.
+func (c *WebSocketClient) OnRevocation(handler RevocationHandler) {
+	c.onRevocation = handler
+}
+
+// OnWelcome sets the handler called whenever a new session is welcomed, before subscriptions are created. It is
+// not called for the session that replaces the current one after a session reconnect.
+//
+// Handlers must be registered before calling Connect.
+func (c *WebSocketClient) OnWelcome(handler func(ctx context.Context, session *Session)) {
+	c.onWelcome = handler
+}
+
+// OnError sets the handler for errors that do not end the connection, such as notification handler and
+// MessageStore errors.
+//
+// Handlers must be registered before calling Connect.
+func (c *WebSocketClient) OnError(handler func(err error)) {
+	c.onError = handler
+}
+
+// SessionID returns the ID of the current session, or an empty string when not connected.
+func (c *WebSocketClient) SessionID() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.session == nil {
+		return ""
+	}
+	return c.session.ID
+}
+
+// Connect connects to the EventSub WebSocket server, creates the configured subscriptions, and reads messages until
+// ctx is cancelled or the connection fails. Session reconnects are followed transparently.
+//
+// Connect returns ErrKeepaliveTimeout when the connection is detected as dead. It can be called again to start a
+// new session, the configured subscriptions are then created again.
+func (c *WebSocketClient) Connect(ctx context.Context) error {
+	conn, session, err := c.dial(ctx, c.connectURL())
+	if err != nil {
+		return err
+	}
+	c.setConn(conn, session)
+	defer c.setConn(nil, nil)
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			c.mu.Lock()
+			if c.conn != nil {
+				_ = c.conn.Close()
+			}
+			c.mu.Unlock()
+		case <-done:
+		}
+	}()
+
+	if c.onWelcome != nil {
+		c.onWelcome(ctx, session)
+	}
+
+	if err := c.subscribe(ctx, session.ID); err != nil {
+		return err
+	}
+
+	return c.read(ctx, conn, session)
+}
+
+// connectURL returns the initial URL to connect to, requesting the configured keepalive timeout.
+func (c *WebSocketClient) connectURL() string {
+	if c.keepaliveTimeout <= 0 {
+		return c.url
+	}
+
+	u, err := url.Parse(c.url)
+	if err != nil {
+		return c.url
+	}
+	q := u.Query()
+	q.Set("keepalive_timeout_seconds", strconv.Itoa(int(c.keepaliveTimeout/time.Second)))
+	u.RawQuery = q.Encode()
+	return u.String()
+}
+
+// dial connects to the URL, and waits for the session welcome message.
+func (c *WebSocketClient) dial(ctx context.Context, rawURL string) (*websocket.Conn, *Session, error) {
+	conn, err := websocket.Dial(ctx, rawURL, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	_ = conn.SetReadDeadline(c.now().Add(welcomeTimeout))
+	msg, err := readWebSocketMessage(conn)
+	if err != nil || msg.Metadata.MessageType != MessageTypeSessionWelcome || msg.Payload.Session == nil {
+		_ = conn.Close()
+		if ctx.Err() != nil {
+			return nil, nil, ctx.Err()
+		}
+		return nil, nil, ErrNoWelcome
+	}
+	return conn, msg.Payload.Session, nil
+}
+
+// subscribe creates the configured subscriptions for the session.
+func (c *WebSocketClient) subscribe(ctx context.Context, sessionID string) error {
+	if c.helix == nil {
+		return nil
+	}
+
+	for _, condition := range c.subscriptions {
+		_, err := c.helix.CreateEventSubSubscription(ctx, &helix.CreateEventSubSubscriptionRequest{
+			Condition: condition,
+			Transport: helix.WebSocketTransport(sessionID),
+		})
+		if err != nil {
+			return fmt.Errorf("eventsub: creating %s subscription: %w", condition.EventSubType(), err)
+		}
+	}
+	return nil
+}
+
+// read reads messages until the connection fails, following session reconnects.
+func (c *WebSocketClient) read(ctx context.Context, conn *websocket.Conn, session *Session) error {
+	for {
+		_ = conn.SetReadDeadline(c.readDeadline(session))
+		msg, err := readWebSocketMessage(conn)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				return ErrKeepaliveTimeout
+			}
+			return err
+		}
+
+		if msg.Metadata.MessageType != MessageTypeSessionReconnect {
+			c.handle(ctx, msg)
+			continue
+		}
+
+		if msg.Payload.Session == nil || msg.Payload.Session.ReconnectURL == "" {
+			continue
+		}
+
+		newConn, newSession, err := c.dial(ctx, msg.Payload.Session.ReconnectURL)
+		if err != nil {
+			return err
+		}
+
+		// keep the keepalive timeout of the old session, the new session is welcomed with the same subscriptions
+		if newSession.KeepaliveTimeoutSeconds == nil {
+			newSession.KeepaliveTimeoutSeconds = session.KeepaliveTimeoutSeconds
+		}
+
+		c.drain(ctx, conn, session)
+		c.setConn(newConn, newSession)
+		_ = conn.Close()
+		conn, session = newConn, newSession
+	}
+}
+
+// drain handles the messages still sent on the old connection, until Twitch closes it after welcoming the new one.
+func (c *WebSocketClient) drain(ctx context.Context, conn *websocket.Conn, session *Session) {
+	_ = conn.SetReadDeadline(c.readDeadline(session))
+	for {
+		msg, err := readWebSocketMessage(conn)
+		if err != nil {
+			return
+		}
+		if msg.Metadata.MessageType != MessageTypeSessionReconnect {
+			c.handle(ctx, msg)
+		}
+	}
+}
+
+// handle dispatches notification and revocation messages to the registered handlers.
+func (c *WebSocketClient) handle(ctx context.Context, msg *webSocketMessage) {
+	switch msg.Metadata.MessageType {
+	case MessageTypeNotification:
+		if c.onNotification == nil {
+			return
+		}
+
+		seen, err := c.store.Seen(ctx, msg.Metadata.MessageID)
+		if err != nil || seen {
+			c.reportError(err)
+			return
+		}
+
+		err = c.onNotification(ctx, &Notification{
+			MessageID:    msg.Metadata.MessageID,
+			MessageType:  MessageTypeNotification,
+			Timestamp:    msg.Metadata.MessageTimestamp,
+			Subscription: msg.Payload.Subscription,
+			Event:        msg.Payload.Event,
+		})
+		if err != nil {
+			c.reportError(err)
+			return
+		}
+		c.reportError(c.store.Mark(ctx, msg.Metadata.MessageID, webSocketMessageTTL))
+
+	case MessageTypeRevocation:
+		if c.onRevocation != nil && msg.Payload.Subscription != nil {
+			c.onRevocation(ctx, msg.Payload.Subscription)
+		}
+	}
+}
+
+// reportError passes non-nil errors to the OnError handler.
+func (c *WebSocketClient) reportError(err error) {
+	if err != nil && c.onError != nil {
+		c.onError(err)
+	}
+}
+
+func (c *WebSocketClient) readDeadline(session *Session) time.Time {
+	if session == nil || session.KeepaliveTimeoutSeconds == nil {
+		return time.Time{}
+	}
+	return c.now().Add(time.Duration(*session.KeepaliveTimeoutSeconds)*time.Second + c.keepaliveGrace)
+}
+
+func (c *WebSocketClient) setConn(conn *websocket.Conn, session *Session) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if conn == nil && c.conn != nil {
+		_ = c.conn.Close()
+	}
+	c.conn = conn
+	c.session = session
+}
+
+// readWebSocketMessage reads and decodes the next text message.
+func readWebSocketMessage(conn *websocket.Conn) (*webSocketMessage, error) {
+	for {
+		messageType, data, err := conn.ReadMessage()
+		if err != nil {
+			return nil, err
+		}
+		if messageType != websocket.TextMessage {
+			continue
+		}
+
+		msg := &webSocketMessage{}
+		if err := json.Unmarshal(data, msg); err != nil {
+			return nil, err
+		}
+		return msg, nil
+	}
+}
@@ -0,0 +1,227 @@
+package eventsub
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aidenwallis/go-twitch-client/helix"
+	"github.com/aidenwallis/go-twitch-client/internal/testutils"
+	"github.com/aidenwallis/go-twitch-client/internal/testutils/assert"
+	"github.com/aidenwallis/go-twitch-client/internal/websocket"
+)
+
+func eventSubServer(t *testing.T, session func(conn *websocket.Conn)) *httptest.Server {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := websocket.Upgrade(w, r)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		session(conn)
+
+		// block until the client goes away
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func webSocketURL(srv *httptest.Server) string {
+	return "ws" + strings.TrimPrefix(srv.URL, "http")
+}
+
+func send(t *testing.T, conn *websocket.Conn, messageID, messageType string, payload any) {
+	t.Helper()
+
+	bs, err := json.Marshal(map[string]any{
+		"metadata": map[string]any{
+			"message_id":        messageID,
+			"message_type":      messageType,
+			"message_timestamp": "2022-01-01T00:00:00Z",
+		},
+		"payload": payload,
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, conn.WriteMessage(websocket.TextMessage, bs))
+}
+
+func sendWelcome(t *testing.T, conn *websocket.Conn, sessionID string, keepalive any) {
+	send(t, conn, "welcome-"+sessionID, MessageTypeSessionWelcome, map[string]any{
+		"session": map[string]any{
+			"id":                        sessionID,
+			"status":                    "connected",
+			"connected_at":              "2022-01-01T00:00:00Z",
+			"keepalive_timeout_seconds": keepalive,
+			"reconnect_url":             nil,
+		},
+	})
+}
+
+func sendNotification(t *testing.T, conn *websocket.Conn, messageID string) {
+	send(t, conn, messageID, MessageTypeNotification, map[string]any{
+		"subscription": map[string]any{"id": "sub", "type": helix.EventSubTypeStreamOnline, "version": "1"},
+		"event":        map[string]any{"broadcaster_user_id": "1"},
+	})
+}
+
+func TestWebSocketClient(t *testing.T) {
+	srv := eventSubServer(t, func(conn *websocket.Conn) {
+		sendWelcome(t, conn, "session", 10)
+		send(t, conn, "keepalive", MessageTypeSessionKeepalive, map[string]any{})
+		sendNotification(t, conn, "msg")
+		sendNotification(t, conn, "msg")
+		send(t, conn, "revoke", MessageTypeRevocation, map[string]any{
+			"subscription": map[string]any{"id": "sub", "status": helix.EventSubStatusAuthorizationRevoked},
+		})
+	})
+
+	var created []string
+	helixClient := helix.NewClient(&helix.ClientOptions{
+		ClientID: "clientID",
+		Transport: testutils.Middleware(func(req *http.Request) *testutils.Response {
+			var body map[string]any
+			assert.NoError(t, json.Unmarshal([]byte(testutils.DecodeRawBody(t, req)), &body))
+			transport := body["transport"].(map[string]any)
+			assert.Equal(t, helix.EventSubTransportWebSocket, transport["method"].(string))
+			assert.Equal(t, "session", transport["session_id"].(string))
+			created = append(created, body["type"].(string))
+			return testutils.JSONResponse(t, http.StatusAccepted, map[string]any{"data": []any{}})
+		}),
+	}).ForToken("abc123")
+
+	c := NewWebSocketClient(&WebSocketClientOptions{
+		URL:           webSocketURL(srv),
+		Helix:         helixClient,
+		Subscriptions: []helix.EventSubCondition{&helix.StreamOnlineCondition{BroadcasterUserID: "1"}},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var welcomed string
+	c.OnWelcome(func(ctx context.Context, session *Session) {
+		welcomed = session.ID
+		assert.Equal(t, 10, *session.KeepaliveTimeoutSeconds)
+	})
+
+	notifications := 0
+	c.OnNotification(func(ctx context.Context, n *Notification) error {
+		notifications++
+		assert.Equal(t, "msg", n.MessageID)
+		assert.Equal(t, helix.EventSubTypeStreamOnline, n.Subscription.Type)
+		assert.Equal(t, `{"broadcaster_user_id":"1"}`, string(n.Event))
+		assert.Equal(t, "session", c.SessionID())
+		return nil
+	})
+	c.OnRevocation(func(ctx context.Context, subscription *helix.EventSubSubscription) {
+		assert.Equal(t, helix.EventSubStatusAuthorizationRevoked, subscription.Status)
+		cancel()
+	})
+
+	assert.ErrorIs(t, c.Connect(ctx), context.Canceled)
+	assert.Equal(t, "session", welcomed)
+	assert.Equal(t, 1, notifications)
+	assert.Equal(t, 1, len(created))
+	assert.Equal(t, helix.EventSubTypeStreamOnline, created[0])
+	assert.Equal(t, "", c.SessionID())
+}
+
+func TestWebSocketClientReconnect(t *testing.T) {
+	connected := make(chan struct{})
+	newServer := eventSubServer(t, func(conn *websocket.Conn) {
+		sendWelcome(t, conn, "new", nil)
+		close(connected)
+		sendNotification(t, conn, "2")
+		sendNotification(t, conn, "3")
+	})
+
+	oldServer := eventSubServer(t, func(conn *websocket.Conn) {
+		sendWelcome(t, conn, "old", 10)
+		sendNotification(t, conn, "1")
+		send(t, conn, "reconnect", MessageTypeSessionReconnect, map[string]any{
+			"session": map[string]any{
+				"id":            "old",
+				"status":        "reconnecting",
+				"reconnect_url": webSocketURL(newServer),
+			},
+		})
+		<-connected
+		sendNotification(t, conn, "2")
+		_ = conn.CloseWithReason(4004, "reconnect grace time expired")
+	})
+
+	subscribed := 0
+	helixClient := helix.NewClient(&helix.ClientOptions{
+		ClientID: "clientID",
+		Transport: testutils.Middleware(func(req *http.Request) *testutils.Response {
+			subscribed++
+			return testutils.JSONResponse(t, http.StatusAccepted, map[string]any{"data": []any{}})
+		}),
+	}).ForToken("abc123")
+
+	c := NewWebSocketClient(&WebSocketClientOptions{
+		URL:           webSocketURL(oldServer),
+		Helix:         helixClient,
+		Subscriptions: []helix.EventSubCondition{&helix.StreamOnlineCondition{BroadcasterUserID: "1"}},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var mu sync.Mutex
+	var received []string
+	c.OnNotification(func(ctx context.Context, n *Notification) error {
+		mu.Lock()
+		defer mu.Unlock()
+
+		received = append(received, n.MessageID)
+		if n.MessageID == "3" {
+			assert.Equal(t, "new", c.SessionID())
+			cancel()
+		}
+		return nil
+	})
+
+	assert.ErrorIs(t, c.Connect(ctx), context.Canceled)
+	assert.Equal(t, "1,2,3", strings.Join(received, ","))
+	assert.Equal(t, 1, subscribed)
+}
+
+func TestWebSocketClientKeepaliveTimeout(t *testing.T) {
+	srv := eventSubServer(t, func(conn *websocket.Conn) {
+		sendWelcome(t, conn, "session", 1)
+	})
+
+	c := NewWebSocketClient(&WebSocketClientOptions{URL: webSocketURL(srv)})
+	c.keepaliveGrace = 0
+
+	assert.ErrorIs(t, c.Connect(context.Background()), ErrKeepaliveTimeout)
+}
+
+func TestWebSocketClientNoWelcome(t *testing.T) {
+	srv := eventSubServer(t, func(conn *websocket.Conn) {
+		send(t, conn, "keepalive", MessageTypeSessionKeepalive, map[string]any{})
+	})
+
+	c := NewWebSocketClient(&WebSocketClientOptions{URL: webSocketURL(srv)})
+	assert.ErrorIs(t, c.Connect(context.Background()), ErrNoWelcome)
+}
+
+func TestWebSocketClientKeepaliveQuery(t *testing.T) {
+	c := NewWebSocketClient(&WebSocketClientOptions{KeepaliveTimeout: 30 * time.Second})
+	assert.Equal(t, DefaultWebSocketURL+"?keepalive_timeout_seconds=30", c.connectURL())
+	assert.Equal(t, DefaultWebSocketURL, NewWebSocketClient(nil).connectURL())
+}
@@ -55,7 +55,7 @@ func (c *helixClient) StartCommercial(ctx context.Context, req *StartCommercialR
 	return client.WithBody[StartCommercialResponse](c.Request(&client.RequestConfig{
 		Method:  http.MethodPost,
 		URL:     commercialPath,
-		Headers: c.headers(req.RequestOptions),
+		Headers: c.headers(req.RequestOptions, TokenTypeUser),
 	}).BodyJSON(&startCommercialBody{
 		BroadcasterID: req.BroadcasterID,
 		Length:        req.Length,
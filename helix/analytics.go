@@ -143,7 +143,7 @@ func (c *helixClient) GetExtensionAnalytics(ctx context.Context, req *GetExtensi
 		Method:  http.MethodGet,
 		URL:     extensionAnalyticsPath,
 		Query:   values,
-		Headers: c.headers(req.RequestOptions),
+		Headers: c.headers(req.RequestOptions, TokenTypeUser),
 	}).Do(ctx))
 }
 
@@ -259,6 +259,6 @@ func (c *helixClient) GetGameAnalytics(ctx context.Context, req *GetGameAnalytic
 		Method:  http.MethodGet,
 		URL:     gameAnalyticsPath,
 		Query:   values,
-		Headers: c.headers(req.RequestOptions),
+		Headers: c.headers(req.RequestOptions, TokenTypeUser),
 	}).Do(ctx))
 }
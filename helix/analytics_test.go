@@ -14,12 +14,13 @@ import (
 func TestGetExtensionAnalytics(t *testing.T) {
 	ctx := context.Background()
 	in := &GetExtensionAnalyticsRequest{
-		After:       "after",
-		ExtensionID: "extensionID",
-		Type:        "overview_v2",
-		First:       20,
-		StartedAt:   time.Now().UTC(),
-		EndedAt:     time.Now().Add(time.Hour * 12).UTC(),
+		RequestOptions: requestOptions(),
+		After:          "after",
+		ExtensionID:    "extensionID",
+		Type:           "overview_v2",
+		First:          20,
+		StartedAt:      time.Now().UTC(),
+		EndedAt:        time.Now().Add(time.Hour * 12).UTC(),
 	}
 
 	c := testClient(func(req *http.Request) *testutils.Response {
@@ -55,12 +56,13 @@ func TestGetExtensionAnalytics(t *testing.T) {
 func TestGetGameAnalytics(t *testing.T) {
 	ctx := context.Background()
 	in := &GetGameAnalyticsRequest{
-		After:     "after",
-		GameID:    "gameID",
-		Type:      "overview_v2",
-		First:     20,
-		StartedAt: time.Now().UTC(),
-		EndedAt:   time.Now().Add(time.Hour * 12).UTC(),
+		RequestOptions: requestOptions(),
+		After:          "after",
+		GameID:         "gameID",
+		Type:           "overview_v2",
+		First:          20,
+		StartedAt:      time.Now().UTC(),
+		EndedAt:        time.Now().Add(time.Hour * 12).UTC(),
 	}
 
 	c := testClient(func(req *http.Request) *testutils.Response {
@@ -0,0 +1,58 @@
+package helix
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/aidenwallis/go-twitch-client/internal/client"
+)
+
+// Auth represents the auth namespace
+type Auth interface {
+	// ValidateToken implements https://dev.twitch.tv/docs/authentication/validate-tokens
+	//
+	// Validates an OAuth token, and returns the client ID, user and scopes that it belongs to.
+	ValidateToken(context.Context, *ValidateTokenRequest) (*ValidateTokenResponse, error)
+}
+
+const validateTokenPath = "https://id.twitch.tv/oauth2/validate"
+
+// ValidateTokenRequest defines the options passed to ValidateToken
+type ValidateTokenRequest struct {
+	// Token is the OAuth token to validate, this may be a user or app access token.
+	Token string
+}
+
+// ValidateTokenResponse defines the API response returned by ValidateToken
+type ValidateTokenResponse struct {
+	// ClientID is the client ID the token was issued to.
+	ClientID string `json:"client_id"`
+
+	// Login is the login name of the user the token belongs to. Empty for app access tokens.
+	Login string `json:"login"`
+
+	// Scopes are the scopes granted to the token.
+	Scopes []string `json:"scopes"`
+
+	// UserID is the ID of the user the token belongs to. Empty for app access tokens.
+	UserID string `json:"user_id"`
+
+	// ExpiresIn is the number of seconds until the token expires.
+	ExpiresIn int `json:"expires_in"`
+}
+
+// ValidateToken implements https://dev.twitch.tv/docs/authentication/validate-tokens
+//
+// Validates an OAuth token, and returns the client ID, user and scopes that it belongs to.
+func (c *helixClient) ValidateToken(ctx context.Context, req *ValidateTokenRequest) (*ValidateTokenResponse, error) {
+	return client.WithBody[ValidateTokenResponse](c.Request(&client.RequestConfig{
+		Method: http.MethodGet,
+		URL:    validateTokenPath,
+		Headers: func(context.Context) (http.Header, error) {
+			h := http.Header{}
+			h.Set("Accept", "application/json")
+			h.Set("Authorization", "OAuth "+req.Token)
+			return h, nil
+		},
+	}).Do(ctx))
+}
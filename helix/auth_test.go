@@ -0,0 +1,32 @@
+package helix
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/aidenwallis/go-twitch-client/internal/testutils"
+	"github.com/aidenwallis/go-twitch-client/internal/testutils/assert"
+)
+
+func TestValidateToken(t *testing.T) {
+	ctx := context.Background()
+
+	c := testClient(func(req *http.Request) *testutils.Response {
+		assert.Equal(t, http.MethodGet, req.Method)
+		assert.Equal(t, validateTokenPath, req.URL.String())
+		assert.Equal(t, "OAuth "+fakeToken, req.Header.Get("Authorization"))
+		return testutils.JSONResponse(t, http.StatusOK, &ValidateTokenResponse{
+			ClientID:  fakeClientID,
+			Login:     "forsen",
+			Scopes:    []string{"chat:read"},
+			UserID:    "1",
+			ExpiresIn: 3600,
+		})
+	})
+
+	resp, err := c.ValidateToken(ctx, &ValidateTokenRequest{Token: fakeToken})
+	assert.NoError(t, err)
+	assert.Equal(t, "1", resp.UserID)
+	assert.Equal(t, 3600, resp.ExpiresIn)
+}
@@ -81,7 +81,7 @@ func (c *helixClient) GetChannelInformation(ctx context.Context, req *GetChannel
 	return client.WithBody[GetChannelInformationResponse](c.Request(&client.RequestConfig{
 		Method:  http.MethodGet,
 		URL:     channelsPath,
-		Headers: c.headers(req.RequestOptions),
+		Headers: c.headers(req.RequestOptions, TokenTypeAny),
 		Query:   values,
 	}).Do(ctx))
 }
@@ -126,7 +126,7 @@ func (c *helixClient) ModifyChannelInformation(ctx context.Context, req *ModifyC
 	return client.WithoutBody(c.Request(&client.RequestConfig{
 		Method:  http.MethodPatch,
 		URL:     channelsPath,
-		Headers: c.headers(req.RequestOptions),
+		Headers: c.headers(req.RequestOptions, TokenTypeUser),
 		Query:   values,
 	}).BodyJSON(&modifyChannelInformationBody{
 		GameID:              req.GameID,
@@ -174,7 +174,7 @@ func (c *helixClient) GetChannelEditors(ctx context.Context, req *GetChannelEdit
 	return client.WithBody[GetChannelEditorsResponse](c.Request(&client.RequestConfig{
 		Method:  http.MethodGet,
 		URL:     channelEditorsPath,
-		Headers: c.headers(req.RequestOptions),
+		Headers: c.headers(req.RequestOptions, TokenTypeUser),
 		Query:   values,
 	}).Do(ctx))
 }
@@ -2,9 +2,12 @@ package helix
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/url"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/aidenwallis/go-twitch-client"
 	"github.com/aidenwallis/go-twitch-client/internal/client"
@@ -64,6 +67,11 @@ type Chat interface {
 	// Sends an announcement to the broadcaster’s chat room.
 	SendChatAnnouncement(context.Context, *SendChatAnnouncementRequest) error
 
+	// SendChatMessage implements https://dev.twitch.tv/docs/api/reference#send-chat-message
+	//
+	// Sends a message to the broadcaster’s chat room.
+	SendChatMessage(context.Context, *SendChatMessageRequest) (*SendChatMessageResponse, error)
+
 	// GetUserChatColors implements https://dev.twitch.tv/docs/api/reference#get-user-chat-color
 	//
 	// Gets the color used for the user’s name in chat.
@@ -98,6 +106,12 @@ type GetChannelEmotesResponse struct {
 	Template string `json:"template"`
 }
 
+// DefaultChatEmoteTemplate is the CDN template Twitch returns in emote responses, for emotes whose ID is known without
+// a Helix response, such as emotes in chat messages.
+//
+// See: https://dev.twitch.tv/docs/irc/emotes#cdn-template
+const DefaultChatEmoteTemplate = "https://static-cdn.jtvnw.net/emoticons/v2/{{id}}/{{format}}/{{theme_mode}}/{{scale}}"
+
 // FormatChatEmoteTemplate returns a CDN emote URL based off of a defined template and emote properties
 func FormatChatEmoteTemplate(template, id, format, themeMode, scale string) string {
 	return strings.NewReplacer(
@@ -195,7 +209,7 @@ func (c *helixClient) GetChannelEmotes(ctx context.Context, req *GetChannelEmote
 	return client.WithBody[GetChannelEmotesResponse](c.Request(&client.RequestConfig{
 		Method:  http.MethodGet,
 		URL:     chatEmotesPath,
-		Headers: c.headers(req.RequestOptions),
+		Headers: c.headers(req.RequestOptions, TokenTypeAny),
 		Query:   values,
 	}).Do(ctx))
 }
@@ -273,7 +287,7 @@ func (c *helixClient) GetGlobalEmotes(ctx context.Context, req *GetGlobalEmotesR
 	return client.WithBody[GetGlobalEmotesResponse](c.Request(&client.RequestConfig{
 		Method:  http.MethodGet,
 		URL:     chatGlobalEmotesPath,
-		Headers: c.headers(req.RequestOptions),
+		Headers: c.headers(req.RequestOptions, TokenTypeAny),
 	}).Do(ctx))
 }
 
@@ -377,7 +391,7 @@ func (c *helixClient) GetEmoteSets(ctx context.Context, req *GetEmoteSetsRequest
 	return client.WithBody[GetEmoteSetsResponse](c.Request(&client.RequestConfig{
 		Method:  http.MethodGet,
 		URL:     chatEmoteSetsPath,
-		Headers: c.headers(req.RequestOptions),
+		Headers: c.headers(req.RequestOptions, TokenTypeAny),
 		Query:   values,
 	}).Do(ctx))
 }
@@ -401,7 +415,7 @@ type GetChannelChatBadgesResponse struct {
 // ChatBadge defines a single helix chat badge
 type ChatBadge struct {
 	// SetID is the ID for the chat badge set.
-	SetID string `json:"site_id"`
+	SetID string `json:"set_id"`
 
 	// Versions contains chat badge objects for the set.
 	Versions []*ChatBadgeVersion `json:"versions"`
@@ -420,6 +434,22 @@ type ChatBadgeVersion struct {
 
 	// ImageURL4x is the Large image URL.
 	ImageURL4x string `json:"image_url_4x"`
+
+	// Title of the badge.
+	Title string `json:"title"`
+
+	// Description of the badge.
+	Description string `json:"description"`
+
+	// ClickAction is the action to take when clicking on the badge.
+	//
+	// Is nil if no action is specified.
+	ClickAction *string `json:"click_action"`
+
+	// ClickURL is the URL to navigate to when clicking on the badge.
+	//
+	// Is nil if no URL is specified.
+	ClickURL *string `json:"click_url"`
 }
 
 // GetChannelChatBadges implements https://dev.twitch.tv/docs/api/reference#get-channel-chat-badges
@@ -432,7 +462,7 @@ func (c *helixClient) GetChannelChatBadges(ctx context.Context, req *GetChannelC
 	return client.WithBody[GetChannelChatBadgesResponse](c.Request(&client.RequestConfig{
 		Method:  http.MethodGet,
 		URL:     channelChatBadges,
-		Headers: c.headers(req.RequestOptions),
+		Headers: c.headers(req.RequestOptions, TokenTypeAny),
 		Query:   values,
 	}).Do(ctx))
 }
@@ -457,7 +487,7 @@ func (c *helixClient) GetGlobalChatBadges(ctx context.Context, req *GetGlobalCha
 	return client.WithBody[GetGlobalChatBadgesResponse](c.Request(&client.RequestConfig{
 		Method:  http.MethodGet,
 		URL:     globalChatBadges,
-		Headers: c.headers(req.RequestOptions),
+		Headers: c.headers(req.RequestOptions, TokenTypeAny),
 	}).Do(ctx))
 }
 
@@ -477,6 +507,8 @@ type GetChatSettingsRequest struct {
 	// The ID of a user that has permission to moderate the broadcaster’s chat room. This ID must match the user ID associated with the user OAuth token.
 	//
 	// If the broadcaster wants to get their own settings (instead of having the moderator do it), set this parameter to the broadcaster’s ID, too.
+	//
+	// Filled from the OAuth token when left empty, if ClientOptions.ResolveActingUser is enabled.
 	ModeratorID string
 }
 
@@ -558,16 +590,21 @@ type ChatSettings struct {
 //
 // * Moderator Preferences: https://help.twitch.tv/s/article/setting-up-moderation-for-your-twitch-channel#modpreferences
 func (c *helixClient) GetChatSettings(ctx context.Context, req *GetChatSettingsRequest) (*GetChatSettingsResponse, error) {
+	moderatorID, err := c.actingUserID(ctx, req.RequestOptions, req.ModeratorID)
+	if err != nil {
+		return nil, err
+	}
+
 	values := url.Values{}
 	values.Set("broadcaster_id", req.BroadcasterID)
-	if req.ModeratorID != "" {
-		values.Set("moderator_id", req.ModeratorID)
+	if moderatorID != "" {
+		values.Set("moderator_id", moderatorID)
 	}
 
 	return client.WithBody[GetChatSettingsResponse](c.Request(&client.RequestConfig{
 		Method:  http.MethodGet,
 		URL:     chatSettingsPath,
-		Headers: c.headers(req.RequestOptions),
+		Headers: c.headers(req.RequestOptions, TokenTypeAny),
 		Query:   values,
 	}).Do(ctx))
 }
@@ -583,6 +620,8 @@ type UpdateChatSettingsRequest struct {
 	// This ID must match the user ID associated with the user OAuth token.
 	//
 	// If the broadcaster is making the update, also set this parameter to the broadcaster’s ID.
+	//
+	// Filled from the OAuth token when left empty, if ClientOptions.ResolveActingUser is enabled.
 	ModeratorID string
 
 	// EmoteMode is a value that determines whether chat messages must contain only emotes.
@@ -671,15 +710,20 @@ type updateChatSettingsBody struct {
 //
 // Updates the broadcaster’s chat settings.
 func (c *helixClient) UpdateChatSettings(ctx context.Context, req *UpdateChatSettingsRequest) (*UpdateChatSettingsResponse, error) {
+	moderatorID, err := c.actingUserID(ctx, req.RequestOptions, req.ModeratorID)
+	if err != nil {
+		return nil, err
+	}
+
 	values := url.Values{}
 	values.Set("broadcaster_id", req.BroadcasterID)
-	values.Set("moderator_id", req.ModeratorID)
+	values.Set("moderator_id", moderatorID)
 
 	return client.WithBody[UpdateChatSettingsResponse](c.Request(&client.RequestConfig{
 		Method:  http.MethodPatch,
 		URL:     chatSettingsPath,
 		Query:   values,
-		Headers: c.headers(req.RequestOptions),
+		Headers: c.headers(req.RequestOptions, TokenTypeUser),
 	}).BodyJSON(&updateChatSettingsBody{
 		EmoteMode:                     req.EmoteMode,
 		FollowerMode:                  req.FollowerMode,
@@ -695,6 +739,32 @@ func (c *helixClient) UpdateChatSettings(ctx context.Context, req *UpdateChatSet
 
 const chatAnnouncementsPath = "https://api.twitch.tv/helix/chat/announcements"
 
+// MaxChatMessageLength is the maximum number of characters in a chat message or announcement. Longer announcements are
+// truncated by Twitch.
+const MaxChatMessageLength = 500
+
+var (
+	// ErrEmptyChatMessage is returned when sending a chat message or announcement that is empty after trimming
+	// whitespace.
+	ErrEmptyChatMessage = errors.New("helix: chat message is empty")
+
+	// ErrChatMessageTooLong is returned when sending a chat message longer than MaxChatMessageLength characters.
+	ErrChatMessageTooLong = errors.New("helix: chat message is too long")
+)
+
+// ValidateChatMessage returns ErrEmptyChatMessage when the message is empty after trimming whitespace, and
+// ErrChatMessageTooLong when it is longer than MaxChatMessageLength characters. Announcements must not be empty either,
+// but longer announcements are truncated rather than rejected.
+func ValidateChatMessage(message string) error {
+	if strings.TrimSpace(message) == "" {
+		return ErrEmptyChatMessage
+	}
+	if length := utf8.RuneCountInString(message); length > MaxChatMessageLength {
+		return fmt.Errorf("%w: %d characters, at most %d are allowed", ErrChatMessageTooLong, length, MaxChatMessageLength)
+	}
+	return nil
+}
+
 // SendChatAnnouncementRequest defines the options passed to SendChatAnnouncement
 type SendChatAnnouncementRequest struct {
 	*RequestOptions
@@ -704,6 +774,8 @@ type SendChatAnnouncementRequest struct {
 
 	// ModeratorID is the ID of a user who has permission to moderate the broadcaster’s chat room.
 	// This ID must match the user ID in the OAuth token, which can be a moderator or the broadcaster.
+	//
+	// Filled from the OAuth token when left empty, if ClientOptions.ResolveActingUser is enabled.
 	ModeratorID string
 
 	// Color (optional) used to highlight the announcement. Possible case-sensitive values are:
@@ -737,14 +809,23 @@ type sendChatAnnouncementBody struct {
 //
 // Sends an announcement to the broadcaster’s chat room.
 func (c *helixClient) SendChatAnnouncement(ctx context.Context, req *SendChatAnnouncementRequest) error {
+	if strings.TrimSpace(req.Message) == "" {
+		return ErrEmptyChatMessage
+	}
+
+	moderatorID, err := c.actingUserID(ctx, req.RequestOptions, req.ModeratorID)
+	if err != nil {
+		return err
+	}
+
 	values := url.Values{}
 	values.Set("broadcaster_id", req.BroadcasterID)
-	values.Set("moderator_id", req.ModeratorID)
+	values.Set("moderator_id", moderatorID)
 
 	return client.WithoutBody(c.Request(&client.RequestConfig{
 		Method:  http.MethodPost,
 		URL:     chatAnnouncementsPath,
-		Headers: c.headers(req.RequestOptions),
+		Headers: c.headers(req.RequestOptions, TokenTypeUser),
 		Query:   values,
 	}).BodyJSON(&sendChatAnnouncementBody{
 		Color:   twitch.PointerValue(req.Color),
@@ -752,6 +833,88 @@ func (c *helixClient) SendChatAnnouncement(ctx context.Context, req *SendChatAnn
 	}).Do(ctx))
 }
 
+const chatMessagesPath = "https://api.twitch.tv/helix/chat/messages"
+
+// SendChatMessageRequest defines the options passed to SendChatMessage
+type SendChatMessageRequest struct {
+	*RequestOptions
+
+	// BroadcasterID is the ID of the broadcaster whose chat room the message will be sent to.
+	BroadcasterID string
+
+	// SenderID is the ID of the user sending the message. This ID must match the user ID in the user access token.
+	//
+	// Filled from the OAuth token when left empty, if ClientOptions.ResolveActingUser is enabled.
+	SenderID string
+
+	// Message is the message to send. The message is limited to a maximum of 500 characters, see ValidateChatMessage.
+	// Chat messages can also include emoticons. To include emoticons, use the name of the emote. The names are case sensitive.
+	Message string
+
+	// ReplyParentMessageID (optional) is the ID of the chat message being replied to.
+	ReplyParentMessageID string
+}
+
+// sendChatMessageBody defines the request body for SendChatMessage
+type sendChatMessageBody struct {
+	BroadcasterID        string `json:"broadcaster_id"`
+	SenderID             string `json:"sender_id"`
+	Message              string `json:"message"`
+	ReplyParentMessageID string `json:"reply_parent_message_id,omitempty"`
+}
+
+// ChatMessageDropReason defines why a chat message was not sent
+type ChatMessageDropReason struct {
+	// Code is the code for why the message was dropped.
+	Code string `json:"code"`
+
+	// Message is the message for why the message was dropped.
+	Message string `json:"message"`
+}
+
+// SentChatMessage defines the result of sending a chat message
+type SentChatMessage struct {
+	// MessageID is the message id for the message that was sent.
+	MessageID string `json:"message_id"`
+
+	// IsSent is whether the message passed all checks and was sent.
+	IsSent bool `json:"is_sent"`
+
+	// DropReason is the reason the message was dropped, nil when the message was sent.
+	DropReason *ChatMessageDropReason `json:"drop_reason"`
+}
+
+// SendChatMessageResponse defines the API response returned by SendChatMessage
+type SendChatMessageResponse struct {
+	// Data represents a slice of SentChatMessage
+	Data []*SentChatMessage `json:"data"`
+}
+
+// SendChatMessage implements https://dev.twitch.tv/docs/api/reference#send-chat-message
+//
+// Sends a message to the broadcaster’s chat room.
+func (c *helixClient) SendChatMessage(ctx context.Context, req *SendChatMessageRequest) (*SendChatMessageResponse, error) {
+	if err := ValidateChatMessage(req.Message); err != nil {
+		return nil, err
+	}
+
+	senderID, err := c.actingUserID(ctx, req.RequestOptions, req.SenderID)
+	if err != nil {
+		return nil, err
+	}
+
+	return client.WithBody[SendChatMessageResponse](c.Request(&client.RequestConfig{
+		Method:  http.MethodPost,
+		URL:     chatMessagesPath,
+		Headers: c.headers(req.RequestOptions, TokenTypeAny),
+	}).BodyJSON(&sendChatMessageBody{
+		BroadcasterID:        req.BroadcasterID,
+		SenderID:             senderID,
+		Message:              req.Message,
+		ReplyParentMessageID: req.ReplyParentMessageID,
+	}).Do(ctx))
+}
+
 const chatColorPath = "https://api.twitch.tv/helix/chat/color"
 
 // GetUserChatColorsRequest defines the options passed to GetUserChatColors
@@ -795,7 +958,7 @@ func (c *helixClient) GetUserChatColors(ctx context.Context, req *GetUserChatCol
 	return client.WithBody[GetUserChatColorsResponse](c.Request(&client.RequestConfig{
 		Method:  http.MethodGet,
 		URL:     chatColorPath,
-		Headers: c.headers(req.RequestOptions),
+		Headers: c.headers(req.RequestOptions, TokenTypeAny),
 		Query:   values,
 	}).Do(ctx))
 }
@@ -806,7 +969,9 @@ func (c *helixClient) GetUserChatColors(ctx context.Context, req *GetUserChatCol
 type UpdateUserChatColorRequest struct {
 	*RequestOptions
 
-	// UserID is te ID of the user whose chat color you want to update
+	// UserID is te ID of the user whose chat color you want to update. This ID must match the user ID in the OAuth token.
+	//
+	// Filled from the OAuth token when left empty, if ClientOptions.ResolveActingUser is enabled.
 	UserID string
 
 	// Color to use for the user’s name in chat. All users may specify one of the following named color values.
@@ -849,14 +1014,19 @@ type UpdateUserChatColorRequest struct {
 //
 // Updates the color used for the user’s name in chat.
 func (c *helixClient) UpdateUserChatColor(ctx context.Context, req *UpdateUserChatColorRequest) error {
+	userID, err := c.actingUserID(ctx, req.RequestOptions, req.UserID)
+	if err != nil {
+		return err
+	}
+
 	values := url.Values{}
-	values.Set("user_id", req.UserID)
+	values.Set("user_id", userID)
 	values.Set("color", req.Color)
 
 	return client.WithoutBody(c.Request(&client.RequestConfig{
 		Method:  http.MethodPut,
 		URL:     chatColorPath,
-		Headers: c.headers(req.RequestOptions),
+		Headers: c.headers(req.RequestOptions, TokenTypeUser),
 		Query:   values,
 	}).Do(ctx))
 }
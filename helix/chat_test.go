@@ -3,6 +3,7 @@ package helix
 import (
 	"context"
 	"net/http"
+	"strings"
 	"testing"
 
 	"github.com/aidenwallis/go-twitch-client"
@@ -169,16 +170,20 @@ func TestGetGlobalChatBadges(t *testing.T) {
 		assertToken(t, req)
 		assert.Equal(t, http.MethodGet, req.Method)
 		assert.Equal(t, globalChatBadges, req.URL.String())
-		return testutils.JSONResponse(t, http.StatusOK, &GetGlobalChatBadgesResponse{
-			Data: []*ChatBadge{
+		return testutils.JSONResponse(t, http.StatusOK, map[string]any{
+			"data": []map[string]any{
 				{
-					SetID: "moderator",
-					Versions: []*ChatBadgeVersion{
+					"set_id": "moderator",
+					"versions": []map[string]any{
 						{
-							ID:         "1",
-							ImageURL1x: "https://twitch.tv/1.png",
-							ImageURL2x: "https://twitch.tv/2.png",
-							ImageURL4x: "https://twitch.tv/4.png",
+							"id":           "1",
+							"image_url_1x": "https://twitch.tv/1.png",
+							"image_url_2x": "https://twitch.tv/2.png",
+							"image_url_4x": "https://twitch.tv/4.png",
+							"title":        "Moderator",
+							"description":  "Moderator",
+							"click_action": nil,
+							"click_url":    nil,
 						},
 					},
 				},
@@ -189,6 +194,9 @@ func TestGetGlobalChatBadges(t *testing.T) {
 	resp, err := c.GetGlobalChatBadges(ctx, in)
 	assert.NoError(t, err)
 	assert.Equal(t, 1, len(resp.Data))
+	assert.Equal(t, "moderator", resp.Data[0].SetID)
+	assert.Equal(t, "Moderator", resp.Data[0].Versions[0].Title)
+	assert.Equal(t, true, resp.Data[0].Versions[0].ClickAction == nil)
 }
 
 func TestGetChatSettings(t *testing.T) {
@@ -258,11 +266,68 @@ func TestSendChatAnnouncement(t *testing.T) {
 		assertToken(t, req)
 		assert.Equal(t, http.MethodPost, req.Method)
 		assert.Equal(t, chatAnnouncementsPath+"?broadcaster_id=1&moderator_id=2", req.URL.String())
-		assert.Equal(t, `{"message":"message"}`, testutils.DecodeRawBody(t, req))
+		assert.Equal(t, `{"message":"`+in.Message+`"}`, testutils.DecodeRawBody(t, req))
 		return testutils.EmptyResponse(http.StatusNoContent)
 	})
 
 	assert.NoError(t, c.SendChatAnnouncement(ctx, in))
+
+	// long announcements are sent, Twitch truncates them
+	in.Message = strings.Repeat("a", MaxChatMessageLength+100)
+	assert.NoError(t, c.SendChatAnnouncement(ctx, in))
+
+	in.Message = "  "
+	assert.ErrorIs(t, c.SendChatAnnouncement(ctx, in), ErrEmptyChatMessage)
+}
+
+func TestSendChatMessage(t *testing.T) {
+	ctx := context.Background()
+	in := &SendChatMessageRequest{
+		RequestOptions: requestOptions(),
+
+		BroadcasterID:        "1",
+		SenderID:             "2",
+		Message:              "message",
+		ReplyParentMessageID: "3",
+	}
+
+	c := testClient(func(req *http.Request) *testutils.Response {
+		assertToken(t, req)
+		assert.Equal(t, http.MethodPost, req.Method)
+		assert.Equal(t, chatMessagesPath, req.URL.String())
+		assert.Equal(t, `{"broadcaster_id":"1","sender_id":"2","message":"message","reply_parent_message_id":"3"}`, testutils.DecodeRawBody(t, req))
+		return testutils.JSONResponse(t, http.StatusOK, &SendChatMessageResponse{
+			Data: []*SentChatMessage{
+				{
+					MessageID: "4",
+					IsSent:    false,
+					DropReason: &ChatMessageDropReason{
+						Code:    "msg_duplicate",
+						Message: "message is a duplicate",
+					},
+				},
+			},
+		})
+	})
+
+	resp, err := c.SendChatMessage(ctx, in)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(resp.Data))
+	assert.Equal(t, false, resp.Data[0].IsSent)
+	assert.Equal(t, "msg_duplicate", resp.Data[0].DropReason.Code)
+
+	in.Message = strings.Repeat("a", MaxChatMessageLength+1)
+	_, err = c.SendChatMessage(ctx, in)
+	assert.ErrorIs(t, err, ErrChatMessageTooLong)
+}
+
+func TestValidateChatMessage(t *testing.T) {
+	assert.NoError(t, ValidateChatMessage("hi"))
+	// the limit is in characters, not bytes
+	assert.NoError(t, ValidateChatMessage(strings.Repeat("ä", MaxChatMessageLength)))
+	assert.ErrorIs(t, ValidateChatMessage(strings.Repeat("ä", MaxChatMessageLength+1)), ErrChatMessageTooLong)
+	assert.ErrorIs(t, ValidateChatMessage(" \t\n "), ErrEmptyChatMessage)
+	assert.ErrorIs(t, ValidateChatMessage(""), ErrEmptyChatMessage)
 }
 
 func TestGetUserChatColors(t *testing.T) {
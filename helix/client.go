@@ -11,10 +11,24 @@ import (
 // Client defines the helix client
 type Client interface {
 	Ads
+	Auth
 	Analytics
 	Channels
 	Chat
+	EventSub
 	Users
+
+	// ForToken returns a view of the client whose requests default to the given OAuth token, instead of falling
+	// back to the AccessTokenLoader. A token passed in a request's RequestOptions still takes precedence.
+	//
+	// The view shares the HTTP connection pool and cached state of the client it was created from.
+	ForToken(token string) Client
+
+	// ForUser returns a view of the client whose requests default to the given user's OAuth token, which is loaded
+	// through the UserTokenLoader on every request. A token passed in a request's RequestOptions still takes precedence.
+	//
+	// The view shares the HTTP connection pool and cached state of the client it was created from.
+	ForUser(userID string) Client
 }
 
 // Pagination is the helix pagination response
@@ -28,6 +42,10 @@ type helixClient struct {
 
 	accessTokenLoader AccessTokenLoader
 	clientID          string
+	identities        *identityCache
+	resolveActingUser bool
+	scope             *clientScope
+	userTokenLoader   UserTokenLoader
 }
 
 // RequestOptions are the common options passed to every request
@@ -36,9 +54,52 @@ type RequestOptions struct {
 	Token string
 }
 
+// TokenType defines the types of OAuth token that an endpoint accepts
+type TokenType uint8
+
+const (
+	// TokenTypeApp is an app access token, obtained through the client credentials grant flow.
+	TokenTypeApp TokenType = 1 << iota
+
+	// TokenTypeUser is a user access token, obtained through a flow where the user authorizes your app.
+	TokenTypeUser
+
+	// TokenTypeAny accepts both app and user access tokens.
+	TokenTypeAny = TokenTypeApp | TokenTypeUser
+)
+
+// String returns a stringified token type
+func (t TokenType) String() string {
+	switch t {
+	case TokenTypeApp:
+		return "app access token"
+	case TokenTypeUser:
+		return "user access token"
+	case TokenTypeAny:
+		return "app or user access token"
+	}
+	return "<unknown>"
+}
+
+// TokenTypeError is returned when a request has no token of a type the endpoint accepts. For example, this is
+// returned by endpoints requiring a user access token when no token was passed in RequestOptions, rather than
+// falling back to the app access token from AccessTokenLoader, which Twitch would reject.
+type TokenTypeError struct {
+	// Accepted is the set of token types accepted by the endpoint.
+	Accepted TokenType
+}
+
+// Error returns a stringified error
+func (e *TokenTypeError) Error() string {
+	return "helix: endpoint requires a " + e.Accepted.String() + ", but none was provided in RequestOptions"
+}
+
 // AccessTokenLoader is the loader to return a generic app access token
 type AccessTokenLoader func(ctx context.Context) (appAccessToken string, err error)
 
+// UserTokenLoader is the loader to return a user access token for the given user, used by ForUser
+type UserTokenLoader func(ctx context.Context, userID string) (userAccessToken string, err error)
+
 // ClientOptions defines all options this client supports.
 type ClientOptions struct {
 	// ClientID is your third-party client ID that you will use for interacting with the
@@ -68,7 +129,24 @@ type ClientOptions struct {
 	// to that in these cases.
 	//
 	// If you do not define a loader, it will simply return an error in those cases instead.
+	//
+	// The loader is never used for endpoints that only accept user access tokens, these return a TokenTypeError
+	// when no token is provided instead.
 	AccessTokenLoader AccessTokenLoader
+
+	// ResolveActingUser enables resolving the user behind the OAuth token passed in RequestOptions, so that
+	// "acting user" fields which must match that user (such as UpdateChatSettingsRequest.ModeratorID or
+	// UpdateUserChatColorRequest.UserID) are filled automatically when left empty.
+	//
+	// Tokens are resolved using ValidateToken, and the result is cached until the token expires, for at most
+	// an hour. Requests that supply an acting user ID that disagrees with the token are rejected with an
+	// ActingUserMismatchError before calling Twitch.
+	ResolveActingUser bool
+
+	// UserTokenLoader is an optional argument that loads user access tokens from your token store, for clients
+	// returned by ForUser. It is invoked for every request made by those clients, so you should cache tokens
+	// in your loader where appropriate.
+	UserTokenLoader UserTokenLoader
 }
 
 // NewClient creates a new instance of Client
@@ -80,6 +158,9 @@ func NewClient(options *ClientOptions) Client {
 	return &helixClient{
 		accessTokenLoader: options.AccessTokenLoader,
 		clientID:          options.ClientID,
+		identities:        newIdentityCache(),
+		resolveActingUser: options.ResolveActingUser,
+		userTokenLoader:   options.UserTokenLoader,
 		Client: client.NewClient(&client.Options{
 			RequestTimeout: options.RequestTimeout,
 			Transport:      options.Transport,
@@ -87,15 +168,24 @@ func NewClient(options *ClientOptions) Client {
 	}
 }
 
-func (c *helixClient) headers(options *RequestOptions) func(context.Context) (http.Header, error) {
+// headers builds the request headers, accepts defines the token types the endpoint accepts. The app access token
+// from AccessTokenLoader is only used as a fallback when the endpoint accepts app access tokens.
+func (c *helixClient) headers(options *RequestOptions, accepts TokenType) func(context.Context) (http.Header, error) {
 	return func(ctx context.Context) (http.Header, error) {
 		h := http.Header{}
 
 		h.Set("Accept", "application/json")
 		h.Set("Client-ID", c.clientID)
 
-		if options != nil && options.Token != "" {
-			setToken(h, options.Token)
+		token, err := c.requestToken(ctx, options)
+		if err != nil {
+			return nil, err
+		}
+
+		if token != "" {
+			setToken(h, token)
+		} else if accepts&TokenTypeApp == 0 {
+			return nil, &TokenTypeError{Accepted: accepts}
 		} else if c.accessTokenLoader != nil {
 			token, err := c.accessTokenLoader(ctx)
 			if err != nil {
@@ -39,10 +39,11 @@ func TestDefaultOptions(t *testing.T) {
 	c := NewClient(nil)
 	c.(*helixClient).Transport = testutils.Middleware(func(req *http.Request) *testutils.Response {
 		assert.Equal(t, "", req.Header.Get("Client-ID"))
-		return testutils.EmptyResponse(http.StatusOK)
+		return testutils.JSONResponse(t, http.StatusOK, &GetUsersResponse{})
 	})
 
-	assert.NoError(t, c.BlockUser(ctx, &BlockUserRequest{TargetUserID: "id"}))
+	_, err := c.GetUsers(ctx, &GetUsersRequest{IDs: []string{"id"}})
+	assert.NoError(t, err)
 }
 
 func TestAccessTokenLoader(t *testing.T) {
@@ -59,7 +60,7 @@ func TestAccessTokenLoader(t *testing.T) {
 				return testutils.EmptyResponse(http.StatusOK)
 			}),
 		})
-		if c.BlockUser(ctx, &BlockUserRequest{TargetUserID: "id"}) != expected {
+		if _, err := c.GetUsers(ctx, &GetUsersRequest{IDs: []string{"id"}}); err != expected {
 			t.Error("expected error to be thrown")
 		}
 	})
@@ -72,9 +73,47 @@ func TestAccessTokenLoader(t *testing.T) {
 			ClientID: fakeClientID,
 			Transport: testutils.Middleware(func(req *http.Request) *testutils.Response {
 				assertToken(t, req)
-				return testutils.EmptyResponse(http.StatusOK)
+				return testutils.JSONResponse(t, http.StatusOK, &GetUsersResponse{})
 			}),
 		})
-		assert.NoError(t, c.BlockUser(ctx, &BlockUserRequest{TargetUserID: "id"}))
+		_, err := c.GetUsers(ctx, &GetUsersRequest{IDs: []string{"id"}})
+		assert.NoError(t, err)
+	})
+}
+
+func TestTokenTypeEnforcement(t *testing.T) {
+	ctx := context.Background()
+	loaderCalled := false
+
+	c := NewClient(&ClientOptions{
+		AccessTokenLoader: func(ctx context.Context) (string, error) {
+			loaderCalled = true
+			return fakeToken, nil
+		},
+		ClientID: fakeClientID,
+		Transport: testutils.Middleware(func(req *http.Request) *testutils.Response {
+			assertToken(t, req)
+			return testutils.EmptyResponse(http.StatusNoContent)
+		}),
+	})
+
+	t.Run("user token required", func(t *testing.T) {
+		err := c.BlockUser(ctx, &BlockUserRequest{TargetUserID: "id"})
+
+		var tokenErr *TokenTypeError
+		assert.Equal(t, true, errors.As(err, &tokenErr))
+		assert.Equal(t, TokenTypeUser, tokenErr.Accepted)
+		assert.Equal(t, "helix: endpoint requires a user access token, but none was provided in RequestOptions", err.Error())
+		assert.Equal(t, false, loaderCalled, "app token must not be loaded")
+	})
+
+	t.Run("user token provided", func(t *testing.T) {
+		assert.NoError(t, c.BlockUser(ctx, &BlockUserRequest{RequestOptions: requestOptions(), TargetUserID: "id"}))
+	})
+
+	t.Run("strings", func(t *testing.T) {
+		assert.Equal(t, "app access token", TokenTypeApp.String())
+		assert.Equal(t, "app or user access token", TokenTypeAny.String())
+		assert.Equal(t, "<unknown>", TokenType(0).String())
 	})
 }
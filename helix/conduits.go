@@ -0,0 +1,250 @@
+package helix
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/aidenwallis/go-twitch-client/internal/client"
+)
+
+const (
+	conduitsPath      = "https://api.twitch.tv/helix/eventsub/conduits"
+	conduitShardsPath = "https://api.twitch.tv/helix/eventsub/conduits/shards"
+)
+
+// Conduit is an EventSub conduit, which delivers notifications of its subscriptions to a set of shards
+type Conduit struct {
+	// ID is the conduit ID.
+	ID string `json:"id"`
+
+	// ShardCount is the number of shards associated with this conduit.
+	ShardCount int `json:"shard_count"`
+}
+
+// ConduitShard is a shard of a conduit, backed by either a webhook or a WebSocket session
+type ConduitShard struct {
+	// ID is the shard ID.
+	ID string `json:"id"`
+
+	// Status of the shard, one of the EventSubStatus constants.
+	Status string `json:"status"`
+
+	// Transport is the transport details used to send the notifications.
+	Transport EventSubTransport `json:"transport"`
+}
+
+// ConduitShardError is the reason a single shard could not be updated by UpdateConduitShards
+type ConduitShardError struct {
+	// ID is the shard ID.
+	ID string `json:"id"`
+
+	// Message is the error that occurred while updating the shard.
+	Message string `json:"message"`
+
+	// Code is the error code used to represent a specific error condition while attempting to update shards.
+	Code string `json:"code"`
+}
+
+// GetConduitsRequest defines the options passed to GetConduits
+type GetConduitsRequest struct {
+	*RequestOptions
+}
+
+// GetConduitsResponse defines the API response returned by GetConduits
+type GetConduitsResponse struct {
+	// Data contains the conduits created by the client ID in the access token.
+	Data []*Conduit `json:"data"`
+}
+
+// GetConduits implements https://dev.twitch.tv/docs/api/reference#get-conduits
+//
+// Gets the conduits for a client ID.
+func (c *helixClient) GetConduits(ctx context.Context, req *GetConduitsRequest) (*GetConduitsResponse, error) {
+	return client.WithBody[GetConduitsResponse](c.Request(&client.RequestConfig{
+		Method:  http.MethodGet,
+		URL:     conduitsPath,
+		Headers: c.headers(req.RequestOptions, TokenTypeApp),
+	}).Do(ctx))
+}
+
+// CreateConduitsRequest defines the options passed to CreateConduits
+type CreateConduitsRequest struct {
+	*RequestOptions
+
+	// ShardCount is the number of shards to create for this conduit.
+	ShardCount int
+}
+
+// CreateConduitsResponse defines the API response returned by CreateConduits
+type CreateConduitsResponse struct {
+	// Data contains the single conduit that was created.
+	Data []*Conduit `json:"data"`
+}
+
+// createConduitsBody implements the request body structure for CreateConduits
+type createConduitsBody struct {
+	ShardCount int `json:"shard_count"`
+}
+
+// CreateConduits implements https://dev.twitch.tv/docs/api/reference#create-conduits
+//
+// Creates a new conduit.
+func (c *helixClient) CreateConduits(ctx context.Context, req *CreateConduitsRequest) (*CreateConduitsResponse, error) {
+	return client.WithBody[CreateConduitsResponse](c.Request(&client.RequestConfig{
+		Method:  http.MethodPost,
+		URL:     conduitsPath,
+		Headers: c.headers(req.RequestOptions, TokenTypeApp),
+	}).BodyJSON(&createConduitsBody{ShardCount: req.ShardCount}).Do(ctx))
+}
+
+// UpdateConduitsRequest defines the options passed to UpdateConduits
+type UpdateConduitsRequest struct {
+	*RequestOptions
+
+	// ID is the conduit ID.
+	ID string
+
+	// ShardCount is the new number of shards for this conduit. Shards beyond the new count are removed.
+	ShardCount int
+}
+
+// UpdateConduitsResponse defines the API response returned by UpdateConduits
+type UpdateConduitsResponse struct {
+	// Data contains the single conduit that was updated.
+	Data []*Conduit `json:"data"`
+}
+
+// updateConduitsBody implements the request body structure for UpdateConduits
+type updateConduitsBody struct {
+	ID         string `json:"id"`
+	ShardCount int    `json:"shard_count"`
+}
+
+// UpdateConduits implements https://dev.twitch.tv/docs/api/reference#update-conduits
+//
+// Updates a conduit’s shard count.
+func (c *helixClient) UpdateConduits(ctx context.Context, req *UpdateConduitsRequest) (*UpdateConduitsResponse, error) {
+	return client.WithBody[UpdateConduitsResponse](c.Request(&client.RequestConfig{
+		Method:  http.MethodPatch,
+		URL:     conduitsPath,
+		Headers: c.headers(req.RequestOptions, TokenTypeApp),
+	}).BodyJSON(&updateConduitsBody{ID: req.ID, ShardCount: req.ShardCount}).Do(ctx))
+}
+
+// DeleteConduitRequest defines the options passed to DeleteConduit
+type DeleteConduitRequest struct {
+	*RequestOptions
+
+	// ID is the conduit ID.
+	ID string
+}
+
+// DeleteConduit implements https://dev.twitch.tv/docs/api/reference#delete-conduit
+//
+// Deletes a specified conduit. Note that it may take some time for Eventsub subscriptions on a deleted conduit to show
+// as disabled when calling GetEventSubSubscriptions.
+func (c *helixClient) DeleteConduit(ctx context.Context, req *DeleteConduitRequest) error {
+	values := url.Values{}
+	values.Set("id", req.ID)
+
+	return client.WithoutBody(c.Request(&client.RequestConfig{
+		Method:  http.MethodDelete,
+		URL:     conduitsPath,
+		Headers: c.headers(req.RequestOptions, TokenTypeApp),
+		Query:   values,
+	}).Do(ctx))
+}
+
+// GetConduitShardsRequest defines the options passed to GetConduitShards
+type GetConduitShardsRequest struct {
+	*RequestOptions
+
+	// ConduitID is the conduit ID.
+	ConduitID string
+
+	// Status (optional) filters the list to shards with this status, see the EventSubStatus constants.
+	Status string
+
+	// After (optional) is the cursor used to get the next page of results. The Pagination object in the response
+	// contains the cursor’s value.
+	After string
+}
+
+// GetConduitShardsResponse defines the API response returned by GetConduitShards
+type GetConduitShardsResponse struct {
+	// Data contains a slice of ConduitShard
+	Data []*ConduitShard `json:"data"`
+
+	// Pagination contains a cursor value, to be used in a subsequent request to specify the starting point of the next set of results.
+	Pagination Pagination `json:"pagination"`
+}
+
+// GetConduitShards implements https://dev.twitch.tv/docs/api/reference#get-conduit-shards
+//
+// Gets a lists of all shards for a conduit.
+func (c *helixClient) GetConduitShards(ctx context.Context, req *GetConduitShardsRequest) (*GetConduitShardsResponse, error) {
+	values := url.Values{}
+	values.Set("conduit_id", req.ConduitID)
+	if req.Status != "" {
+		values.Set("status", req.Status)
+	}
+	if req.After != "" {
+		values.Set("after", req.After)
+	}
+
+	return client.WithBody[GetConduitShardsResponse](c.Request(&client.RequestConfig{
+		Method:  http.MethodGet,
+		URL:     conduitShardsPath,
+		Headers: c.headers(req.RequestOptions, TokenTypeApp),
+		Query:   values,
+	}).Do(ctx))
+}
+
+// ConduitShardUpdate assigns a transport to a single shard
+type ConduitShardUpdate struct {
+	// ID is the shard ID.
+	ID string `json:"id"`
+
+	// Transport is the webhook or WebSocket transport of the shard, use WebhookTransport or WebSocketTransport to
+	// create one.
+	Transport *EventSubTransport `json:"transport"`
+}
+
+// UpdateConduitShardsRequest defines the options passed to UpdateConduitShards
+type UpdateConduitShardsRequest struct {
+	*RequestOptions
+
+	// ConduitID is the conduit ID.
+	ConduitID string
+
+	// Shards is the list of shards to update.
+	Shards []*ConduitShardUpdate
+}
+
+// UpdateConduitShardsResponse defines the API response returned by UpdateConduitShards
+type UpdateConduitShardsResponse struct {
+	// Data contains the shards that were successfully updated.
+	Data []*ConduitShard `json:"data"`
+
+	// Errors contains the shards that could not be updated, and why.
+	Errors []*ConduitShardError `json:"errors"`
+}
+
+// updateConduitShardsBody implements the request body structure for UpdateConduitShards
+type updateConduitShardsBody struct {
+	ConduitID string                `json:"conduit_id"`
+	Shards    []*ConduitShardUpdate `json:"shards"`
+}
+
+// UpdateConduitShards implements https://dev.twitch.tv/docs/api/reference#update-conduit-shards
+//
+// Updates shard(s) for a conduit. Shards that fail to update are reported in UpdateConduitShardsResponse.Errors,
+// while the others are still updated.
+func (c *helixClient) UpdateConduitShards(ctx context.Context, req *UpdateConduitShardsRequest) (*UpdateConduitShardsResponse, error) {
+	return client.WithBody[UpdateConduitShardsResponse](c.Request(&client.RequestConfig{
+		Method:  http.MethodPatch,
+		URL:     conduitShardsPath,
+		Headers: c.headers(req.RequestOptions, TokenTypeApp),
+	}).BodyJSON(&updateConduitShardsBody{ConduitID: req.ConduitID, Shards: req.Shards}).Do(ctx))
+}
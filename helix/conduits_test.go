@@ -0,0 +1,131 @@
+package helix
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/aidenwallis/go-twitch-client/internal/testutils"
+	"github.com/aidenwallis/go-twitch-client/internal/testutils/assert"
+)
+
+func TestGetConduits(t *testing.T) {
+	c := testClient(func(req *http.Request) *testutils.Response {
+		assertToken(t, req)
+		assert.Equal(t, http.MethodGet, req.Method)
+		assert.Equal(t, conduitsPath, req.URL.String())
+		return testutils.JSONResponse(t, http.StatusOK, &GetConduitsResponse{
+			Data: []*Conduit{{ID: "conduit", ShardCount: 5}},
+		})
+	})
+
+	resp, err := c.GetConduits(context.Background(), &GetConduitsRequest{RequestOptions: requestOptions()})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(resp.Data))
+	assert.Equal(t, "conduit", resp.Data[0].ID)
+	assert.Equal(t, 5, resp.Data[0].ShardCount)
+}
+
+func TestCreateConduits(t *testing.T) {
+	c := testClient(func(req *http.Request) *testutils.Response {
+		assertToken(t, req)
+		assert.Equal(t, http.MethodPost, req.Method)
+		assert.Equal(t, conduitsPath, req.URL.String())
+		assert.Equal(t, `{"shard_count":5}`, testutils.DecodeRawBody(t, req))
+		return testutils.JSONResponse(t, http.StatusOK, &CreateConduitsResponse{
+			Data: []*Conduit{{ID: "conduit", ShardCount: 5}},
+		})
+	})
+
+	resp, err := c.CreateConduits(context.Background(), &CreateConduitsRequest{RequestOptions: requestOptions(), ShardCount: 5})
+	assert.NoError(t, err)
+	assert.Equal(t, "conduit", resp.Data[0].ID)
+}
+
+func TestUpdateConduits(t *testing.T) {
+	c := testClient(func(req *http.Request) *testutils.Response {
+		assertToken(t, req)
+		assert.Equal(t, http.MethodPatch, req.Method)
+		assert.Equal(t, conduitsPath, req.URL.String())
+		assert.Equal(t, `{"id":"conduit","shard_count":3}`, testutils.DecodeRawBody(t, req))
+		return testutils.JSONResponse(t, http.StatusOK, &UpdateConduitsResponse{
+			Data: []*Conduit{{ID: "conduit", ShardCount: 3}},
+		})
+	})
+
+	resp, err := c.UpdateConduits(context.Background(), &UpdateConduitsRequest{RequestOptions: requestOptions(), ID: "conduit", ShardCount: 3})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, resp.Data[0].ShardCount)
+}
+
+func TestDeleteConduit(t *testing.T) {
+	c := testClient(func(req *http.Request) *testutils.Response {
+		assertToken(t, req)
+		assert.Equal(t, http.MethodDelete, req.Method)
+		assert.Equal(t, conduitsPath+"?id=conduit", req.URL.String())
+		return testutils.EmptyResponse(http.StatusNoContent)
+	})
+
+	assert.NoError(t, c.DeleteConduit(context.Background(), &DeleteConduitRequest{RequestOptions: requestOptions(), ID: "conduit"}))
+}
+
+func TestGetConduitShards(t *testing.T) {
+	c := testClient(func(req *http.Request) *testutils.Response {
+		assertToken(t, req)
+		assert.Equal(t, http.MethodGet, req.Method)
+		assert.Equal(t, conduitShardsPath, testutils.WithoutQuery(req.URL))
+		assert.Equal(t, "conduit", req.URL.Query().Get("conduit_id"))
+		assert.Equal(t, EventSubStatusEnabled, req.URL.Query().Get("status"))
+		assert.Equal(t, "cursor", req.URL.Query().Get("after"))
+		return testutils.JSONResponse(t, http.StatusOK, &GetConduitShardsResponse{
+			Data: []*ConduitShard{
+				{ID: "0", Status: EventSubStatusEnabled, Transport: *WebSocketTransport("session")},
+			},
+			Pagination: Pagination{Cursor: "next"},
+		})
+	})
+
+	resp, err := c.GetConduitShards(context.Background(), &GetConduitShardsRequest{
+		RequestOptions: requestOptions(),
+		ConduitID:      "conduit",
+		Status:         EventSubStatusEnabled,
+		After:          "cursor",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "session", resp.Data[0].Transport.SessionID)
+	assert.Equal(t, "next", resp.Pagination.Cursor)
+}
+
+func TestUpdateConduitShards(t *testing.T) {
+	c := testClient(func(req *http.Request) *testutils.Response {
+		assertToken(t, req)
+		assert.Equal(t, http.MethodPatch, req.Method)
+		assert.Equal(t, conduitShardsPath, req.URL.String())
+		assert.Equal(
+			t,
+			`{"conduit_id":"conduit","shards":[{"id":"0","transport":{"method":"websocket","session_id":"session"}},{"id":"1","transport":{"method":"webhook","callback":"https://example.com","secret":"supersecret"}}]}`,
+			testutils.DecodeRawBody(t, req),
+		)
+		return testutils.JSONResponse(t, http.StatusAccepted, &UpdateConduitShardsResponse{
+			Data: []*ConduitShard{
+				{ID: "0", Status: EventSubStatusEnabled, Transport: *WebSocketTransport("session")},
+			},
+			Errors: []*ConduitShardError{
+				{ID: "1", Message: "The callback verification failed.", Code: "callback_verification_failed"},
+			},
+		})
+	})
+
+	resp, err := c.UpdateConduitShards(context.Background(), &UpdateConduitShardsRequest{
+		RequestOptions: requestOptions(),
+		ConduitID:      "conduit",
+		Shards: []*ConduitShardUpdate{
+			{ID: "0", Transport: WebSocketTransport("session")},
+			{ID: "1", Transport: WebhookTransport("https://example.com", "supersecret")},
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(resp.Data))
+	assert.Equal(t, "1", resp.Errors[0].ID)
+	assert.Equal(t, "callback_verification_failed", resp.Errors[0].Code)
+}
@@ -0,0 +1,347 @@
+package helix
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/aidenwallis/go-twitch-client/internal/client"
+)
+
+// EventSub represents the EventSub namespace
+type EventSub interface {
+	// CreateEventSubSubscription implements https://dev.twitch.tv/docs/api/reference#create-eventsub-subscription
+	//
+	// Creates an EventSub subscription. The subscription type and version are taken from the typed condition.
+	CreateEventSubSubscription(context.Context, *CreateEventSubSubscriptionRequest) (*CreateEventSubSubscriptionResponse, error)
+
+	// DeleteEventSubSubscription implements https://dev.twitch.tv/docs/api/reference#delete-eventsub-subscription
+	//
+	// Deletes an EventSub subscription.
+	DeleteEventSubSubscription(context.Context, *DeleteEventSubSubscriptionRequest) error
+
+	// GetEventSubSubscriptions implements https://dev.twitch.tv/docs/api/reference#get-eventsub-subscriptions
+	//
+	// Gets a list of EventSub subscriptions that the client in the access token created.
+	GetEventSubSubscriptions(context.Context, *GetEventSubSubscriptionsRequest) (*GetEventSubSubscriptionsResponse, error)
+
+	// GetConduits implements https://dev.twitch.tv/docs/api/reference#get-conduits
+	//
+	// Gets the conduits for a client ID.
+	GetConduits(context.Context, *GetConduitsRequest) (*GetConduitsResponse, error)
+
+	// CreateConduits implements https://dev.twitch.tv/docs/api/reference#create-conduits
+	//
+	// Creates a new conduit.
+	CreateConduits(context.Context, *CreateConduitsRequest) (*CreateConduitsResponse, error)
+
+	// UpdateConduits implements https://dev.twitch.tv/docs/api/reference#update-conduits
+	//
+	// Updates a conduit’s shard count.
+	UpdateConduits(context.Context, *UpdateConduitsRequest) (*UpdateConduitsResponse, error)
+
+	// DeleteConduit implements https://dev.twitch.tv/docs/api/reference#delete-conduit
+	//
+	// Deletes a specified conduit.
+	DeleteConduit(context.Context, *DeleteConduitRequest) error
+
+	// GetConduitShards implements https://dev.twitch.tv/docs/api/reference#get-conduit-shards
+	//
+	// Gets a lists of all shards for a conduit.
+	GetConduitShards(context.Context, *GetConduitShardsRequest) (*GetConduitShardsResponse, error)
+
+	// UpdateConduitShards implements https://dev.twitch.tv/docs/api/reference#update-conduit-shards
+	//
+	// Updates shard(s) for a conduit.
+	UpdateConduitShards(context.Context, *UpdateConduitShardsRequest) (*UpdateConduitShardsResponse, error)
+}
+
+const eventSubSubscriptionsPath = "https://api.twitch.tv/helix/eventsub/subscriptions"
+
+// ErrMissingEventSubCondition is returned by CreateEventSubSubscription when no condition or transport was given.
+var ErrMissingEventSubCondition = errors.New("helix: eventsub subscriptions require a condition and transport")
+
+const (
+	// EventSubTransportWebhook delivers notifications to a HTTPS callback.
+	EventSubTransportWebhook = "webhook"
+
+	// EventSubTransportWebSocket delivers notifications over an EventSub WebSocket session.
+	EventSubTransportWebSocket = "websocket"
+
+	// EventSubTransportConduit delivers notifications to the shards of a conduit.
+	EventSubTransportConduit = "conduit"
+)
+
+const (
+	// EventSubStatusEnabled means the subscription is enabled.
+	EventSubStatusEnabled = "enabled"
+
+	// EventSubStatusWebhookCallbackVerificationPending means the subscription is pending verification of the specified callback URL.
+	EventSubStatusWebhookCallbackVerificationPending = "webhook_callback_verification_pending"
+
+	// EventSubStatusWebhookCallbackVerificationFailed means the specified callback URL failed verification.
+	EventSubStatusWebhookCallbackVerificationFailed = "webhook_callback_verification_failed"
+
+	// EventSubStatusNotificationFailuresExceeded means the notification delivery failure rate was too high.
+	EventSubStatusNotificationFailuresExceeded = "notification_failures_exceeded"
+
+	// EventSubStatusAuthorizationRevoked means the authorization was revoked for one or more users specified in the condition.
+	EventSubStatusAuthorizationRevoked = "authorization_revoked"
+
+	// EventSubStatusModeratorRemoved means the moderator that authorized the subscription is no longer one of the broadcaster's moderators.
+	EventSubStatusModeratorRemoved = "moderator_removed"
+
+	// EventSubStatusUserRemoved means one of the users specified in the condition was removed.
+	EventSubStatusUserRemoved = "user_removed"
+
+	// EventSubStatusVersionRemoved means the subscribed to subscription type and version is no longer supported.
+	EventSubStatusVersionRemoved = "version_removed"
+
+	// EventSubStatusWebSocketDisconnected means the client closed the connection.
+	EventSubStatusWebSocketDisconnected = "websocket_disconnected"
+
+	// EventSubStatusWebSocketFailedPingPong means the client failed to respond to a ping message.
+	EventSubStatusWebSocketFailedPingPong = "websocket_failed_ping_pong"
+
+	// EventSubStatusWebSocketReceivedInboundTraffic means the client sent a non-pong message.
+	EventSubStatusWebSocketReceivedInboundTraffic = "websocket_received_inbound_traffic"
+
+	// EventSubStatusWebSocketConnectionUnused means the client failed to subscribe to events within the required time.
+	EventSubStatusWebSocketConnectionUnused = "websocket_connection_unused"
+
+	// EventSubStatusWebSocketInternalError means the Twitch WebSocket server experienced an unexpected error.
+	EventSubStatusWebSocketInternalError = "websocket_internal_error"
+
+	// EventSubStatusWebSocketNetworkTimeout means the Twitch WebSocket server timed out writing the message to the client.
+	EventSubStatusWebSocketNetworkTimeout = "websocket_network_timeout"
+
+	// EventSubStatusWebSocketNetworkError means the Twitch WebSocket server experienced a network error writing the message to the client.
+	EventSubStatusWebSocketNetworkError = "websocket_network_error"
+
+	// EventSubStatusWebSocketFailedToReconnect means the client failed to reconnect to the Twitch WebSocket server within
+	// the required time after a session reconnect message. Only used for conduit shards.
+	EventSubStatusWebSocketFailedToReconnect = "websocket_failed_to_reconnect"
+)
+
+// EventSubTransport defines how EventSub notifications are delivered
+type EventSubTransport struct {
+	// Method is the transport method, one of EventSubTransportWebhook, EventSubTransportWebSocket or EventSubTransportConduit.
+	Method string `json:"method"`
+
+	// Callback is the callback URL where the notifications are sent. Only set for webhooks.
+	Callback string `json:"callback,omitempty"`
+
+	// Secret is the secret used to verify the signature of webhook notifications, it must be between 10 and 100 characters.
+	// Only set when creating webhook subscriptions, Twitch never returns it.
+	Secret string `json:"secret,omitempty"`
+
+	// SessionID is an ID that identifies the WebSocket that notifications are sent to. Only set for WebSockets.
+	SessionID string `json:"session_id,omitempty"`
+
+	// ConduitID is an ID that identifies the conduit that notifications are sent to. Only set for conduits.
+	ConduitID string `json:"conduit_id,omitempty"`
+
+	// ConnectedAt is the time the WebSocket connection was established. Only returned by Twitch for WebSockets.
+	ConnectedAt *time.Time `json:"connected_at,omitempty"`
+
+	// DisconnectedAt is the time the WebSocket connection was lost. Only returned by Twitch for WebSockets.
+	DisconnectedAt *time.Time `json:"disconnected_at,omitempty"`
+}
+
+// WebhookTransport returns a transport that delivers notifications to the webhook callback, signed using the secret.
+func WebhookTransport(callback, secret string) *EventSubTransport {
+	return &EventSubTransport{Method: EventSubTransportWebhook, Callback: callback, Secret: secret}
+}
+
+// WebSocketTransport returns a transport that delivers notifications to the given EventSub WebSocket session.
+func WebSocketTransport(sessionID string) *EventSubTransport {
+	return &EventSubTransport{Method: EventSubTransportWebSocket, SessionID: sessionID}
+}
+
+// ConduitTransport returns a transport that delivers notifications to the given conduit.
+func ConduitTransport(conduitID string) *EventSubTransport {
+	return &EventSubTransport{Method: EventSubTransportConduit, ConduitID: conduitID}
+}
+
+// tokenType returns the token type Twitch requires when creating subscriptions with this transport. WebSocket
+// subscriptions require a user access token, webhooks and conduits require an app access token.
+func (t *EventSubTransport) tokenType() TokenType {
+	if t.Method == EventSubTransportWebSocket {
+		return TokenTypeUser
+	}
+	return TokenTypeApp
+}
+
+// EventSubSubscription represents an EventSub subscription in Helix
+type EventSubSubscription struct {
+	// ID is an ID that identifies the subscription.
+	ID string `json:"id"`
+
+	// Status is the subscription’s status, see the EventSubStatus constants. The subscriber receives events only for enabled subscriptions.
+	Status string `json:"status"`
+
+	// Type is the subscription’s type.
+	Type string `json:"type"`
+
+	// Version is the version number that identifies this definition of the subscription’s data.
+	Version string `json:"version"`
+
+	// Condition is the subscription’s parameter values.
+	Condition map[string]string `json:"condition"`
+
+	// CreatedAt is the date and time of when the subscription was created.
+	CreatedAt time.Time `json:"created_at"`
+
+	// Transport is the transport details used to send the notifications.
+	Transport EventSubTransport `json:"transport"`
+
+	// Cost is the amount that the subscription counts against your limit.
+	Cost int `json:"cost"`
+}
+
+// CreateEventSubSubscriptionRequest defines the options passed to CreateEventSubSubscription
+type CreateEventSubSubscriptionRequest struct {
+	*RequestOptions
+
+	// Condition is the typed condition of the subscription type to create, it also defines the type and version
+	// of the subscription. Use RawEventSubCondition for types that have no typed condition.
+	Condition EventSubCondition
+
+	// Transport is the transport details that you want Twitch to use when sending you notifications. Use
+	// WebhookTransport, WebSocketTransport or ConduitTransport to create one.
+	//
+	// Webhook and conduit subscriptions require an app access token, WebSocket subscriptions require a user access token.
+	Transport *EventSubTransport
+}
+
+// CreateEventSubSubscriptionResponse defines the API response returned by CreateEventSubSubscription
+type CreateEventSubSubscriptionResponse struct {
+	// Data contains the single subscription that you created.
+	Data []*EventSubSubscription `json:"data"`
+
+	// Total is the total number of subscriptions that you’ve created.
+	Total int `json:"total"`
+
+	// TotalCost is the sum of all of your subscription costs.
+	TotalCost int `json:"total_cost"`
+
+	// MaxTotalCost is the maximum total cost that you’re allowed to incur for all subscriptions that you create.
+	MaxTotalCost int `json:"max_total_cost"`
+}
+
+// createEventSubSubscriptionBody implements the request body structure for CreateEventSubSubscription
+type createEventSubSubscriptionBody struct {
+	Type      string             `json:"type"`
+	Version   string             `json:"version"`
+	Condition EventSubCondition  `json:"condition"`
+	Transport *EventSubTransport `json:"transport"`
+}
+
+// CreateEventSubSubscription implements https://dev.twitch.tv/docs/api/reference#create-eventsub-subscription
+//
+// Creates an EventSub subscription. The subscription type and version are taken from the typed condition.
+func (c *helixClient) CreateEventSubSubscription(ctx context.Context, req *CreateEventSubSubscriptionRequest) (*CreateEventSubSubscriptionResponse, error) {
+	if req.Condition == nil || req.Transport == nil {
+		return nil, ErrMissingEventSubCondition
+	}
+
+	return client.WithBody[CreateEventSubSubscriptionResponse](c.Request(&client.RequestConfig{
+		Method:  http.MethodPost,
+		URL:     eventSubSubscriptionsPath,
+		Headers: c.headers(req.RequestOptions, req.Transport.tokenType()),
+	}).BodyJSON(&createEventSubSubscriptionBody{
+		Type:      req.Condition.EventSubType(),
+		Version:   req.Condition.EventSubVersion(),
+		Condition: req.Condition,
+		Transport: req.Transport,
+	}).Do(ctx))
+}
+
+// DeleteEventSubSubscriptionRequest defines the options passed to DeleteEventSubSubscription
+type DeleteEventSubSubscriptionRequest struct {
+	*RequestOptions
+
+	// ID is the ID of the subscription to delete.
+	ID string
+}
+
+// DeleteEventSubSubscription implements https://dev.twitch.tv/docs/api/reference#delete-eventsub-subscription
+//
+// Deletes an EventSub subscription.
+func (c *helixClient) DeleteEventSubSubscription(ctx context.Context, req *DeleteEventSubSubscriptionRequest) error {
+	values := url.Values{}
+	values.Set("id", req.ID)
+
+	return client.WithoutBody(c.Request(&client.RequestConfig{
+		Method:  http.MethodDelete,
+		URL:     eventSubSubscriptionsPath,
+		Headers: c.headers(req.RequestOptions, TokenTypeAny),
+		Query:   values,
+	}).Do(ctx))
+}
+
+// GetEventSubSubscriptionsRequest defines the options passed to GetEventSubSubscriptions
+//
+// Twitch only accepts one of Status, Type or UserID per request.
+type GetEventSubSubscriptionsRequest struct {
+	*RequestOptions
+
+	// Status (optional) filters the list to subscriptions with this status, see the EventSubStatus constants.
+	Status string
+
+	// Type (optional) filters the list to subscriptions of this subscription type.
+	Type string
+
+	// UserID (optional) filters the list to subscriptions that have a condition referencing this user ID.
+	UserID string
+
+	// After (optional) is the cursor used to get the next page of results. The Pagination object in the response
+	// contains the cursor’s value.
+	After string
+}
+
+// GetEventSubSubscriptionsResponse defines the API response returned by GetEventSubSubscriptions
+type GetEventSubSubscriptionsResponse struct {
+	// Data contains a slice of EventSubSubscription
+	Data []*EventSubSubscription `json:"data"`
+
+	// Total is the total number of subscriptions that you’ve created.
+	Total int `json:"total"`
+
+	// TotalCost is the sum of all of your subscription costs.
+	TotalCost int `json:"total_cost"`
+
+	// MaxTotalCost is the maximum total cost that you’re allowed to incur for all subscriptions that you create.
+	MaxTotalCost int `json:"max_total_cost"`
+
+	// Pagination contains a cursor value, to be used in a subsequent request to specify the starting point of the next set of results.
+	Pagination Pagination `json:"pagination"`
+}
+
+// GetEventSubSubscriptions implements https://dev.twitch.tv/docs/api/reference#get-eventsub-subscriptions
+//
+// Gets a list of EventSub subscriptions that the client in the access token created.
+func (c *helixClient) GetEventSubSubscriptions(ctx context.Context, req *GetEventSubSubscriptionsRequest) (*GetEventSubSubscriptionsResponse, error) {
+	values := url.Values{}
+	if req.Status != "" {
+		values.Set("status", req.Status)
+	}
+	if req.Type != "" {
+		values.Set("type", req.Type)
+	}
+	if req.UserID != "" {
+		values.Set("user_id", req.UserID)
+	}
+	if req.After != "" {
+		values.Set("after", req.After)
+	}
+
+	return client.WithBody[GetEventSubSubscriptionsResponse](c.Request(&client.RequestConfig{
+		Method:  http.MethodGet,
+		URL:     eventSubSubscriptionsPath,
+		Headers: c.headers(req.RequestOptions, TokenTypeAny),
+		Query:   values,
+	}).Do(ctx))
+}
@@ -0,0 +1,539 @@
+package helix
+
+import "encoding/json"
+
+// EventSubCondition is implemented by the typed condition of each EventSub subscription type and version. The
+// condition is encoded as the condition object of the subscription.
+type EventSubCondition interface {
+	// EventSubType returns the subscription type the condition is for.
+	EventSubType() string
+
+	// EventSubVersion returns the version of the subscription type the condition is for.
+	EventSubVersion() string
+}
+
+// Subscription types with a typed EventSubCondition, see: https://dev.twitch.tv/docs/eventsub/eventsub-subscription-types
+const (
+	// EventSubTypeChannelUpdate is the channel.update subscription type
+	EventSubTypeChannelUpdate = "channel.update"
+
+	// EventSubTypeChannelFollow is the channel.follow subscription type
+	EventSubTypeChannelFollow = "channel.follow"
+
+	// EventSubTypeChannelSubscribe is the channel.subscribe subscription type
+	EventSubTypeChannelSubscribe = "channel.subscribe"
+
+	// EventSubTypeChannelSubscriptionGift is the channel.subscription.gift subscription type
+	EventSubTypeChannelSubscriptionGift = "channel.subscription.gift"
+
+	// EventSubTypeChannelSubscriptionMessage is the channel.subscription.message subscription type
+	EventSubTypeChannelSubscriptionMessage = "channel.subscription.message"
+
+	// EventSubTypeChannelCheer is the channel.cheer subscription type
+	EventSubTypeChannelCheer = "channel.cheer"
+
+	// EventSubTypeChannelRaid is the channel.raid subscription type
+	EventSubTypeChannelRaid = "channel.raid"
+
+	// EventSubTypeChannelBan is the channel.ban subscription type
+	EventSubTypeChannelBan = "channel.ban"
+
+	// EventSubTypeChannelUnban is the channel.unban subscription type
+	EventSubTypeChannelUnban = "channel.unban"
+
+	// EventSubTypeStreamOnline is the stream.online subscription type
+	EventSubTypeStreamOnline = "stream.online"
+
+	// EventSubTypeStreamOffline is the stream.offline subscription type
+	EventSubTypeStreamOffline = "stream.offline"
+
+	// EventSubTypeChannelPointsCustomRewardRedemptionAdd is the channel.channel_points_custom_reward_redemption.add subscription type
+	EventSubTypeChannelPointsCustomRewardRedemptionAdd = "channel.channel_points_custom_reward_redemption.add"
+
+	// EventSubTypeChannelPointsCustomRewardRedemptionUpdate is the channel.channel_points_custom_reward_redemption.update subscription type
+	EventSubTypeChannelPointsCustomRewardRedemptionUpdate = "channel.channel_points_custom_reward_redemption.update"
+
+	// EventSubTypeChannelPollBegin is the channel.poll.begin subscription type
+	EventSubTypeChannelPollBegin = "channel.poll.begin"
+
+	// EventSubTypeChannelPollProgress is the channel.poll.progress subscription type
+	EventSubTypeChannelPollProgress = "channel.poll.progress"
+
+	// EventSubTypeChannelPollEnd is the channel.poll.end subscription type
+	EventSubTypeChannelPollEnd = "channel.poll.end"
+
+	// EventSubTypeChannelPredictionBegin is the channel.prediction.begin subscription type
+	EventSubTypeChannelPredictionBegin = "channel.prediction.begin"
+
+	// EventSubTypeChannelPredictionProgress is the channel.prediction.progress subscription type
+	EventSubTypeChannelPredictionProgress = "channel.prediction.progress"
+
+	// EventSubTypeChannelPredictionLock is the channel.prediction.lock subscription type
+	EventSubTypeChannelPredictionLock = "channel.prediction.lock"
+
+	// EventSubTypeChannelPredictionEnd is the channel.prediction.end subscription type
+	EventSubTypeChannelPredictionEnd = "channel.prediction.end"
+
+	// EventSubTypeChannelHypeTrainBegin is the channel.hype_train.begin subscription type
+	EventSubTypeChannelHypeTrainBegin = "channel.hype_train.begin"
+
+	// EventSubTypeChannelHypeTrainProgress is the channel.hype_train.progress subscription type
+	EventSubTypeChannelHypeTrainProgress = "channel.hype_train.progress"
+
+	// EventSubTypeChannelHypeTrainEnd is the channel.hype_train.end subscription type
+	EventSubTypeChannelHypeTrainEnd = "channel.hype_train.end"
+
+	// EventSubTypeChannelChatMessage is the channel.chat.message subscription type
+	EventSubTypeChannelChatMessage = "channel.chat.message"
+
+	// EventSubTypeChannelChatSettingsUpdate is the channel.chat_settings.update subscription type
+	EventSubTypeChannelChatSettingsUpdate = "channel.chat_settings.update"
+)
+
+// ChannelUpdateCondition is the condition for channel.update version 2, sent when a broadcaster updates their channel properties e.g., category, title, content classification labels, broadcast, or language.
+type ChannelUpdateCondition struct {
+	// BroadcasterUserID is the broadcaster user ID for the channel you want to get updates for.
+	BroadcasterUserID string `json:"broadcaster_user_id"`
+}
+
+// EventSubType implements EventSubCondition
+func (c *ChannelUpdateCondition) EventSubType() string {
+	return EventSubTypeChannelUpdate
+}
+
+// EventSubVersion implements EventSubCondition
+func (c *ChannelUpdateCondition) EventSubVersion() string {
+	return "2"
+}
+
+// ChannelFollowCondition is the condition for channel.follow version 2, sent when a specified channel receives a follow.
+type ChannelFollowCondition struct {
+	// BroadcasterUserID is the broadcaster user ID for the channel you want to get follow notifications for.
+	BroadcasterUserID string `json:"broadcaster_user_id"`
+
+	// ModeratorUserID is the ID of the moderator of the channel you want to get follow notifications for. If you have authorization from the broadcaster rather than a moderator, specify the broadcaster’s user ID here.
+	ModeratorUserID string `json:"moderator_user_id"`
+}
+
+// EventSubType implements EventSubCondition
+func (c *ChannelFollowCondition) EventSubType() string {
+	return EventSubTypeChannelFollow
+}
+
+// EventSubVersion implements EventSubCondition
+func (c *ChannelFollowCondition) EventSubVersion() string {
+	return "2"
+}
+
+// ChannelSubscribeCondition is the condition for channel.subscribe version 1, sent when a specified channel receives a subscriber. This does not include resubscribes.
+type ChannelSubscribeCondition struct {
+	// BroadcasterUserID is the broadcaster user ID for the channel you want to get subscribe notifications for.
+	BroadcasterUserID string `json:"broadcaster_user_id"`
+}
+
+// EventSubType implements EventSubCondition
+func (c *ChannelSubscribeCondition) EventSubType() string {
+	return EventSubTypeChannelSubscribe
+}
+
+// EventSubVersion implements EventSubCondition
+func (c *ChannelSubscribeCondition) EventSubVersion() string {
+	return "1"
+}
+
+// ChannelSubscriptionGiftCondition is the condition for channel.subscription.gift version 1, sent when a user gives one or more gifted subscriptions in a channel.
+type ChannelSubscriptionGiftCondition struct {
+	// BroadcasterUserID is the broadcaster user ID for the channel you want to get subscription gift notifications for.
+	BroadcasterUserID string `json:"broadcaster_user_id"`
+}
+
+// EventSubType implements EventSubCondition
+func (c *ChannelSubscriptionGiftCondition) EventSubType() string {
+	return EventSubTypeChannelSubscriptionGift
+}
+
+// EventSubVersion implements EventSubCondition
+func (c *ChannelSubscriptionGiftCondition) EventSubVersion() string {
+	return "1"
+}
+
+// ChannelSubscriptionMessageCondition is the condition for channel.subscription.message version 1, sent when a user sends a resubscription chat message in a specific channel.
+type ChannelSubscriptionMessageCondition struct {
+	// BroadcasterUserID is the broadcaster user ID for the channel you want to get resubscription chat message notifications for.
+	BroadcasterUserID string `json:"broadcaster_user_id"`
+}
+
+// EventSubType implements EventSubCondition
+func (c *ChannelSubscriptionMessageCondition) EventSubType() string {
+	return EventSubTypeChannelSubscriptionMessage
+}
+
+// EventSubVersion implements EventSubCondition
+func (c *ChannelSubscriptionMessageCondition) EventSubVersion() string {
+	return "1"
+}
+
+// ChannelCheerCondition is the condition for channel.cheer version 1, sent when a user cheers on the specified channel.
+type ChannelCheerCondition struct {
+	// BroadcasterUserID is the broadcaster user ID for the channel you want to get cheer notifications for.
+	BroadcasterUserID string `json:"broadcaster_user_id"`
+}
+
+// EventSubType implements EventSubCondition
+func (c *ChannelCheerCondition) EventSubType() string {
+	return EventSubTypeChannelCheer
+}
+
+// EventSubVersion implements EventSubCondition
+func (c *ChannelCheerCondition) EventSubVersion() string {
+	return "1"
+}
+
+// ChannelRaidCondition is the condition for channel.raid version 1, sent when a broadcaster raids another broadcaster’s channel. Specify exactly one of the two user IDs.
+type ChannelRaidCondition struct {
+	// FromBroadcasterUserID (optional) is the broadcaster user ID that created the channel raid you want to get notifications for.
+	FromBroadcasterUserID string `json:"from_broadcaster_user_id,omitempty"`
+
+	// ToBroadcasterUserID (optional) is the broadcaster user ID that received the channel raid you want to get notifications for.
+	ToBroadcasterUserID string `json:"to_broadcaster_user_id,omitempty"`
+}
+
+// EventSubType implements EventSubCondition
+func (c *ChannelRaidCondition) EventSubType() string {
+	return EventSubTypeChannelRaid
+}
+
+// EventSubVersion implements EventSubCondition
+func (c *ChannelRaidCondition) EventSubVersion() string {
+	return "1"
+}
+
+// ChannelBanCondition is the condition for channel.ban version 1, sent when a viewer is banned from the specified channel.
+type ChannelBanCondition struct {
+	// BroadcasterUserID is the broadcaster user ID for the channel you want to get ban notifications for.
+	BroadcasterUserID string `json:"broadcaster_user_id"`
+}
+
+// EventSubType implements EventSubCondition
+func (c *ChannelBanCondition) EventSubType() string {
+	return EventSubTypeChannelBan
+}
+
+// EventSubVersion implements EventSubCondition
+func (c *ChannelBanCondition) EventSubVersion() string {
+	return "1"
+}
+
+// ChannelUnbanCondition is the condition for channel.unban version 1, sent when a viewer is unbanned from the specified channel.
+type ChannelUnbanCondition struct {
+	// BroadcasterUserID is the broadcaster user ID for the channel you want to get unban notifications for.
+	BroadcasterUserID string `json:"broadcaster_user_id"`
+}
+
+// EventSubType implements EventSubCondition
+func (c *ChannelUnbanCondition) EventSubType() string {
+	return EventSubTypeChannelUnban
+}
+
+// EventSubVersion implements EventSubCondition
+func (c *ChannelUnbanCondition) EventSubVersion() string {
+	return "1"
+}
+
+// StreamOnlineCondition is the condition for stream.online version 1, sent when the specified broadcaster starts a stream.
+type StreamOnlineCondition struct {
+	// BroadcasterUserID is the broadcaster user ID you want to get stream online notifications for.
+	BroadcasterUserID string `json:"broadcaster_user_id"`
+}
+
+// EventSubType implements EventSubCondition
+func (c *StreamOnlineCondition) EventSubType() string {
+	return EventSubTypeStreamOnline
+}
+
+// EventSubVersion implements EventSubCondition
+func (c *StreamOnlineCondition) EventSubVersion() string {
+	return "1"
+}
+
+// StreamOfflineCondition is the condition for stream.offline version 1, sent when the specified broadcaster stops a stream.
+type StreamOfflineCondition struct {
+	// BroadcasterUserID is the broadcaster user ID you want to get stream offline notifications for.
+	BroadcasterUserID string `json:"broadcaster_user_id"`
+}
+
+// EventSubType implements EventSubCondition
+func (c *StreamOfflineCondition) EventSubType() string {
+	return EventSubTypeStreamOffline
+}
+
+// EventSubVersion implements EventSubCondition
+func (c *StreamOfflineCondition) EventSubVersion() string {
+	return "1"
+}
+
+// ChannelPointsCustomRewardRedemptionAddCondition is the condition for channel.channel_points_custom_reward_redemption.add version 1, sent when a viewer has redeemed a custom channel points reward on the specified channel.
+type ChannelPointsCustomRewardRedemptionAddCondition struct {
+	// BroadcasterUserID is the broadcaster user ID for the channel you want to receive channel points custom reward redemption add notifications for.
+	BroadcasterUserID string `json:"broadcaster_user_id"`
+
+	// RewardID (optional) specifies a reward ID to only receive notifications for a specific reward.
+	RewardID string `json:"reward_id,omitempty"`
+}
+
+// EventSubType implements EventSubCondition
+func (c *ChannelPointsCustomRewardRedemptionAddCondition) EventSubType() string {
+	return EventSubTypeChannelPointsCustomRewardRedemptionAdd
+}
+
+// EventSubVersion implements EventSubCondition
+func (c *ChannelPointsCustomRewardRedemptionAddCondition) EventSubVersion() string {
+	return "1"
+}
+
+// ChannelPointsCustomRewardRedemptionUpdateCondition is the condition for channel.channel_points_custom_reward_redemption.update version 1, sent when a redemption of a channel points custom reward has been updated for the specified channel.
+type ChannelPointsCustomRewardRedemptionUpdateCondition struct {
+	// BroadcasterUserID is the broadcaster user ID for the channel you want to receive channel points custom reward redemption update notifications for.
+	BroadcasterUserID string `json:"broadcaster_user_id"`
+
+	// RewardID (optional) specifies a reward ID to only receive notifications for a specific reward.
+	RewardID string `json:"reward_id,omitempty"`
+}
+
+// EventSubType implements EventSubCondition
+func (c *ChannelPointsCustomRewardRedemptionUpdateCondition) EventSubType() string {
+	return EventSubTypeChannelPointsCustomRewardRedemptionUpdate
+}
+
+// EventSubVersion implements EventSubCondition
+func (c *ChannelPointsCustomRewardRedemptionUpdateCondition) EventSubVersion() string {
+	return "1"
+}
+
+// ChannelPollBeginCondition is the condition for channel.poll.begin version 1, sent when a poll begins on the specified channel.
+type ChannelPollBeginCondition struct {
+	// BroadcasterUserID is the broadcaster user ID of the channel for which “poll begin” notifications will be received.
+	BroadcasterUserID string `json:"broadcaster_user_id"`
+}
+
+// EventSubType implements EventSubCondition
+func (c *ChannelPollBeginCondition) EventSubType() string {
+	return EventSubTypeChannelPollBegin
+}
+
+// EventSubVersion implements EventSubCondition
+func (c *ChannelPollBeginCondition) EventSubVersion() string {
+	return "1"
+}
+
+// ChannelPollProgressCondition is the condition for channel.poll.progress version 1, sent when users respond to a poll on the specified channel.
+type ChannelPollProgressCondition struct {
+	// BroadcasterUserID is the broadcaster user ID of the channel for which “poll progress” notifications will be received.
+	BroadcasterUserID string `json:"broadcaster_user_id"`
+}
+
+// EventSubType implements EventSubCondition
+func (c *ChannelPollProgressCondition) EventSubType() string {
+	return EventSubTypeChannelPollProgress
+}
+
+// EventSubVersion implements EventSubCondition
+func (c *ChannelPollProgressCondition) EventSubVersion() string {
+	return "1"
+}
+
+// ChannelPollEndCondition is the condition for channel.poll.end version 1, sent when a poll ends on the specified channel.
+type ChannelPollEndCondition struct {
+	// BroadcasterUserID is the broadcaster user ID of the channel for which “poll end” notifications will be received.
+	BroadcasterUserID string `json:"broadcaster_user_id"`
+}
+
+// EventSubType implements EventSubCondition
+func (c *ChannelPollEndCondition) EventSubType() string {
+	return EventSubTypeChannelPollEnd
+}
+
+// EventSubVersion implements EventSubCondition
+func (c *ChannelPollEndCondition) EventSubVersion() string {
+	return "1"
+}
+
+// ChannelPredictionBeginCondition is the condition for channel.prediction.begin version 1, sent when a Prediction begins on the specified channel.
+type ChannelPredictionBeginCondition struct {
+	// BroadcasterUserID is the broadcaster user ID of the channel for which “prediction begin” notifications will be received.
+	BroadcasterUserID string `json:"broadcaster_user_id"`
+}
+
+// EventSubType implements EventSubCondition
+func (c *ChannelPredictionBeginCondition) EventSubType() string {
+	return EventSubTypeChannelPredictionBegin
+}
+
+// EventSubVersion implements EventSubCondition
+func (c *ChannelPredictionBeginCondition) EventSubVersion() string {
+	return "1"
+}
+
+// ChannelPredictionProgressCondition is the condition for channel.prediction.progress version 1, sent when users participate in a Prediction on the specified channel.
+type ChannelPredictionProgressCondition struct {
+	// BroadcasterUserID is the broadcaster user ID of the channel for which “prediction progress” notifications will be received.
+	BroadcasterUserID string `json:"broadcaster_user_id"`
+}
+
+// EventSubType implements EventSubCondition
+func (c *ChannelPredictionProgressCondition) EventSubType() string {
+	return EventSubTypeChannelPredictionProgress
+}
+
+// EventSubVersion implements EventSubCondition
+func (c *ChannelPredictionProgressCondition) EventSubVersion() string {
+	return "1"
+}
+
+// ChannelPredictionLockCondition is the condition for channel.prediction.lock version 1, sent when a Prediction is locked on the specified channel.
+type ChannelPredictionLockCondition struct {
+	// BroadcasterUserID is the broadcaster user ID of the channel for which “prediction lock” notifications will be received.
+	BroadcasterUserID string `json:"broadcaster_user_id"`
+}
+
+// EventSubType implements EventSubCondition
+func (c *ChannelPredictionLockCondition) EventSubType() string {
+	return EventSubTypeChannelPredictionLock
+}
+
+// EventSubVersion implements EventSubCondition
+func (c *ChannelPredictionLockCondition) EventSubVersion() string {
+	return "1"
+}
+
+// ChannelPredictionEndCondition is the condition for channel.prediction.end version 1, sent when a Prediction ends on the specified channel.
+type ChannelPredictionEndCondition struct {
+	// BroadcasterUserID is the broadcaster user ID of the channel for which “prediction end” notifications will be received.
+	BroadcasterUserID string `json:"broadcaster_user_id"`
+}
+
+// EventSubType implements EventSubCondition
+func (c *ChannelPredictionEndCondition) EventSubType() string {
+	return EventSubTypeChannelPredictionEnd
+}
+
+// EventSubVersion implements EventSubCondition
+func (c *ChannelPredictionEndCondition) EventSubVersion() string {
+	return "1"
+}
+
+// ChannelHypeTrainBeginCondition is the condition for channel.hype_train.begin version 1, sent when a Hype Train begins on the specified channel.
+type ChannelHypeTrainBeginCondition struct {
+	// BroadcasterUserID is the ID of the broadcaster that you want to get Hype Train begin notifications for.
+	BroadcasterUserID string `json:"broadcaster_user_id"`
+}
+
+// EventSubType implements EventSubCondition
+func (c *ChannelHypeTrainBeginCondition) EventSubType() string {
+	return EventSubTypeChannelHypeTrainBegin
+}
+
+// EventSubVersion implements EventSubCondition
+func (c *ChannelHypeTrainBeginCondition) EventSubVersion() string {
+	return "1"
+}
+
+// ChannelHypeTrainProgressCondition is the condition for channel.hype_train.progress version 1, sent when a Hype Train makes progress on the specified channel.
+type ChannelHypeTrainProgressCondition struct {
+	// BroadcasterUserID is the ID of the broadcaster that you want to get Hype Train progress notifications for.
+	BroadcasterUserID string `json:"broadcaster_user_id"`
+}
+
+// EventSubType implements EventSubCondition
+func (c *ChannelHypeTrainProgressCondition) EventSubType() string {
+	return EventSubTypeChannelHypeTrainProgress
+}
+
+// EventSubVersion implements EventSubCondition
+func (c *ChannelHypeTrainProgressCondition) EventSubVersion() string {
+	return "1"
+}
+
+// ChannelHypeTrainEndCondition is the condition for channel.hype_train.end version 1, sent when a Hype Train ends on the specified channel.
+type ChannelHypeTrainEndCondition struct {
+	// BroadcasterUserID is the ID of the broadcaster that you want to get Hype Train end notifications for.
+	BroadcasterUserID string `json:"broadcaster_user_id"`
+}
+
+// EventSubType implements EventSubCondition
+func (c *ChannelHypeTrainEndCondition) EventSubType() string {
+	return EventSubTypeChannelHypeTrainEnd
+}
+
+// EventSubVersion implements EventSubCondition
+func (c *ChannelHypeTrainEndCondition) EventSubVersion() string {
+	return "1"
+}
+
+// ChannelChatMessageCondition is the condition for channel.chat.message version 1, sent when any user sends a message to a channel’s chat room.
+type ChannelChatMessageCondition struct {
+	// BroadcasterUserID is the User ID of the channel to receive chat message events for.
+	BroadcasterUserID string `json:"broadcaster_user_id"`
+
+	// UserID is the User ID to read chat as.
+	UserID string `json:"user_id"`
+}
+
+// EventSubType implements EventSubCondition
+func (c *ChannelChatMessageCondition) EventSubType() string {
+	return EventSubTypeChannelChatMessage
+}
+
+// EventSubVersion implements EventSubCondition
+func (c *ChannelChatMessageCondition) EventSubVersion() string {
+	return "1"
+}
+
+// ChannelChatSettingsUpdateCondition is the condition for channel.chat_settings.update version 1, sent when a broadcaster’s chat settings are updated.
+type ChannelChatSettingsUpdateCondition struct {
+	// BroadcasterUserID is the User ID of the channel to receive chat settings update events for.
+	BroadcasterUserID string `json:"broadcaster_user_id"`
+
+	// UserID is the User ID to read chat as.
+	UserID string `json:"user_id"`
+}
+
+// EventSubType implements EventSubCondition
+func (c *ChannelChatSettingsUpdateCondition) EventSubType() string {
+	return EventSubTypeChannelChatSettingsUpdate
+}
+
+// EventSubVersion implements EventSubCondition
+func (c *ChannelChatSettingsUpdateCondition) EventSubVersion() string {
+	return "1"
+}
+
+// RawEventSubCondition is a condition for any subscription type and version, including ones that have no typed condition.
+type RawEventSubCondition struct {
+	// Type is the subscription type.
+	Type string
+
+	// Version is the version of the subscription type.
+	Version string
+
+	// Condition contains the parameter values of the condition.
+	Condition map[string]string
+}
+
+// EventSubType implements EventSubCondition
+func (c *RawEventSubCondition) EventSubType() string {
+	return c.Type
+}
+
+// EventSubVersion implements EventSubCondition
+func (c *RawEventSubCondition) EventSubVersion() string {
+	return c.Version
+}
+
+// MarshalJSON implements json.Marshaler, encoding only the condition values.
+func (c *RawEventSubCondition) MarshalJSON() ([]byte, error) {
+	if c.Condition == nil {
+		return []byte("{}"), nil
+	}
+	return json.Marshal(c.Condition)
+}
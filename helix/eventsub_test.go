@@ -0,0 +1,185 @@
+package helix
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/aidenwallis/go-twitch-client/internal/testutils"
+	"github.com/aidenwallis/go-twitch-client/internal/testutils/assert"
+)
+
+func TestCreateEventSubSubscription(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("webhook", func(t *testing.T) {
+		in := &CreateEventSubSubscriptionRequest{
+			RequestOptions: requestOptions(),
+			Condition:      &ChannelFollowCondition{BroadcasterUserID: "1", ModeratorUserID: "2"},
+			Transport:      WebhookTransport("https://example.com/webhook", "supersecret"),
+		}
+
+		c := testClient(func(req *http.Request) *testutils.Response {
+			assertToken(t, req)
+			assert.Equal(t, http.MethodPost, req.Method)
+			assert.Equal(t, eventSubSubscriptionsPath, req.URL.String())
+			assert.Equal(
+				t,
+				`{"type":"channel.follow","version":"2","condition":{"broadcaster_user_id":"1","moderator_user_id":"2"},"transport":{"method":"webhook","callback":"https://example.com/webhook","secret":"supersecret"}}`,
+				testutils.DecodeRawBody(t, req),
+			)
+			return testutils.JSONResponse(t, http.StatusAccepted, &CreateEventSubSubscriptionResponse{
+				Data: []*EventSubSubscription{
+					{
+						ID:        "abc",
+						Status:    EventSubStatusWebhookCallbackVerificationPending,
+						Type:      EventSubTypeChannelFollow,
+						Version:   "2",
+						Condition: map[string]string{"broadcaster_user_id": "1", "moderator_user_id": "2"},
+						CreatedAt: time.Now().UTC(),
+						Transport: EventSubTransport{Method: EventSubTransportWebhook, Callback: "https://example.com/webhook"},
+						Cost:      0,
+					},
+				},
+				Total:        1,
+				TotalCost:    0,
+				MaxTotalCost: 10000,
+			})
+		})
+
+		resp, err := c.CreateEventSubSubscription(ctx, in)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, len(resp.Data))
+		assert.Equal(t, "2", resp.Data[0].Condition["moderator_user_id"])
+		assert.Equal(t, 10000, resp.MaxTotalCost)
+	})
+
+	t.Run("raw condition over websocket", func(t *testing.T) {
+		in := &CreateEventSubSubscriptionRequest{
+			RequestOptions: requestOptions(),
+			Condition: &RawEventSubCondition{
+				Type:      "user.update",
+				Version:   "1",
+				Condition: map[string]string{"user_id": "1"},
+			},
+			Transport: WebSocketTransport("session"),
+		}
+
+		c := testClient(func(req *http.Request) *testutils.Response {
+			assert.Equal(
+				t,
+				`{"type":"user.update","version":"1","condition":{"user_id":"1"},"transport":{"method":"websocket","session_id":"session"}}`,
+				testutils.DecodeRawBody(t, req),
+			)
+			return testutils.JSONResponse(t, http.StatusAccepted, &CreateEventSubSubscriptionResponse{})
+		})
+
+		_, err := c.CreateEventSubSubscription(ctx, in)
+		assert.NoError(t, err)
+	})
+
+	t.Run("websocket requires user token", func(t *testing.T) {
+		c := testClient(func(req *http.Request) *testutils.Response {
+			t.Error("request should not be sent")
+			return testutils.EmptyResponse(http.StatusAccepted)
+		})
+
+		_, err := c.CreateEventSubSubscription(ctx, &CreateEventSubSubscriptionRequest{
+			Condition: &StreamOnlineCondition{BroadcasterUserID: "1"},
+			Transport: WebSocketTransport("session"),
+		})
+
+		var tokenErr *TokenTypeError
+		assert.Equal(t, true, errors.As(err, &tokenErr))
+		assert.Equal(t, TokenTypeUser, tokenErr.Accepted)
+	})
+
+	t.Run("conduit uses app token", func(t *testing.T) {
+		c := NewClient(&ClientOptions{
+			ClientID: fakeClientID,
+			AccessTokenLoader: func(ctx context.Context) (string, error) {
+				return fakeToken, nil
+			},
+			Transport: testutils.Middleware(func(req *http.Request) *testutils.Response {
+				assertToken(t, req)
+				assert.Equal(
+					t,
+					`{"type":"channel.chat.message","version":"1","condition":{"broadcaster_user_id":"1","user_id":"2"},"transport":{"method":"conduit","conduit_id":"conduit"}}`,
+					testutils.DecodeRawBody(t, req),
+				)
+				return testutils.JSONResponse(t, http.StatusAccepted, &CreateEventSubSubscriptionResponse{})
+			}),
+		})
+
+		_, err := c.CreateEventSubSubscription(ctx, &CreateEventSubSubscriptionRequest{
+			Condition: &ChannelChatMessageCondition{BroadcasterUserID: "1", UserID: "2"},
+			Transport: ConduitTransport("conduit"),
+		})
+		assert.NoError(t, err)
+	})
+
+	t.Run("missing condition", func(t *testing.T) {
+		c := testClient(nil)
+		_, err := c.CreateEventSubSubscription(ctx, &CreateEventSubSubscriptionRequest{Transport: ConduitTransport("conduit")})
+		assert.ErrorIs(t, err, ErrMissingEventSubCondition)
+	})
+}
+
+func TestDeleteEventSubSubscription(t *testing.T) {
+	ctx := context.Background()
+	in := &DeleteEventSubSubscriptionRequest{
+		RequestOptions: requestOptions(),
+		ID:             "abc",
+	}
+
+	c := testClient(func(req *http.Request) *testutils.Response {
+		assertToken(t, req)
+		assert.Equal(t, http.MethodDelete, req.Method)
+		assert.Equal(t, eventSubSubscriptionsPath+"?id=abc", req.URL.String())
+		return testutils.EmptyResponse(http.StatusNoContent)
+	})
+
+	assert.NoError(t, c.DeleteEventSubSubscription(ctx, in))
+}
+
+func TestGetEventSubSubscriptions(t *testing.T) {
+	ctx := context.Background()
+	in := &GetEventSubSubscriptionsRequest{
+		RequestOptions: requestOptions(),
+		Status:         EventSubStatusEnabled,
+		Type:           EventSubTypeStreamOnline,
+		UserID:         "1",
+		After:          "cursor",
+	}
+
+	c := testClient(func(req *http.Request) *testutils.Response {
+		assertToken(t, req)
+		assert.Equal(t, http.MethodGet, req.Method)
+		assert.Equal(t, eventSubSubscriptionsPath+"?after=cursor&status=enabled&type=stream.online&user_id=1", req.URL.String())
+		return testutils.JSONResponse(t, http.StatusOK, &GetEventSubSubscriptionsResponse{
+			Data: []*EventSubSubscription{
+				{
+					ID:        "abc",
+					Status:    EventSubStatusEnabled,
+					Type:      EventSubTypeStreamOnline,
+					Version:   "1",
+					Condition: map[string]string{"broadcaster_user_id": "1"},
+					Transport: EventSubTransport{Method: EventSubTransportWebSocket, SessionID: "session"},
+					Cost:      1,
+				},
+			},
+			Total:        1,
+			TotalCost:    1,
+			MaxTotalCost: 10,
+			Pagination:   Pagination{Cursor: "next"},
+		})
+	})
+
+	resp, err := c.GetEventSubSubscriptions(ctx, in)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(resp.Data))
+	assert.Equal(t, 1, resp.TotalCost)
+	assert.Equal(t, "next", resp.Pagination.Cursor)
+}
@@ -0,0 +1,137 @@
+package helix
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// maxIdentityCacheDuration is the longest a validated token identity is cached for. Twitch expects
+// apps to revalidate tokens hourly anyway.
+const maxIdentityCacheDuration = time.Hour
+
+// ActingUserMismatchError is returned when a request supplies an acting user ID (such as a ModeratorID)
+// that doesn't match the user the OAuth token belongs to.
+type ActingUserMismatchError struct {
+	// Supplied is the user ID that was set on the request.
+	Supplied string
+
+	// TokenUserID is the ID of the user the OAuth token belongs to.
+	TokenUserID string
+}
+
+// Error returns a stringified error
+func (e *ActingUserMismatchError) Error() string {
+	return fmt.Sprintf("helix: acting user ID %q does not match token user ID %q", e.Supplied, e.TokenUserID)
+}
+
+// identityCache caches the user ID that user access tokens belong to.
+type identityCache struct {
+	mu      sync.Mutex
+	entries map[[sha256.Size]byte]*identityEntry
+	now     func() time.Time
+}
+
+type identityEntry struct {
+	userID    string
+	expiresAt time.Time
+}
+
+func newIdentityCache() *identityCache {
+	return &identityCache{
+		entries: map[[sha256.Size]byte]*identityEntry{},
+		now:     time.Now,
+	}
+}
+
+// get returns the cached user ID for a token, tokens are stored hashed so that they aren't kept in memory.
+func (c *identityCache) get(token string) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := sha256.Sum256([]byte(token))
+	entry, ok := c.entries[key]
+	if !ok {
+		return "", false
+	}
+	if !c.now().Before(entry.expiresAt) {
+		delete(c.entries, key)
+		return "", false
+	}
+	return entry.userID, true
+}
+
+func (c *identityCache) set(token, userID string, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	for key, entry := range c.entries {
+		if !now.Before(entry.expiresAt) {
+			delete(c.entries, key)
+		}
+	}
+
+	if ttl <= 0 || ttl > maxIdentityCacheDuration {
+		ttl = maxIdentityCacheDuration
+	}
+	c.entries[sha256.Sum256([]byte(token))] = &identityEntry{userID: userID, expiresAt: now.Add(ttl)}
+}
+
+// tokenUserID returns the ID of the user the token belongs to, validating the token when it isn't cached.
+// App access tokens return an empty user ID.
+func (c *helixClient) tokenUserID(ctx context.Context, token string) (string, error) {
+	if userID, ok := c.identities.get(token); ok {
+		return userID, nil
+	}
+
+	resp, err := c.ValidateToken(ctx, &ValidateTokenRequest{Token: token})
+	if err != nil {
+		return "", err
+	}
+
+	c.identities.set(token, resp.UserID, time.Duration(resp.ExpiresIn)*time.Second)
+	return resp.UserID, nil
+}
+
+// actingUserID returns the user ID to send for fields that must match the user behind the OAuth token.
+//
+// When ResolveActingUser is enabled and the request carries a user token, an empty supplied ID is filled
+// with the token's user ID, and a supplied ID that disagrees with the token is rejected.
+//
+// Clients returned by ForUser already know their user, so their token doesn't need validating.
+func (c *helixClient) actingUserID(ctx context.Context, options *RequestOptions, supplied string) (string, error) {
+	if !c.resolveActingUser {
+		return supplied, nil
+	}
+
+	var userID string
+	if c.scope != nil && c.scope.userID != "" && (options == nil || options.Token == "") {
+		userID = c.scope.userID
+	} else {
+		token, err := c.requestToken(ctx, options)
+		if err != nil {
+			return "", err
+		}
+		if token == "" {
+			return supplied, nil
+		}
+
+		if userID, err = c.tokenUserID(ctx, token); err != nil {
+			return "", err
+		}
+	}
+
+	switch {
+	case userID == "":
+		// app access token, there is no user to act as
+		return supplied, nil
+	case supplied == "":
+		return userID, nil
+	case supplied != userID:
+		return "", &ActingUserMismatchError{Supplied: supplied, TokenUserID: userID}
+	}
+	return supplied, nil
+}
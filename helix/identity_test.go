@@ -0,0 +1,145 @@
+package helix
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/aidenwallis/go-twitch-client/internal/testutils"
+	"github.com/aidenwallis/go-twitch-client/internal/testutils/assert"
+)
+
+func actingUserClient(t *testing.T, userID string, validations *int, it testutils.RoundTripInterceptor) Client {
+	return NewClient(&ClientOptions{
+		ClientID:          fakeClientID,
+		ResolveActingUser: true,
+		Transport: testutils.Middleware(func(req *http.Request) *testutils.Response {
+			if req.URL.String() == validateTokenPath {
+				*validations++
+				return testutils.JSONResponse(t, http.StatusOK, &ValidateTokenResponse{
+					ClientID:  fakeClientID,
+					UserID:    userID,
+					ExpiresIn: 3600,
+				})
+			}
+			return it(req)
+		}),
+	})
+}
+
+func TestResolveActingUser(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("fills empty ids", func(t *testing.T) {
+		validations := 0
+		c := actingUserClient(t, "2", &validations, func(req *http.Request) *testutils.Response {
+			assertToken(t, req)
+			assert.Equal(t, "2", req.URL.Query().Get("moderator_id"))
+			return testutils.JSONResponse(t, http.StatusOK, &GetChatSettingsResponse{})
+		})
+
+		_, err := c.GetChatSettings(ctx, &GetChatSettingsRequest{RequestOptions: requestOptions(), BroadcasterID: "1"})
+		assert.NoError(t, err)
+
+		_, err = c.UpdateChatSettings(ctx, &UpdateChatSettingsRequest{RequestOptions: requestOptions(), BroadcasterID: "1"})
+		assert.NoError(t, err)
+
+		// validation is cached between requests
+		assert.Equal(t, 1, validations)
+	})
+
+	t.Run("matching id", func(t *testing.T) {
+		validations := 0
+		c := actingUserClient(t, "2", &validations, func(req *http.Request) *testutils.Response {
+			assert.Equal(t, "2", req.URL.Query().Get("user_id"))
+			return testutils.EmptyResponse(http.StatusNoContent)
+		})
+
+		assert.NoError(t, c.UpdateUserChatColor(ctx, &UpdateUserChatColorRequest{
+			RequestOptions: requestOptions(),
+			UserID:         "2",
+			Color:          "blue",
+		}))
+	})
+
+	t.Run("mismatched id", func(t *testing.T) {
+		validations := 0
+		c := actingUserClient(t, "2", &validations, func(req *http.Request) *testutils.Response {
+			t.Error("request should not be sent")
+			return testutils.EmptyResponse(http.StatusNoContent)
+		})
+
+		err := c.SendChatAnnouncement(ctx, &SendChatAnnouncementRequest{
+			RequestOptions: requestOptions(),
+			BroadcasterID:  "1",
+			ModeratorID:    "3",
+			Message:        "message",
+		})
+
+		var mismatch *ActingUserMismatchError
+		assert.Equal(t, true, errors.As(err, &mismatch))
+		assert.Equal(t, "3", mismatch.Supplied)
+		assert.Equal(t, "2", mismatch.TokenUserID)
+		assert.Equal(t, `helix: acting user ID "3" does not match token user ID "2"`, err.Error())
+	})
+
+	t.Run("app token", func(t *testing.T) {
+		validations := 0
+		c := actingUserClient(t, "", &validations, func(req *http.Request) *testutils.Response {
+			assert.Equal(t, "", req.URL.Query().Get("moderator_id"))
+			return testutils.JSONResponse(t, http.StatusOK, &GetChatSettingsResponse{})
+		})
+
+		_, err := c.GetChatSettings(ctx, &GetChatSettingsRequest{RequestOptions: requestOptions(), BroadcasterID: "1"})
+		assert.NoError(t, err)
+	})
+
+	t.Run("validation error", func(t *testing.T) {
+		c := NewClient(&ClientOptions{
+			ResolveActingUser: true,
+			Transport: testutils.Middleware(func(req *http.Request) *testutils.Response {
+				assert.Equal(t, validateTokenPath, req.URL.String())
+				return testutils.JSONResponse(t, http.StatusUnauthorized, map[string]interface{}{"status": 401, "message": "invalid access token"})
+			}),
+		})
+
+		_, err := c.GetUserBlocks(ctx, &GetUserBlocksRequest{RequestOptions: requestOptions()})
+		assert.Equal(t, "[401] invalid access token", err.Error())
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		c := testClient(func(req *http.Request) *testutils.Response {
+			assert.Equal(t, "3", req.URL.Query().Get("moderator_id"))
+			return testutils.JSONResponse(t, http.StatusOK, &GetChatSettingsResponse{})
+		})
+
+		_, err := c.GetChatSettings(ctx, &GetChatSettingsRequest{RequestOptions: requestOptions(), BroadcasterID: "1", ModeratorID: "3"})
+		assert.NoError(t, err)
+	})
+}
+
+func TestIdentityCache(t *testing.T) {
+	now := time.Now()
+	c := newIdentityCache()
+	c.now = func() time.Time { return now }
+
+	c.set("a", "1", time.Minute)
+	c.set("b", "2", 0)
+
+	userID, ok := c.get("a")
+	assert.Equal(t, true, ok)
+	assert.Equal(t, "1", userID)
+
+	now = now.Add(time.Minute)
+	_, ok = c.get("a")
+	assert.Equal(t, false, ok)
+
+	// ttl is capped
+	_, ok = c.get("b")
+	assert.Equal(t, true, ok)
+	now = now.Add(maxIdentityCacheDuration)
+	_, ok = c.get("b")
+	assert.Equal(t, false, ok)
+}
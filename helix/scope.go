@@ -0,0 +1,55 @@
+package helix
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrNoUserTokenLoader is returned by clients created through ForUser when no UserTokenLoader was configured.
+var ErrNoUserTokenLoader = errors.New("helix: ForUser requires a UserTokenLoader")
+
+// clientScope is the identity a client view returned by ForToken or ForUser is bound to
+type clientScope struct {
+	token  string
+	userID string
+}
+
+// ForToken returns a view of the client whose requests default to the given OAuth token, instead of falling
+// back to the AccessTokenLoader. A token passed in a request's RequestOptions still takes precedence.
+func (c *helixClient) ForToken(token string) Client {
+	return c.withScope(&clientScope{token: token})
+}
+
+// ForUser returns a view of the client whose requests default to the given user's OAuth token, which is loaded
+// through the UserTokenLoader on every request. A token passed in a request's RequestOptions still takes precedence.
+func (c *helixClient) ForUser(userID string) Client {
+	return c.withScope(&clientScope{userID: userID})
+}
+
+// withScope copies the client, the copy shares the underlying HTTP client and caches.
+func (c *helixClient) withScope(scope *clientScope) Client {
+	scoped := *c
+	scoped.scope = scope
+	return &scoped
+}
+
+// requestToken returns the token bound to a request. The token in RequestOptions takes precedence over the token of
+// the client's scope. An empty token is returned when neither is set, leaving the caller to fall back to an app token.
+func (c *helixClient) requestToken(ctx context.Context, options *RequestOptions) (string, error) {
+	if options != nil && options.Token != "" {
+		return options.Token, nil
+	}
+
+	switch {
+	case c.scope == nil:
+		return "", nil
+	case c.scope.token != "":
+		return c.scope.token, nil
+	case c.scope.userID == "":
+		return "", nil
+	case c.userTokenLoader == nil:
+		return "", ErrNoUserTokenLoader
+	}
+
+	return c.userTokenLoader(ctx, c.scope.userID)
+}
@@ -0,0 +1,72 @@
+package helix
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/aidenwallis/go-twitch-client/internal/testutils"
+	"github.com/aidenwallis/go-twitch-client/internal/testutils/assert"
+)
+
+func TestForToken(t *testing.T) {
+	ctx := context.Background()
+	expectedToken := "scoped"
+
+	c := testClient(func(req *http.Request) *testutils.Response {
+		assert.Equal(t, "Bearer "+expectedToken, req.Header.Get("Authorization"))
+		return testutils.EmptyResponse(http.StatusNoContent)
+	})
+	scoped := c.ForToken("scoped")
+
+	// scoped token satisfies user token endpoints
+	assert.NoError(t, scoped.BlockUser(ctx, &BlockUserRequest{TargetUserID: "id"}))
+
+	// explicit request options win
+	expectedToken = fakeToken
+	assert.NoError(t, scoped.BlockUser(ctx, &BlockUserRequest{RequestOptions: requestOptions(), TargetUserID: "id"}))
+
+	// the parent client is unaffected
+	err := c.BlockUser(ctx, &BlockUserRequest{TargetUserID: "id"})
+	assert.Equal(t, "helix: endpoint requires a user access token, but none was provided in RequestOptions", err.Error())
+
+	// views share the http client and caches
+	assert.Equal(t, c.(*helixClient).Client, scoped.(*helixClient).Client)
+	assert.Equal(t, c.(*helixClient).identities, scoped.(*helixClient).identities)
+}
+
+func TestForUser(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("loads user token", func(t *testing.T) {
+		loaded := []string{}
+		c := NewClient(&ClientOptions{
+			ClientID:          fakeClientID,
+			ResolveActingUser: true,
+			UserTokenLoader: func(ctx context.Context, userID string) (string, error) {
+				loaded = append(loaded, userID)
+				return fakeToken, nil
+			},
+			Transport: testutils.Middleware(func(req *http.Request) *testutils.Response {
+				assert.Equal(t, false, req.URL.String() == validateTokenPath, "scoped user must not be validated")
+				assertToken(t, req)
+				assert.Equal(t, "2", req.URL.Query().Get("user_id"))
+				return testutils.EmptyResponse(http.StatusNoContent)
+			}),
+		})
+
+		assert.NoError(t, c.ForUser("2").UpdateUserChatColor(ctx, &UpdateUserChatColorRequest{Color: "blue"}))
+		assert.Equal(t, 1, len(loaded))
+		assert.Equal(t, "2", loaded[0])
+	})
+
+	t.Run("no loader", func(t *testing.T) {
+		c := testClient(func(req *http.Request) *testutils.Response {
+			t.Error("request should not be sent")
+			return testutils.EmptyResponse(http.StatusNoContent)
+		})
+
+		err := c.ForUser("2").BlockUser(ctx, &BlockUserRequest{TargetUserID: "id"})
+		assert.ErrorIs(t, err, ErrNoUserTokenLoader)
+	})
+}
@@ -98,7 +98,7 @@ func (c *helixClient) GetUsers(ctx context.Context, req *GetUsersRequest) (*GetU
 	values["login"] = req.Logins
 
 	return client.WithBody[GetUsersResponse](c.Request(&client.RequestConfig{
-		Headers: c.headers(req.RequestOptions),
+		Headers: c.headers(req.RequestOptions, TokenTypeAny),
 		Method:  http.MethodGet,
 		URL:     usersPath,
 		Query:   values,
@@ -184,7 +184,7 @@ func (c *helixClient) GetUserFollows(ctx context.Context, req *GetUserFollowsReq
 	}
 
 	return client.WithBody[GetUserFollowsResponse](c.Request(&client.RequestConfig{
-		Headers: c.headers(req.RequestOptions),
+		Headers: c.headers(req.RequestOptions, TokenTypeAny),
 		Method:  http.MethodGet,
 		URL:     userFollowsPath,
 		Query:   values,
@@ -198,6 +198,8 @@ type GetUserBlocksRequest struct {
 	*RequestOptions
 
 	// BroadcasterID is the User ID for a Twitch user. This must match your access tokens' user.
+	//
+	// Filled from the OAuth token when left empty, if ClientOptions.ResolveActingUser is enabled.
 	BroadcasterID string
 
 	// After (optional) is a Cursor for forward pagination: tells the server where to start fetching the next set of results, in a multi-page response.
@@ -240,8 +242,13 @@ type UserBlock struct {
 
 // GetUserBlocks implements https://dev.twitch.tv/docs/api/reference#get-user-block-list
 func (c *helixClient) GetUserBlocks(ctx context.Context, req *GetUserBlocksRequest) (*GetUserBlocksResponse, error) {
+	broadcasterID, err := c.actingUserID(ctx, req.RequestOptions, req.BroadcasterID)
+	if err != nil {
+		return nil, err
+	}
+
 	values := url.Values{}
-	values.Set("broadcaster_id", req.BroadcasterID)
+	values.Set("broadcaster_id", broadcasterID)
 	if req.After != "" {
 		values.Set("after", req.After)
 	}
@@ -250,7 +257,7 @@ func (c *helixClient) GetUserBlocks(ctx context.Context, req *GetUserBlocksReque
 	}
 
 	return client.WithBody[GetUserBlocksResponse](c.Request(&client.RequestConfig{
-		Headers: c.headers(req.RequestOptions),
+		Headers: c.headers(req.RequestOptions, TokenTypeUser),
 		Method:  http.MethodGet,
 		URL:     userBlocksPath,
 		Query:   values,
@@ -283,7 +290,7 @@ func (c *helixClient) BlockUser(ctx context.Context, req *BlockUserRequest) erro
 	}
 
 	return client.WithoutBody(c.Request(&client.RequestConfig{
-		Headers: c.headers(req.RequestOptions),
+		Headers: c.headers(req.RequestOptions, TokenTypeUser),
 		Method:  http.MethodPut,
 		URL:     userBlocksPath,
 		Query:   values,
@@ -304,7 +311,7 @@ func (c *helixClient) UnblockUser(ctx context.Context, req *UnblockUserRequest)
 	values.Set("target_user_id", req.TargetUserID)
 
 	return client.WithoutBody(c.Request(&client.RequestConfig{
-		Headers: c.headers(req.RequestOptions),
+		Headers: c.headers(req.RequestOptions, TokenTypeUser),
 		Method:  http.MethodDelete,
 		URL:     userBlocksPath,
 		Query:   values,
@@ -331,7 +338,7 @@ func (c *helixClient) UpdateUser(ctx context.Context, req *UpdateUserRequest) (*
 	values.Set("description", req.Description)
 
 	return client.WithBody[UpdateUserResponse](c.Request(&client.RequestConfig{
-		Headers: c.headers(req.RequestOptions),
+		Headers: c.headers(req.RequestOptions, TokenTypeUser),
 		Method:  http.MethodPut,
 		URL:     usersPath,
 		Query:   values,
@@ -374,7 +381,7 @@ func (c *helixClient) GetUserExtensions(ctx context.Context, req *GetUserExtensi
 	return client.WithBody[GetUserExtensionsResponse](c.Request(&client.RequestConfig{
 		Method:  http.MethodGet,
 		URL:     userExtensionsListPath,
-		Headers: c.headers(req.RequestOptions),
+		Headers: c.headers(req.RequestOptions, TokenTypeUser),
 	}).Do(ctx))
 }
 
@@ -436,7 +443,7 @@ func (c *helixClient) GetUserActiveExtensions(ctx context.Context, req *GetUserA
 	return client.WithBody[GetUserActiveExtensionsResponse](c.Request(&client.RequestConfig{
 		Method:  http.MethodGet,
 		URL:     userExtensionsPath,
-		Headers: c.headers(req.RequestOptions),
+		Headers: c.headers(req.RequestOptions, TokenTypeAny),
 		Query:   values,
 	}).Do(ctx))
 }
@@ -465,6 +472,6 @@ func (c *helixClient) UpdateUserExtensions(ctx context.Context, req *UpdateUserE
 	return client.WithBody[UpdateUserExtensionsResponse](c.Request(&client.RequestConfig{
 		Method:  http.MethodPut,
 		URL:     userExtensionsPath,
-		Headers: c.headers(req.RequestOptions),
+		Headers: c.headers(req.RequestOptions, TokenTypeUser),
 	}).BodyJSON(&updateUserExtensionsBody{Data: req.Body}).Do(ctx))
 }
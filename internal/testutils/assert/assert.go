@@ -1,6 +1,7 @@
 package assert
 
 import (
+	"errors"
 	"fmt"
 	"testing"
 )
@@ -18,3 +19,10 @@ func NoError(t *testing.T, err error, messages ...any) {
 		t.Error("received unexpected error", messages)
 	}
 }
+
+// ErrorIs asserts that the error matches the target error
+func ErrorIs(t *testing.T, err, target error, messages ...any) {
+	if !errors.Is(err, target) {
+		t.Error(fmt.Sprintf("expected error %v but received %v", target, err), messages)
+	}
+}
@@ -0,0 +1,282 @@
+// Package websocket is a minimal RFC 6455 WebSocket implementation, covering what the EventSub and chat transports
+// need: dialing ws:// and wss:// servers, upgrading server connections in tests, and reading and writing unfragmented
+// messages.
+package websocket
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+)
+
+// Message types, as defined by the frame opcodes
+const (
+	TextMessage   = 1
+	BinaryMessage = 2
+	CloseMessage  = 8
+	PingMessage   = 9
+	PongMessage   = 10
+)
+
+// Close codes used by this package
+const (
+	CloseNormalClosure  = 1000
+	CloseGoingAway      = 1001
+	CloseProtocolError  = 1002
+	CloseNoStatusRcvd   = 1005
+	CloseMessageTooBig  = 1009
+	continuationMessage = 0
+)
+
+// maxMessageSize is the largest message that is read, larger messages close the connection.
+const maxMessageSize = 16 << 20
+
+// ErrMessageTooBig is returned when a message larger than the maximum message size is received
+var ErrMessageTooBig = errors.New("websocket: message too big")
+
+// CloseError is returned by ReadMessage once the peer has closed the connection
+type CloseError struct {
+	// Code is the close status code sent by the peer.
+	Code int
+
+	// Reason is the close reason sent by the peer.
+	Reason string
+}
+
+// Error implements error
+func (e *CloseError) Error() string {
+	return fmt.Sprintf("websocket: closed with code %d: %s", e.Code, e.Reason)
+}
+
+// Conn is a WebSocket connection. ReadMessage must only be called from one goroutine at a time, writes may be
+// made concurrently.
+type Conn struct {
+	conn net.Conn
+	br   *bufio.Reader
+
+	// client connections mask outgoing frames, as required by RFC 6455
+	client bool
+
+	writeMu   sync.Mutex
+	closeOnce sync.Once
+	closeSent bool
+}
+
+func newConn(conn net.Conn, br *bufio.Reader, client bool) *Conn {
+	return &Conn{conn: conn, br: br, client: client}
+}
+
+// ReadMessage reads the next text or binary message. Pings are answered automatically, and pongs are ignored. Once
+// the peer closes the connection, a *CloseError is returned.
+func (c *Conn) ReadMessage() (messageType int, data []byte, err error) {
+	for {
+		fin, opcode, payload, err := c.readFrame()
+		if err != nil {
+			return 0, nil, err
+		}
+
+		switch opcode {
+		case PingMessage:
+			if err := c.WriteMessage(PongMessage, payload); err != nil {
+				return 0, nil, err
+			}
+			continue
+
+		case PongMessage:
+			continue
+
+		case CloseMessage:
+			return 0, nil, c.closeReceived(payload)
+
+		case TextMessage, BinaryMessage:
+			messageType, data = opcode, payload
+
+		default:
+			_ = c.writeClose(CloseProtocolError, "unexpected opcode")
+			return 0, nil, fmt.Errorf("websocket: unexpected opcode %d", opcode)
+		}
+
+		// control frames may arrive between the fragments of a message, they are never fragmented themselves
+		for !fin {
+			frameFin, opcode, payload, err := c.readFrame()
+			if err != nil {
+				return 0, nil, err
+			}
+
+			switch opcode {
+			case PingMessage:
+				if err := c.WriteMessage(PongMessage, payload); err != nil {
+					return 0, nil, err
+				}
+				continue
+			case PongMessage:
+				continue
+			case CloseMessage:
+				return 0, nil, c.closeReceived(payload)
+			case continuationMessage:
+			default:
+				_ = c.writeClose(CloseProtocolError, "expected continuation frame")
+				return 0, nil, fmt.Errorf("websocket: expected continuation frame, got opcode %d", opcode)
+			}
+
+			if len(data)+len(payload) > maxMessageSize {
+				_ = c.writeClose(CloseMessageTooBig, "")
+				return 0, nil, ErrMessageTooBig
+			}
+			data = append(data, payload...)
+			fin = frameFin
+		}
+
+		return messageType, data, nil
+	}
+}
+
+// closeReceived answers a close frame from the peer, returning the *CloseError it carries.
+func (c *Conn) closeReceived(payload []byte) error {
+	closeErr := &CloseError{Code: CloseNoStatusRcvd}
+	if len(payload) >= 2 {
+		closeErr.Code = int(binary.BigEndian.Uint16(payload))
+		closeErr.Reason = string(payload[2:])
+	}
+	_ = c.writeClose(CloseNormalClosure, "")
+	return closeErr
+}
+
+// readFrame reads a single frame, unmasking its payload when masked.
+func (c *Conn) readFrame() (fin bool, opcode int, payload []byte, err error) {
+	var header [2]byte
+	if _, err := io.ReadFull(c.br, header[:]); err != nil {
+		return false, 0, nil, err
+	}
+
+	fin = header[0]&0x80 != 0
+	opcode = int(header[0] & 0x0f)
+	masked := header[1]&0x80 != 0
+
+	length := uint64(header[1] & 0x7f)
+	switch length {
+	case 126:
+		var ext [2]byte
+		if _, err := io.ReadFull(c.br, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err := io.ReadFull(c.br, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = binary.BigEndian.Uint64(ext[:])
+	}
+
+	if length > maxMessageSize {
+		_ = c.writeClose(CloseMessageTooBig, "")
+		return false, 0, nil, ErrMessageTooBig
+	}
+
+	var mask [4]byte
+	if masked {
+		if _, err := io.ReadFull(c.br, mask[:]); err != nil {
+			return false, 0, nil, err
+		}
+	}
+
+	payload = make([]byte, length)
+	if _, err := io.ReadFull(c.br, payload); err != nil {
+		return false, 0, nil, err
+	}
+
+	if masked {
+		for i := range payload {
+			payload[i] ^= mask[i%4]
+		}
+	}
+	return fin, opcode, payload, nil
+}
+
+// WriteMessage writes data as a single frame message of the given type.
+func (c *Conn) WriteMessage(messageType int, data []byte) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	if c.closeSent {
+		return net.ErrClosed
+	}
+	if messageType == CloseMessage {
+		c.closeSent = true
+	}
+	return c.writeFrame(messageType, data)
+}
+
+func (c *Conn) writeFrame(opcode int, data []byte) error {
+	frame := make([]byte, 0, len(data)+14)
+	frame = append(frame, 0x80|byte(opcode))
+
+	var maskBit byte
+	if c.client {
+		maskBit = 0x80
+	}
+
+	switch {
+	case len(data) <= 125:
+		frame = append(frame, maskBit|byte(len(data)))
+	case len(data) <= 0xffff:
+		frame = append(frame, maskBit|126, 0, 0)
+		binary.BigEndian.PutUint16(frame[len(frame)-2:], uint16(len(data)))
+	default:
+		frame = append(frame, maskBit|127, 0, 0, 0, 0, 0, 0, 0, 0)
+		binary.BigEndian.PutUint64(frame[len(frame)-8:], uint64(len(data)))
+	}
+
+	if c.client {
+		var mask [4]byte
+		if _, err := rand.Read(mask[:]); err != nil {
+			return err
+		}
+		frame = append(frame, mask[:]...)
+		start := len(frame)
+		frame = append(frame, data...)
+		for i := range frame[start:] {
+			frame[start+i] ^= mask[i%4]
+		}
+	} else {
+		frame = append(frame, data...)
+	}
+
+	_, err := c.conn.Write(frame)
+	return err
+}
+
+func (c *Conn) writeClose(code int, reason string) error {
+	payload := make([]byte, 2, 2+len(reason))
+	binary.BigEndian.PutUint16(payload, uint16(code))
+	payload = append(payload, reason...)
+	return c.WriteMessage(CloseMessage, payload)
+}
+
+// SetReadDeadline sets the deadline for future ReadMessage calls, a zero value disables the deadline.
+func (c *Conn) SetReadDeadline(t time.Time) error {
+	return c.conn.SetReadDeadline(t)
+}
+
+// CloseWithReason sends a close frame with the given code and reason, then closes the underlying connection.
+func (c *Conn) CloseWithReason(code int, reason string) error {
+	_ = c.conn.SetWriteDeadline(time.Now().Add(time.Second))
+	_ = c.writeClose(code, reason)
+	return c.Close()
+}
+
+// Close closes the underlying connection without sending a close frame.
+func (c *Conn) Close() error {
+	var err error
+	c.closeOnce.Do(func() {
+		err = c.conn.Close()
+	})
+	return err
+}
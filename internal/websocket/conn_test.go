@@ -0,0 +1,210 @@
+package websocket
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aidenwallis/go-twitch-client/internal/testutils/assert"
+)
+
+func echoServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := Upgrade(w, r)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		for {
+			messageType, data, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			if string(data) == "close" {
+				_ = conn.CloseWithReason(CloseGoingAway, "bye")
+				return
+			}
+			if string(data) == "ping" {
+				_ = conn.WriteMessage(PingMessage, []byte("hello"))
+				continue
+			}
+			if err := conn.WriteMessage(messageType, data); err != nil {
+				return
+			}
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func wsURL(srv *httptest.Server) string {
+	return "ws" + strings.TrimPrefix(srv.URL, "http")
+}
+
+func TestRoundTrip(t *testing.T) {
+	srv := echoServer(t)
+
+	conn, err := Dial(context.Background(), wsURL(srv), nil)
+	assert.NoError(t, err)
+	defer conn.Close()
+
+	for _, size := range []int{0, 5, 125, 126, 65535, 65536, 100000} {
+		message := strings.Repeat("a", size)
+		assert.NoError(t, conn.WriteMessage(TextMessage, []byte(message)))
+
+		messageType, data, err := conn.ReadMessage()
+		assert.NoError(t, err)
+		assert.Equal(t, TextMessage, messageType)
+		assert.Equal(t, message, string(data))
+	}
+}
+
+func TestPingIsAnswered(t *testing.T) {
+	srv := echoServer(t)
+
+	conn, err := Dial(context.Background(), wsURL(srv), nil)
+	assert.NoError(t, err)
+	defer conn.Close()
+
+	// the server pings, the pong is sent from within ReadMessage and ignored by the server
+	assert.NoError(t, conn.WriteMessage(TextMessage, []byte("ping")))
+	assert.NoError(t, conn.WriteMessage(TextMessage, []byte("after")))
+
+	_, data, err := conn.ReadMessage()
+	assert.NoError(t, err)
+	assert.Equal(t, "after", string(data))
+}
+
+func TestCloseError(t *testing.T) {
+	srv := echoServer(t)
+
+	conn, err := Dial(context.Background(), wsURL(srv), nil)
+	assert.NoError(t, err)
+	defer conn.Close()
+
+	assert.NoError(t, conn.WriteMessage(TextMessage, []byte("close")))
+
+	_, _, err = conn.ReadMessage()
+	var closeErr *CloseError
+	assert.Equal(t, true, errors.As(err, &closeErr))
+	assert.Equal(t, CloseGoingAway, closeErr.Code)
+	assert.Equal(t, "bye", closeErr.Reason)
+}
+
+// rawFrame encodes an unmasked frame, as sent by servers
+func rawFrame(fin bool, opcode int, payload string) []byte {
+	b := byte(opcode)
+	if fin {
+		b |= 0x80
+	}
+	return append([]byte{b, byte(len(payload))}, payload...)
+}
+
+func TestFragmentedMessage(t *testing.T) {
+	client, server := net.Pipe()
+	defer client.Close()
+	defer server.Close()
+	_ = client.SetDeadline(time.Now().Add(5 * time.Second))
+	_ = server.SetDeadline(time.Now().Add(5 * time.Second))
+
+	conn := newConn(client, bufio.NewReader(client), true)
+	peer := newConn(server, bufio.NewReader(server), false)
+
+	done := make(chan error, 1)
+	go func() {
+		// a ping between the fragments is answered, and does not end the message
+		_, _ = server.Write(rawFrame(false, TextMessage, "hel"))
+		_, _ = server.Write(rawFrame(true, PingMessage, "hi"))
+		_, opcode, payload, err := peer.readFrame()
+		if err != nil || opcode != PongMessage || string(payload) != "hi" {
+			done <- errors.New("expected pong")
+			return
+		}
+		_, _ = server.Write(rawFrame(false, continuationMessage, "lo"))
+		_, _ = server.Write(rawFrame(true, continuationMessage, " world"))
+
+		// a close between the fragments closes the connection
+		_, _ = server.Write(rawFrame(false, TextMessage, "a"))
+		_, _ = server.Write(rawFrame(true, CloseMessage, "\x03\xe9bye"))
+		_, opcode, _, err = peer.readFrame()
+		if err != nil || opcode != CloseMessage {
+			done <- errors.New("expected close")
+			return
+		}
+		done <- nil
+	}()
+
+	messageType, data, err := conn.ReadMessage()
+	assert.NoError(t, err)
+	assert.Equal(t, TextMessage, messageType)
+	assert.Equal(t, "hello world", string(data))
+
+	_, _, err = conn.ReadMessage()
+	var closeErr *CloseError
+	assert.Equal(t, true, errors.As(err, &closeErr))
+	assert.Equal(t, CloseGoingAway, closeErr.Code)
+	assert.Equal(t, "bye", closeErr.Reason)
+	assert.NoError(t, <-done)
+}
+
+func TestBadHandshake(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	defer srv.Close()
+
+	_, err := Dial(context.Background(), wsURL(srv), nil)
+	assert.ErrorIs(t, err, ErrBadHandshake)
+
+	_, err = Dial(context.Background(), "http://localhost", nil)
+	assert.Equal(t, true, err != nil)
+}
+
+func TestDialCancelled(t *testing.T) {
+	// the context is cancelled by a timer while the dial is blocked, without synchronizing with the dialer
+	dial := func(rawURL string) error {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		time.AfterFunc(100*time.Millisecond, cancel)
+
+		d := &Dialer{TLSConfig: &tls.Config{InsecureSkipVerify: true}}
+		_, err := d.Dial(ctx, rawURL, nil)
+		return err
+	}
+
+	t.Run("tls handshake", func(t *testing.T) {
+		// the server accepts the connection, but never answers the TLS handshake
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		assert.NoError(t, err)
+		defer listener.Close()
+
+		go func() {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+			time.Sleep(5 * time.Second)
+		}()
+
+		assert.ErrorIs(t, dial("wss://"+listener.Addr().String()), context.Canceled)
+	})
+
+	t.Run("websocket handshake", func(t *testing.T) {
+		// the TLS handshake completes, but the server never answers the upgrade request
+		srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			<-r.Context().Done()
+		}))
+		defer srv.Close()
+
+		assert.ErrorIs(t, dial("wss"+strings.TrimPrefix(srv.URL, "https")), context.Canceled)
+	})
+}
@@ -0,0 +1,448 @@
+package irc
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Commands sent by Twitch chat.
+//
+// See: https://dev.twitch.tv/docs/irc/commands
+const (
+	CommandPrivmsg         = "PRIVMSG"
+	CommandClearChat       = "CLEARCHAT"
+	CommandClearMsg        = "CLEARMSG"
+	CommandRoomState       = "ROOMSTATE"
+	CommandUserState       = "USERSTATE"
+	CommandGlobalUserState = "GLOBALUSERSTATE"
+	CommandNotice          = "NOTICE"
+	CommandUserNotice      = "USERNOTICE"
+	CommandWhisper         = "WHISPER"
+	CommandReconnect       = "RECONNECT"
+	CommandHostTarget      = "HOSTTARGET"
+	CommandPing            = "PING"
+	CommandPong            = "PONG"
+	CommandJoin            = "JOIN"
+	CommandPart            = "PART"
+)
+
+// ErrUnexpectedCommand is returned when a typed view is requested for a message with a different command
+var ErrUnexpectedCommand = errors.New("irc: unexpected command")
+
+// actionPrefix and actionSuffix wrap the text of /me messages
+const (
+	actionPrefix = "\x01ACTION "
+	actionSuffix = "\x01"
+)
+
+func (m *Message) expect(command string) error {
+	if m.Command != command {
+		return fmt.Errorf("%w: expected %s, got %s", ErrUnexpectedCommand, command, m.Command)
+	}
+	return nil
+}
+
+// PrivateMessage is a chat message sent to a channel
+//
+// See: https://dev.twitch.tv/docs/irc/tags#privmsg-tags
+type PrivateMessage struct {
+	// ID is the message ID.
+	ID string
+
+	// Channel is the login of the channel, without the leading #.
+	Channel string
+
+	// RoomID is the user ID of the channel.
+	RoomID string
+
+	// User is the sender of the message.
+	User User
+
+	// Text is the message text, with the /me action wrapping removed.
+	Text string
+
+	// Action is whether the message was sent with /me.
+	Action bool
+
+	// Emotes are the positions of the emotes in Text.
+	Emotes []Emote
+
+	// Bits is the amount of bits cheered in the message.
+	Bits int
+
+	// FirstMessage is whether this is the first message the user sent in the channel.
+	FirstMessage bool
+
+	// CustomRewardID is the ID of the channel points reward redeemed with the message, if any.
+	CustomRewardID string
+
+	// Reply is the message this message replies to, nil when it is not a reply.
+	Reply *Reply
+
+	// Time is when the message was sent.
+	Time time.Time
+}
+
+// Reply is the parent of a reply message
+type Reply struct {
+	// MessageID is the ID of the parent message.
+	MessageID string
+
+	// UserID is the user ID of the parent message sender.
+	UserID string
+
+	// UserLogin is the login of the parent message sender.
+	UserLogin string
+
+	// DisplayName is the display name of the parent message sender.
+	DisplayName string
+
+	// Text is the text of the parent message.
+	Text string
+}
+
+// PrivateMessage returns the typed view of a PRIVMSG message.
+func (m *Message) PrivateMessage() (*PrivateMessage, error) {
+	if err := m.expect(CommandPrivmsg); err != nil {
+		return nil, err
+	}
+
+	text := m.Trailing()
+	action := strings.HasPrefix(text, actionPrefix) && strings.HasSuffix(text, actionSuffix) && len(text) >= len(actionPrefix)+len(actionSuffix)
+	if action {
+		text = text[len(actionPrefix) : len(text)-len(actionSuffix)]
+	}
+
+	msg := &PrivateMessage{
+		ID:             m.Tags["id"],
+		Channel:        channelName(m.Param(0)),
+		RoomID:         m.Tags["room-id"],
+		User:           parseUser(m.Tags, m.Prefix.Name),
+		Text:           text,
+		Action:         action,
+		Emotes:         ParseEmotes(m.Tags["emotes"]),
+		FirstMessage:   m.Tags["first-msg"] == "1",
+		CustomRewardID: m.Tags["custom-reward-id"],
+		Time:           parseTimestamp(m.Tags["tmi-sent-ts"]),
+	}
+	if bits := parseInt(m.Tags, "bits"); bits != nil {
+		msg.Bits = *bits
+	}
+	if id := m.Tags["reply-parent-msg-id"]; id != "" {
+		msg.Reply = &Reply{
+			MessageID:   id,
+			UserID:      m.Tags["reply-parent-user-id"],
+			UserLogin:   m.Tags["reply-parent-user-login"],
+			DisplayName: m.Tags["reply-parent-display-name"],
+			Text:        m.Tags["reply-parent-msg-body"],
+		}
+	}
+	return msg, nil
+}
+
+// ClearChat is sent when a user is banned or timed out, or when all messages in a channel are cleared
+//
+// See: https://dev.twitch.tv/docs/irc/tags#clearchat-tags
+type ClearChat struct {
+	// Channel is the login of the channel, without the leading #.
+	Channel string
+
+	// RoomID is the user ID of the channel.
+	RoomID string
+
+	// TargetUserID is the user ID of the banned or timed out user, empty when the chat was cleared.
+	TargetUserID string
+
+	// TargetUserLogin is the login of the banned or timed out user, empty when the chat was cleared.
+	TargetUserLogin string
+
+	// Duration is the timeout duration, zero for permanent bans and chat clears.
+	Duration time.Duration
+
+	// Time is when the messages were cleared.
+	Time time.Time
+}
+
+// IsBan returns whether the user was permanently banned.
+func (c *ClearChat) IsBan() bool {
+	return c.TargetUserLogin != "" && c.Duration == 0
+}
+
+// IsTimeout returns whether the user was timed out.
+func (c *ClearChat) IsTimeout() bool {
+	return c.TargetUserLogin != "" && c.Duration > 0
+}
+
+// ClearChat returns the typed view of a CLEARCHAT message.
+func (m *Message) ClearChat() (*ClearChat, error) {
+	if err := m.expect(CommandClearChat); err != nil {
+		return nil, err
+	}
+
+	msg := &ClearChat{
+		Channel:      channelName(m.Param(0)),
+		RoomID:       m.Tags["room-id"],
+		TargetUserID: m.Tags["target-user-id"],
+		Time:         parseTimestamp(m.Tags["tmi-sent-ts"]),
+	}
+	if len(m.Params) > 1 {
+		msg.TargetUserLogin = m.Params[1]
+	}
+	if seconds := parseInt(m.Tags, "ban-duration"); seconds != nil {
+		msg.Duration = time.Duration(*seconds) * time.Second
+	}
+	return msg, nil
+}
+
+// ClearMessage is sent when a single message is deleted
+//
+// See: https://dev.twitch.tv/docs/irc/tags#clearmsg-tags
+type ClearMessage struct {
+	// Channel is the login of the channel, without the leading #.
+	Channel string
+
+	// RoomID is the user ID of the channel, Twitch does not always send it.
+	RoomID string
+
+	// TargetMessageID is the ID of the deleted message.
+	TargetMessageID string
+
+	// Login is the login of the user who sent the deleted message.
+	Login string
+
+	// Text is the text of the deleted message.
+	Text string
+
+	// Time is when the message was deleted.
+	Time time.Time
+}
+
+// ClearMessage returns the typed view of a CLEARMSG message.
+func (m *Message) ClearMessage() (*ClearMessage, error) {
+	if err := m.expect(CommandClearMsg); err != nil {
+		return nil, err
+	}
+
+	return &ClearMessage{
+		Channel:         channelName(m.Param(0)),
+		RoomID:          m.Tags["room-id"],
+		TargetMessageID: m.Tags["target-msg-id"],
+		Login:           m.Tags["login"],
+		Text:            m.Param(1),
+		Time:            parseTimestamp(m.Tags["tmi-sent-ts"]),
+	}, nil
+}
+
+// RoomState is the chat settings of a channel. Twitch sends every setting when joining a channel, and only the changed
+// settings afterwards, so every setting is nil when it was not included in the message.
+//
+// See: https://dev.twitch.tv/docs/irc/tags#roomstate-tags
+type RoomState struct {
+	// Channel is the login of the channel, without the leading #.
+	Channel string
+
+	// RoomID is the user ID of the channel.
+	RoomID string
+
+	// EmoteOnly is whether only emotes may be sent.
+	EmoteOnly *bool
+
+	// FollowersOnly is how many minutes users must have followed for to chat, -1 when followers only mode is disabled.
+	FollowersOnly *int
+
+	// UniqueChat is whether messages must be unique, known as r9k.
+	UniqueChat *bool
+
+	// Slow is how many seconds users must wait between messages, 0 when slow mode is disabled.
+	Slow *int
+
+	// SubsOnly is whether only subscribers may chat.
+	SubsOnly *bool
+}
+
+// RoomState returns the typed view of a ROOMSTATE message.
+func (m *Message) RoomState() (*RoomState, error) {
+	if err := m.expect(CommandRoomState); err != nil {
+		return nil, err
+	}
+
+	return &RoomState{
+		Channel:       channelName(m.Param(0)),
+		RoomID:        m.Tags["room-id"],
+		EmoteOnly:     parseBool(m.Tags, "emote-only"),
+		FollowersOnly: parseInt(m.Tags, "followers-only"),
+		UniqueChat:    parseBool(m.Tags, "r9k"),
+		Slow:          parseInt(m.Tags, "slow"),
+		SubsOnly:      parseBool(m.Tags, "subs-only"),
+	}, nil
+}
+
+// UserState is the state of the logged in user in a channel, sent when joining a channel and after sending a message
+//
+// See: https://dev.twitch.tv/docs/irc/tags#userstate-tags
+type UserState struct {
+	// Channel is the login of the channel, without the leading #.
+	Channel string
+
+	// User is the logged in user, the user ID and login are not included.
+	User User
+
+	// EmoteSets are the emote set IDs the user may use.
+	EmoteSets []string
+
+	// MessageID is the ID of the sent message, empty when the state was sent after joining.
+	MessageID string
+}
+
+// UserState returns the typed view of a USERSTATE message.
+func (m *Message) UserState() (*UserState, error) {
+	if err := m.expect(CommandUserState); err != nil {
+		return nil, err
+	}
+
+	return &UserState{
+		Channel:   channelName(m.Param(0)),
+		User:      parseUser(m.Tags, ""),
+		EmoteSets: parseEmoteSets(m.Tags["emote-sets"]),
+		MessageID: m.Tags["id"],
+	}, nil
+}
+
+// GlobalUserState is the state of the logged in user, sent after authenticating
+//
+// See: https://dev.twitch.tv/docs/irc/tags#globaluserstate-tags
+type GlobalUserState struct {
+	// User is the logged in user, the login is not included.
+	User User
+
+	// EmoteSets are the emote set IDs the user may use.
+	EmoteSets []string
+}
+
+// GlobalUserState returns the typed view of a GLOBALUSERSTATE message.
+func (m *Message) GlobalUserState() (*GlobalUserState, error) {
+	if err := m.expect(CommandGlobalUserState); err != nil {
+		return nil, err
+	}
+
+	return &GlobalUserState{
+		User:      parseUser(m.Tags, ""),
+		EmoteSets: parseEmoteSets(m.Tags["emote-sets"]),
+	}, nil
+}
+
+// Notice is a notice about a channel, or about the connection when Channel is empty
+//
+// See: https://dev.twitch.tv/docs/irc/msg-id
+type Notice struct {
+	// Channel is the login of the channel, without the leading #, empty for notices about the connection.
+	Channel string
+
+	// MessageID identifies the kind of notice, such as msg_duplicate.
+	MessageID string
+
+	// Text is the human readable notice.
+	Text string
+}
+
+// Notice returns the typed view of a NOTICE message.
+func (m *Message) Notice() (*Notice, error) {
+	if err := m.expect(CommandNotice); err != nil {
+		return nil, err
+	}
+
+	msg := &Notice{
+		MessageID: m.Tags["msg-id"],
+		Text:      m.Trailing(),
+	}
+	if target := m.Param(0); strings.HasPrefix(target, "#") {
+		msg.Channel = channelName(target)
+	}
+	return msg, nil
+}
+
+// Whisper is a private message between two users
+//
+// See: https://dev.twitch.tv/docs/irc/tags#whisper-tags
+type Whisper struct {
+	// ID is the message ID.
+	ID string
+
+	// ThreadID is the ID of the whisper thread between the two users.
+	ThreadID string
+
+	// User is the sender of the whisper.
+	User User
+
+	// To is the login of the recipient.
+	To string
+
+	// Text is the whisper text.
+	Text string
+
+	// Emotes are the positions of the emotes in Text.
+	Emotes []Emote
+}
+
+// Whisper returns the typed view of a WHISPER message.
+func (m *Message) Whisper() (*Whisper, error) {
+	if err := m.expect(CommandWhisper); err != nil {
+		return nil, err
+	}
+
+	return &Whisper{
+		ID:       m.Tags["message-id"],
+		ThreadID: m.Tags["thread-id"],
+		User:     parseUser(m.Tags, m.Prefix.Name),
+		To:       m.Param(0),
+		Text:     m.Param(1),
+		Emotes:   ParseEmotes(m.Tags["emotes"]),
+	}, nil
+}
+
+// Reconnect is sent when Twitch is about to restart the server, clients should reconnect and rejoin their channels
+//
+// See: https://dev.twitch.tv/docs/irc/commands#reconnect
+type Reconnect struct{}
+
+// Reconnect returns the typed view of a RECONNECT message.
+func (m *Message) Reconnect() (*Reconnect, error) {
+	if err := m.expect(CommandReconnect); err != nil {
+		return nil, err
+	}
+	return &Reconnect{}, nil
+}
+
+// HostTarget is sent when a channel starts or stops hosting another channel
+//
+// See: https://dev.twitch.tv/docs/irc/commands#hosttarget
+type HostTarget struct {
+	// Channel is the login of the hosting channel, without the leading #.
+	Channel string
+
+	// Target is the login of the hosted channel, empty when the channel stopped hosting.
+	Target string
+
+	// Viewers is the number of viewers watching the host.
+	Viewers int
+}
+
+// HostTarget returns the typed view of a HOSTTARGET message.
+func (m *Message) HostTarget() (*HostTarget, error) {
+	if err := m.expect(CommandHostTarget); err != nil {
+		return nil, err
+	}
+
+	msg := &HostTarget{Channel: channelName(m.Param(0))}
+	target, viewers := cutSpace(m.Param(1))
+	if target != "-" {
+		msg.Target = target
+	}
+	if v, err := strconv.Atoi(viewers); err == nil {
+		msg.Viewers = v
+	}
+	return msg, nil
+}
@@ -0,0 +1,168 @@
+package irc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aidenwallis/go-twitch-client/internal/testutils/assert"
+)
+
+func parse(t *testing.T, line string) *Message {
+	t.Helper()
+	m, err := Parse(line)
+	assert.NoError(t, err)
+	return m
+}
+
+func TestPrivateMessage(t *testing.T) {
+	msg, err := parse(t, privmsgLine).PrivateMessage()
+	assert.NoError(t, err)
+	assert.Equal(t, "abc", msg.ID)
+	assert.Equal(t, "forsen", msg.Channel)
+	assert.Equal(t, "1", msg.RoomID)
+	assert.Equal(t, "1", msg.User.ID)
+	assert.Equal(t, "forsen", msg.User.Login)
+	assert.Equal(t, "Forsen", msg.User.DisplayName)
+	assert.Equal(t, "#FF0000", msg.User.Color)
+	assert.Equal(t, true, msg.User.Subscriber)
+	assert.Equal(t, true, msg.User.IsBroadcaster())
+	assert.Equal(t, false, msg.User.IsVIP())
+	assert.Equal(t, "12", msg.User.BadgeInfo[0].Version)
+	assert.Equal(t, "Kappa hello chat", msg.Text)
+	assert.Equal(t, false, msg.Action)
+	assert.Equal(t, Emote{ID: "25", Start: 0, End: 4}, msg.Emotes[0])
+	assert.Equal(t, int64(1507246572675), msg.Time.UnixMilli())
+	assert.Equal(t, true, msg.Reply == nil)
+
+	msg, err = parse(t, "@bits=100;reply-parent-msg-id=p;reply-parent-user-login=nymn;reply-parent-msg-body=hello\\sthere :a!a@a PRIVMSG #forsen :\x01ACTION cheer100\x01").PrivateMessage()
+	assert.NoError(t, err)
+	assert.Equal(t, true, msg.Action)
+	assert.Equal(t, "cheer100", msg.Text)
+	assert.Equal(t, 100, msg.Bits)
+	assert.Equal(t, "nymn", msg.Reply.UserLogin)
+	assert.Equal(t, "hello there", msg.Reply.Text)
+
+	_, err = parse(t, "PING :tmi.twitch.tv").PrivateMessage()
+	assert.ErrorIs(t, err, ErrUnexpectedCommand)
+}
+
+func TestClearChat(t *testing.T) {
+	msg, err := parse(t, "@ban-duration=600;room-id=1;target-user-id=2;tmi-sent-ts=1642715756806 :tmi.twitch.tv CLEARCHAT #forsen :nymn").ClearChat()
+	assert.NoError(t, err)
+	assert.Equal(t, "forsen", msg.Channel)
+	assert.Equal(t, "nymn", msg.TargetUserLogin)
+	assert.Equal(t, "2", msg.TargetUserID)
+	assert.Equal(t, 10*time.Minute, msg.Duration)
+	assert.Equal(t, true, msg.IsTimeout())
+	assert.Equal(t, false, msg.IsBan())
+
+	msg, err = parse(t, "@room-id=1;target-user-id=2 :tmi.twitch.tv CLEARCHAT #forsen :nymn").ClearChat()
+	assert.NoError(t, err)
+	assert.Equal(t, true, msg.IsBan())
+
+	msg, err = parse(t, "@room-id=1 :tmi.twitch.tv CLEARCHAT #forsen").ClearChat()
+	assert.NoError(t, err)
+	assert.Equal(t, "", msg.TargetUserLogin)
+	assert.Equal(t, false, msg.IsBan())
+	assert.Equal(t, false, msg.IsTimeout())
+}
+
+func TestClearMessage(t *testing.T) {
+	msg, err := parse(t, "@login=nymn;room-id=;target-msg-id=abc;tmi-sent-ts=1642720582342 :tmi.twitch.tv CLEARMSG #forsen :bad words").ClearMessage()
+	assert.NoError(t, err)
+	assert.Equal(t, "forsen", msg.Channel)
+	assert.Equal(t, "nymn", msg.Login)
+	assert.Equal(t, "abc", msg.TargetMessageID)
+	assert.Equal(t, "bad words", msg.Text)
+}
+
+func TestRoomState(t *testing.T) {
+	msg, err := parse(t, "@emote-only=0;followers-only=-1;r9k=0;room-id=1;slow=0;subs-only=0 :tmi.twitch.tv ROOMSTATE #forsen").RoomState()
+	assert.NoError(t, err)
+	assert.Equal(t, "forsen", msg.Channel)
+	assert.Equal(t, false, *msg.EmoteOnly)
+	assert.Equal(t, -1, *msg.FollowersOnly)
+	assert.Equal(t, false, *msg.UniqueChat)
+	assert.Equal(t, 0, *msg.Slow)
+	assert.Equal(t, false, *msg.SubsOnly)
+
+	// deltas only include the changed setting
+	msg, err = parse(t, "@room-id=1;slow=10 :tmi.twitch.tv ROOMSTATE #forsen").RoomState()
+	assert.NoError(t, err)
+	assert.Equal(t, 10, *msg.Slow)
+	assert.Equal(t, true, msg.EmoteOnly == nil)
+	assert.Equal(t, true, msg.FollowersOnly == nil)
+	assert.Equal(t, true, msg.SubsOnly == nil)
+}
+
+func TestUserState(t *testing.T) {
+	msg, err := parse(t, "@badge-info=;badges=moderator/1;color=;display-name=Bot;emote-sets=0,300374282;id=xyz;mod=1;subscriber=0;user-type=mod :tmi.twitch.tv USERSTATE #forsen").UserState()
+	assert.NoError(t, err)
+	assert.Equal(t, "forsen", msg.Channel)
+	assert.Equal(t, true, msg.User.Mod)
+	assert.Equal(t, true, msg.User.HasBadge("moderator"))
+	assert.Equal(t, 0, len(msg.User.BadgeInfo))
+	assert.Equal(t, 2, len(msg.EmoteSets))
+	assert.Equal(t, "xyz", msg.MessageID)
+
+	global, err := parse(t, "@badges=;color=#0000FF;display-name=Bot;emote-sets=0;user-id=3;user-type= :tmi.twitch.tv GLOBALUSERSTATE").GlobalUserState()
+	assert.NoError(t, err)
+	assert.Equal(t, "3", global.User.ID)
+	assert.Equal(t, "Bot", global.User.DisplayName)
+	assert.Equal(t, "0", global.EmoteSets[0])
+}
+
+func TestNotice(t *testing.T) {
+	msg, err := parse(t, "@msg-id=msg_duplicate :tmi.twitch.tv NOTICE #forsen :Your message is identical to the one you sent less than 30 seconds ago.").Notice()
+	assert.NoError(t, err)
+	assert.Equal(t, "forsen", msg.Channel)
+	assert.Equal(t, "msg_duplicate", msg.MessageID)
+
+	msg, err = parse(t, ":tmi.twitch.tv NOTICE * :Login authentication failed").Notice()
+	assert.NoError(t, err)
+	assert.Equal(t, "", msg.Channel)
+	assert.Equal(t, "Login authentication failed", msg.Text)
+}
+
+func TestWhisper(t *testing.T) {
+	msg, err := parse(t, "@display-name=Nymn;emotes=25:0-4;message-id=1;thread-id=2_3;user-id=2 :nymn!nymn@nymn.tmi.twitch.tv WHISPER forsen :Kappa hi").Whisper()
+	assert.NoError(t, err)
+	assert.Equal(t, "nymn", msg.User.Login)
+	assert.Equal(t, "2", msg.User.ID)
+	assert.Equal(t, "forsen", msg.To)
+	assert.Equal(t, "Kappa hi", msg.Text)
+	assert.Equal(t, "2_3", msg.ThreadID)
+	assert.Equal(t, 1, len(msg.Emotes))
+}
+
+func TestReconnect(t *testing.T) {
+	_, err := parse(t, ":tmi.twitch.tv RECONNECT").Reconnect()
+	assert.NoError(t, err)
+}
+
+func TestHostTarget(t *testing.T) {
+	msg, err := parse(t, ":tmi.twitch.tv HOSTTARGET #forsen :nymn 42").HostTarget()
+	assert.NoError(t, err)
+	assert.Equal(t, "forsen", msg.Channel)
+	assert.Equal(t, "nymn", msg.Target)
+	assert.Equal(t, 42, msg.Viewers)
+
+	msg, err = parse(t, ":tmi.twitch.tv HOSTTARGET #forsen :- 0").HostTarget()
+	assert.NoError(t, err)
+	assert.Equal(t, "", msg.Target)
+}
+
+func TestParseEmotes(t *testing.T) {
+	emotes := ParseEmotes("25:0-4,12-16/1902:6-10/bad/30:x-1,3-2")
+	assert.Equal(t, 3, len(emotes))
+	assert.Equal(t, Emote{ID: "25", Start: 12, End: 16}, emotes[1])
+	assert.Equal(t, Emote{ID: "1902", Start: 6, End: 10}, emotes[2])
+	assert.Equal(t, 0, len(ParseEmotes("")))
+}
+
+func TestParseBadges(t *testing.T) {
+	badges := ParseBadges("broadcaster/1,,subscriber/3012,glhf-pledge")
+	assert.Equal(t, 3, len(badges))
+	assert.Equal(t, Badge{Name: "subscriber", Version: "3012"}, badges[1])
+	assert.Equal(t, Badge{Name: "glhf-pledge"}, badges[2])
+}
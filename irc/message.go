@@ -0,0 +1,209 @@
+// Package irc implements parsing and serializing IRCv3 messages, with typed views of the messages sent by Twitch chat.
+//
+// See: https://dev.twitch.tv/docs/irc
+package irc
+
+import (
+	"errors"
+	"sort"
+	"strings"
+)
+
+var (
+	// ErrEmptyMessage is returned when parsing an empty line
+	ErrEmptyMessage = errors.New("irc: empty message")
+
+	// ErrMissingCommand is returned when parsing a line without a valid command
+	ErrMissingCommand = errors.New("irc: message has no command")
+)
+
+// Message is a parsed IRC message
+type Message struct {
+	// Tags are the IRCv3 message tags, with their values unescaped.
+	Tags Tags
+
+	// Prefix is the source of the message, empty when the message has no prefix.
+	Prefix Prefix
+
+	// Command is the message command, such as PRIVMSG, or a numeric reply.
+	Command string
+
+	// Params are the command parameters, the trailing parameter is included as the last element.
+	Params []string
+}
+
+// Tags are IRCv3 message tags, mapping keys to their unescaped values
+type Tags map[string]string
+
+// Get returns the value of the tag, or an empty string when it is not set.
+func (t Tags) Get(key string) string {
+	return t[key]
+}
+
+// Prefix is the source of an IRC message, in the nick!user@host form
+type Prefix struct {
+	// Name is the nickname of a user, or a server name.
+	Name string
+
+	// User is the username of the user.
+	User string
+
+	// Host is the host of the user.
+	Host string
+}
+
+// IsZero returns whether the prefix is empty.
+func (p Prefix) IsZero() bool {
+	return p.Name == "" && p.User == "" && p.Host == ""
+}
+
+// String implements fmt.Stringer, formatting the prefix without the leading colon.
+func (p Prefix) String() string {
+	s := p.Name
+	if p.User != "" {
+		s += "!" + p.User
+	}
+	if p.Host != "" {
+		s += "@" + p.Host
+	}
+	return s
+}
+
+// Parse parses a single raw line into a Message, a trailing CRLF is ignored.
+//
+// Parsing does not copy the line, the tags, prefix, command and params all reference it. Only tag values that
+// contain escape sequences are allocated.
+func Parse(line string) (*Message, error) {
+	line = strings.TrimSuffix(line, "\n")
+	line = strings.TrimSuffix(line, "\r")
+	if line == "" {
+		return nil, ErrEmptyMessage
+	}
+
+	m := &Message{}
+
+	if line[0] == '@' {
+		var raw string
+		raw, line = cutSpace(line[1:])
+		m.Tags = parseTags(raw)
+	}
+
+	line = trimSpaces(line)
+	if line != "" && line[0] == ':' {
+		var raw string
+		raw, line = cutSpace(line[1:])
+		m.Prefix = parsePrefix(raw)
+	}
+
+	line = trimSpaces(line)
+	m.Command, line = cutSpace(line)
+	if m.Command == "" || m.Command[0] == '@' || m.Command[0] == ':' {
+		return nil, ErrMissingCommand
+	}
+
+	if line != "" {
+		m.Params = make([]string, 0, strings.Count(line, " ")+1)
+	}
+	for {
+		line = trimSpaces(line)
+		if line == "" {
+			break
+		}
+		if line[0] == ':' {
+			m.Params = append(m.Params, line[1:])
+			break
+		}
+
+		var param string
+		param, line = cutSpace(line)
+		m.Params = append(m.Params, param)
+	}
+
+	return m, nil
+}
+
+// Param returns the parameter at index i, or an empty string when there are not enough parameters.
+func (m *Message) Param(i int) string {
+	if i < 0 || i >= len(m.Params) {
+		return ""
+	}
+	return m.Params[i]
+}
+
+// Trailing returns the last parameter, which is the message text for most Twitch commands.
+func (m *Message) Trailing() string {
+	return m.Param(len(m.Params) - 1)
+}
+
+// String serializes the message into a raw line, without a trailing CRLF. Tags are written in sorted order.
+func (m *Message) String() string {
+	var b strings.Builder
+
+	if len(m.Tags) > 0 {
+		keys := make([]string, 0, len(m.Tags))
+		for k := range m.Tags {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		b.WriteByte('@')
+		for i, k := range keys {
+			if i > 0 {
+				b.WriteByte(';')
+			}
+			b.WriteString(k)
+			if v := m.Tags[k]; v != "" {
+				b.WriteByte('=')
+				escapeTagValue(&b, v)
+			}
+		}
+		b.WriteByte(' ')
+	}
+
+	if !m.Prefix.IsZero() {
+		b.WriteByte(':')
+		b.WriteString(m.Prefix.String())
+		b.WriteByte(' ')
+	}
+
+	b.WriteString(m.Command)
+
+	for i, param := range m.Params {
+		b.WriteByte(' ')
+		if i == len(m.Params)-1 && (param == "" || param[0] == ':' || strings.IndexByte(param, ' ') >= 0) {
+			b.WriteByte(':')
+		}
+		b.WriteString(param)
+	}
+
+	return b.String()
+}
+
+func parsePrefix(raw string) Prefix {
+	var p Prefix
+	if i := strings.IndexByte(raw, '@'); i >= 0 {
+		p.Host = raw[i+1:]
+		raw = raw[:i]
+	}
+	if i := strings.IndexByte(raw, '!'); i >= 0 {
+		p.User = raw[i+1:]
+		raw = raw[:i]
+	}
+	p.Name = raw
+	return p
+}
+
+// cutSpace splits s around the first space.
+func cutSpace(s string) (before, after string) {
+	if i := strings.IndexByte(s, ' '); i >= 0 {
+		return s[:i], s[i+1:]
+	}
+	return s, ""
+}
+
+func trimSpaces(s string) string {
+	for s != "" && s[0] == ' ' {
+		s = s[1:]
+	}
+	return s
+}
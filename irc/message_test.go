@@ -0,0 +1,140 @@
+package irc
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/aidenwallis/go-twitch-client/internal/testutils/assert"
+)
+
+const privmsgLine = `@badge-info=subscriber/12;badges=broadcaster/1,subscriber/12;color=#FF0000;display-name=Forsen;emotes=25:0-4;id=abc;mod=0;room-id=1;subscriber=1;tmi-sent-ts=1507246572675;turbo=0;user-id=1;user-type= :forsen!forsen@forsen.tmi.twitch.tv PRIVMSG #forsen :Kappa hello chat`
+
+func TestParse(t *testing.T) {
+	m, err := Parse(privmsgLine + "\r\n")
+	assert.NoError(t, err)
+	assert.Equal(t, "PRIVMSG", m.Command)
+	assert.Equal(t, "forsen", m.Prefix.Name)
+	assert.Equal(t, "forsen", m.Prefix.User)
+	assert.Equal(t, "forsen.tmi.twitch.tv", m.Prefix.Host)
+	assert.Equal(t, 2, len(m.Params))
+	assert.Equal(t, "#forsen", m.Param(0))
+	assert.Equal(t, "Kappa hello chat", m.Trailing())
+	assert.Equal(t, "", m.Param(5))
+	assert.Equal(t, "Forsen", m.Tags.Get("display-name"))
+	assert.Equal(t, "", m.Tags.Get("user-type"))
+
+	m, err = Parse("PING :tmi.twitch.tv")
+	assert.NoError(t, err)
+	assert.Equal(t, "PING", m.Command)
+	assert.Equal(t, true, m.Prefix.IsZero())
+	assert.Equal(t, "tmi.twitch.tv", m.Trailing())
+
+	m, err = Parse(":tmi.twitch.tv 001 justinfan123 :Welcome, GLHF!")
+	assert.NoError(t, err)
+	assert.Equal(t, "001", m.Command)
+	assert.Equal(t, "tmi.twitch.tv", m.Prefix.Name)
+	assert.Equal(t, "justinfan123", m.Param(0))
+	assert.Equal(t, "Welcome, GLHF!", m.Param(1))
+
+	m, err = Parse(":tmi.twitch.tv CAP * ACK :")
+	assert.NoError(t, err)
+	assert.Equal(t, 3, len(m.Params))
+	assert.Equal(t, "", m.Trailing())
+
+	_, err = Parse("\r\n")
+	assert.ErrorIs(t, err, ErrEmptyMessage)
+
+	_, err = Parse("@a=b :prefix")
+	assert.ErrorIs(t, err, ErrMissingCommand)
+}
+
+func TestParseTagEscapes(t *testing.T) {
+	m, err := Parse(`@a=semi\:colon;b=space\sbar;c=back\\slash;d=cr\rlf\n;e=unknown\x;f=trailing\;g;h= CMD`)
+	assert.NoError(t, err)
+	assert.Equal(t, "semi;colon", m.Tags["a"])
+	assert.Equal(t, "space bar", m.Tags["b"])
+	assert.Equal(t, `back\slash`, m.Tags["c"])
+	assert.Equal(t, "cr\rlf\n", m.Tags["d"])
+	assert.Equal(t, "unknownx", m.Tags["e"])
+	assert.Equal(t, "trailing", m.Tags["f"])
+
+	v, ok := m.Tags["g"]
+	assert.Equal(t, true, ok)
+	assert.Equal(t, "", v)
+
+	v, ok = m.Tags["h"]
+	assert.Equal(t, true, ok)
+	assert.Equal(t, "", v)
+}
+
+func TestMessageString(t *testing.T) {
+	m := &Message{
+		Tags:    Tags{"reply-parent-msg-body": "hello; world", "a": ""},
+		Command: "PRIVMSG",
+		Params:  []string{"#forsen", "hi chat"},
+	}
+	assert.Equal(t, `@a;reply-parent-msg-body=hello\:\sworld PRIVMSG #forsen :hi chat`, m.String())
+
+	m = &Message{Prefix: Prefix{Name: "forsen", User: "forsen", Host: "tmi"}, Command: "JOIN", Params: []string{"#forsen"}}
+	assert.Equal(t, ":forsen!forsen@tmi JOIN #forsen", m.String())
+
+	m = &Message{Command: "PRIVMSG", Params: []string{"#forsen", ":)"}}
+	assert.Equal(t, "PRIVMSG #forsen ::)", m.String())
+
+	m = &Message{Command: "CAP", Params: []string{"*", "ACK", ""}}
+	assert.Equal(t, "CAP * ACK :", m.String())
+
+	parsed, err := Parse(privmsgLine)
+	assert.NoError(t, err)
+	reparsed, err := Parse(parsed.String())
+	assert.NoError(t, err)
+	assertMessageEqual(t, parsed, reparsed)
+}
+
+func FuzzParse(f *testing.F) {
+	f.Add(privmsgLine)
+	f.Add("PING :tmi.twitch.tv")
+	f.Add(`@a=\:\s\\\r\n\x\ :nick!user@host CMD p1 p2 :trailing text`)
+	f.Add(":tmi.twitch.tv CAP * ACK :")
+	f.Add("@;;= CMD ::")
+
+	f.Fuzz(func(t *testing.T, line string) {
+		m, err := Parse(line)
+		if err != nil {
+			return
+		}
+		if strings.ContainsAny(line, "\r\n\x00") {
+			// line breaks can't be represented in params, so the line can't round trip
+			return
+		}
+
+		reparsed, err := Parse(m.String())
+		if err != nil {
+			t.Fatalf("reparsing %q: %s", m.String(), err)
+		}
+		assertMessageEqual(t, m, reparsed)
+	})
+}
+
+func BenchmarkParse(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := Parse(privmsgLine); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func assertMessageEqual(t *testing.T, expected, actual *Message) {
+	t.Helper()
+	assert.Equal(t, expected.Command, actual.Command)
+	assert.Equal(t, expected.Prefix, actual.Prefix)
+	assert.Equal(t, len(expected.Params), len(actual.Params))
+	for i := range expected.Params {
+		assert.Equal(t, expected.Params[i], actual.Params[i])
+	}
+	assert.Equal(t, len(expected.Tags), len(actual.Tags))
+	for k, v := range expected.Tags {
+		assert.Equal(t, v, actual.Tags[k])
+	}
+}
@@ -0,0 +1,91 @@
+package irc
+
+import (
+	"strings"
+)
+
+// parseTags parses the raw tags section, without the leading @.
+func parseTags(raw string) Tags {
+	tags := make(Tags, strings.Count(raw, ";")+1)
+	for raw != "" {
+		var tag string
+		if i := strings.IndexByte(raw, ';'); i >= 0 {
+			tag, raw = raw[:i], raw[i+1:]
+		} else {
+			tag, raw = raw, ""
+		}
+
+		key, value := tag, ""
+		if i := strings.IndexByte(tag, '='); i >= 0 {
+			key, value = tag[:i], unescapeTagValue(tag[i+1:])
+		}
+		if key == "" {
+			continue
+		}
+		tags[key] = value
+	}
+	return tags
+}
+
+// unescapeTagValue unescapes an IRCv3 tag value, values without escape sequences are returned as is.
+//
+// See: https://ircv3.net/specs/extensions/message-tags#escaping-values
+func unescapeTagValue(value string) string {
+	i := strings.IndexByte(value, '\\')
+	if i < 0 {
+		return value
+	}
+
+	var b strings.Builder
+	b.Grow(len(value))
+	b.WriteString(value[:i])
+
+	for ; i < len(value); i++ {
+		c := value[i]
+		if c != '\\' {
+			b.WriteByte(c)
+			continue
+		}
+
+		i++
+		if i >= len(value) {
+			// a trailing backslash is dropped
+			break
+		}
+
+		switch value[i] {
+		case ':':
+			b.WriteByte(';')
+		case 's':
+			b.WriteByte(' ')
+		case 'r':
+			b.WriteByte('\r')
+		case 'n':
+			b.WriteByte('\n')
+		default:
+			// includes \\, any other escaped character is itself
+			b.WriteByte(value[i])
+		}
+	}
+	return b.String()
+}
+
+// escapeTagValue writes the IRCv3 escaped tag value to b.
+func escapeTagValue(b *strings.Builder, value string) {
+	for i := 0; i < len(value); i++ {
+		switch c := value[i]; c {
+		case ';':
+			b.WriteString(`\:`)
+		case ' ':
+			b.WriteString(`\s`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\n':
+			b.WriteString(`\n`)
+		default:
+			b.WriteByte(c)
+		}
+	}
+}
@@ -0,0 +1,212 @@
+package irc
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Badge is a chat badge from the badges or badge-info tags
+type Badge struct {
+	// Name is the badge set ID, such as subscriber.
+	Name string
+
+	// Version is the badge version, for badge-info tags this is the badge metadata such as the subscription months.
+	Version string
+}
+
+// ParseBadges parses the value of a badges or badge-info tag, such as broadcaster/1,subscriber/12.
+func ParseBadges(raw string) []Badge {
+	if raw == "" {
+		return nil
+	}
+
+	badges := make([]Badge, 0, strings.Count(raw, ",")+1)
+	for raw != "" {
+		var badge string
+		if i := strings.IndexByte(raw, ','); i >= 0 {
+			badge, raw = raw[:i], raw[i+1:]
+		} else {
+			badge, raw = raw, ""
+		}
+		if badge == "" {
+			continue
+		}
+
+		name, version := badge, ""
+		if i := strings.IndexByte(badge, '/'); i >= 0 {
+			name, version = badge[:i], badge[i+1:]
+		}
+		badges = append(badges, Badge{Name: name, Version: version})
+	}
+	return badges
+}
+
+// Emote is the position of an emote from the emotes tag
+type Emote struct {
+	// ID is the emote ID.
+	ID string
+
+	// Start is the index of the first code point of the emote in the message text.
+	Start int
+
+	// End is the index of the last code point of the emote in the message text, inclusive.
+	End int
+}
+
+// ParseEmotes parses the value of an emotes tag, such as 25:0-4,12-16/1902:6-10. Emotes are returned in the order they
+// appear in the tag, malformed positions are skipped.
+func ParseEmotes(raw string) []Emote {
+	if raw == "" {
+		return nil
+	}
+
+	emotes := make([]Emote, 0, strings.Count(raw, ",")+strings.Count(raw, "/")+1)
+	for raw != "" {
+		var emote string
+		if i := strings.IndexByte(raw, '/'); i >= 0 {
+			emote, raw = raw[:i], raw[i+1:]
+		} else {
+			emote, raw = raw, ""
+		}
+
+		i := strings.IndexByte(emote, ':')
+		if i < 0 {
+			continue
+		}
+		id, positions := emote[:i], emote[i+1:]
+
+		for positions != "" {
+			var position string
+			if i := strings.IndexByte(positions, ','); i >= 0 {
+				position, positions = positions[:i], positions[i+1:]
+			} else {
+				position, positions = positions, ""
+			}
+
+			i := strings.IndexByte(position, '-')
+			if i < 0 {
+				continue
+			}
+			start, err := strconv.Atoi(position[:i])
+			if err != nil {
+				continue
+			}
+			end, err := strconv.Atoi(position[i+1:])
+			if err != nil || end < start {
+				continue
+			}
+			emotes = append(emotes, Emote{ID: id, Start: start, End: end})
+		}
+	}
+	return emotes
+}
+
+// User is the sender of a message, as described by its tags
+type User struct {
+	// ID is the user ID, from the user-id tag.
+	ID string
+
+	// Login is the login name of the user.
+	Login string
+
+	// DisplayName is the display name of the user, from the display-name tag.
+	DisplayName string
+
+	// Color is the hex chat color of the user, empty when the user never set one.
+	Color string
+
+	// Badges are the chat badges of the user.
+	Badges []Badge
+
+	// BadgeInfo is the metadata of the badges, such as the exact number of subscribed months.
+	BadgeInfo []Badge
+
+	// Mod is whether the user is a moderator of the channel.
+	Mod bool
+
+	// Subscriber is whether the user is subscribed to the channel.
+	Subscriber bool
+
+	// Turbo is whether the user has Turbo.
+	Turbo bool
+}
+
+// HasBadge returns whether the user has a badge in the given set.
+func (u *User) HasBadge(name string) bool {
+	for _, badge := range u.Badges {
+		if badge.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// IsBroadcaster returns whether the user is the broadcaster of the channel.
+func (u *User) IsBroadcaster() bool {
+	return u.HasBadge("broadcaster")
+}
+
+// IsVIP returns whether the user is a VIP of the channel.
+func (u *User) IsVIP() bool {
+	return u.HasBadge("vip")
+}
+
+func parseUser(tags Tags, login string) User {
+	return User{
+		ID:          tags["user-id"],
+		Login:       login,
+		DisplayName: tags["display-name"],
+		Color:       tags["color"],
+		Badges:      ParseBadges(tags["badges"]),
+		BadgeInfo:   ParseBadges(tags["badge-info"]),
+		Mod:         tags["mod"] == "1",
+		Subscriber:  tags["subscriber"] == "1",
+		Turbo:       tags["turbo"] == "1",
+	}
+}
+
+// parseEmoteSets parses the value of an emote-sets tag.
+func parseEmoteSets(raw string) []string {
+	if raw == "" {
+		return nil
+	}
+	return strings.Split(raw, ",")
+}
+
+// parseTimestamp parses the value of a tmi-sent-ts tag, in milliseconds since the unix epoch.
+func parseTimestamp(raw string) time.Time {
+	ms, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil {
+		return time.Time{}
+	}
+	return time.UnixMilli(ms)
+}
+
+// parseBool parses a 0 or 1 tag value, returning nil when the tag is not set.
+func parseBool(tags Tags, key string) *bool {
+	v, ok := tags[key]
+	if !ok {
+		return nil
+	}
+	b := v == "1"
+	return &b
+}
+
+// parseInt parses an integer tag value, returning nil when the tag is not set or malformed.
+func parseInt(tags Tags, key string) *int {
+	v, ok := tags[key]
+	if !ok {
+		return nil
+	}
+	i, err := strconv.Atoi(v)
+	if err != nil {
+		return nil
+	}
+	return &i
+}
+
+// channelName strips the leading # from a channel parameter.
+func channelName(param string) string {
+	return strings.TrimPrefix(param, "#")
+}
@@ -0,0 +1,364 @@
+package irc
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// UserNotice msg-id values, identifying the kind of event.
+//
+// See: https://dev.twitch.tv/docs/irc/tags#usernotice-tags
+const (
+	UserNoticeSub                = "sub"
+	UserNoticeResub              = "resub"
+	UserNoticeSubGift            = "subgift"
+	UserNoticeAnonSubGift        = "anonsubgift"
+	UserNoticeSubMysteryGift     = "submysterygift"
+	UserNoticeAnonSubMysteryGift = "anonsubmysterygift"
+	UserNoticeRaid               = "raid"
+	UserNoticeUnraid             = "unraid"
+	UserNoticeRitual             = "ritual"
+	UserNoticeBitsBadgeTier      = "bitsbadgetier"
+	UserNoticeAnnouncement       = "announcement"
+	UserNoticeViewerMilestone    = "viewermilestone"
+)
+
+// msgParamPrefix prefixes the tags that carry the parameters of the event
+const msgParamPrefix = "msg-param-"
+
+// anonymousGifterID is the user ID Twitch sends for gifts by anonymous users
+const anonymousGifterID = "274598607"
+
+// SubPlan is the plan of a subscription
+type SubPlan string
+
+// Subscription plans.
+const (
+	SubPlanPrime SubPlan = "Prime"
+	SubPlanTier1 SubPlan = "1000"
+	SubPlanTier2 SubPlan = "2000"
+	SubPlanTier3 SubPlan = "3000"
+)
+
+// Tier returns the tier of the plan, 1 to 3, Prime subscriptions are tier 1. It returns 0 for unknown plans.
+func (p SubPlan) Tier() int {
+	switch p {
+	case SubPlanPrime, SubPlanTier1:
+		return 1
+	case SubPlanTier2:
+		return 2
+	case SubPlanTier3:
+		return 3
+	}
+	return 0
+}
+
+// UserNotice is sent when an event happens in a channel, such as a subscription, a raid or an announcement
+//
+// See: https://dev.twitch.tv/docs/irc/tags#usernotice-tags
+type UserNotice struct {
+	// ID is the message ID.
+	ID string
+
+	// Channel is the login of the channel, without the leading #.
+	Channel string
+
+	// RoomID is the user ID of the channel.
+	RoomID string
+
+	// User is the user who caused the event, such as the subscriber or the raider.
+	User User
+
+	// MsgID identifies the kind of event, such as UserNoticeSub.
+	MsgID string
+
+	// SystemMessage is the human readable description of the event, as shown in chat.
+	SystemMessage string
+
+	// Text is the message the user included, such as a resub message, empty when there is none.
+	Text string
+
+	// Emotes are the positions of the emotes in Text.
+	Emotes []Emote
+
+	// Time is when the event happened.
+	Time time.Time
+
+	// Event is the typed event for the MsgID, such as *SubNotice. Unknown kinds of events are an *UnknownNotice.
+	Event UserNoticeEvent
+}
+
+// UserNoticeEvent is the typed event of a USERNOTICE, one of *SubNotice, *ResubNotice, *SubGiftNotice,
+// *SubMysteryGiftNotice, *RaidNotice, *UnraidNotice, *RitualNotice, *BitsBadgeTierNotice, *AnnouncementNotice,
+// *ViewerMilestoneNotice or *UnknownNotice.
+type UserNoticeEvent interface {
+	userNoticeEvent()
+}
+
+// SubNotice is sent when a user subscribes for the first time
+type SubNotice struct {
+	// CumulativeMonths is the total number of months the user has subscribed.
+	CumulativeMonths int
+
+	// StreakMonths is the number of consecutive months the user has subscribed, 0 when ShouldShareStreak is false.
+	StreakMonths int
+
+	// ShouldShareStreak is whether the user shares their streak.
+	ShouldShareStreak bool
+
+	// Plan is the subscription plan.
+	Plan SubPlan
+
+	// PlanName is the display name of the plan.
+	PlanName string
+
+	// MultiMonthDuration is the number of months the user paid for up front, 0 or 1 for a single month.
+	MultiMonthDuration int
+
+	// MultiMonthTenure is the month of the multi-month subscription the user is in.
+	MultiMonthTenure int
+
+	// WasGifted is whether the subscription was gifted.
+	WasGifted bool
+}
+
+// ResubNotice is sent when a user shares a resubscription, it has the same fields as a SubNotice
+type ResubNotice struct {
+	SubNotice
+}
+
+// SubGiftNotice is sent when a user gifts a subscription to another user
+type SubGiftNotice struct {
+	// Anonymous is whether the gifter is anonymous, UserNotice.User is then the AnAnonymousGifter account.
+	Anonymous bool
+
+	// Months is the total number of months the recipient has subscribed.
+	Months int
+
+	// GiftMonths is the number of months gifted.
+	GiftMonths int
+
+	// RecipientID is the user ID of the recipient.
+	RecipientID string
+
+	// RecipientLogin is the login of the recipient.
+	RecipientLogin string
+
+	// RecipientDisplayName is the display name of the recipient.
+	RecipientDisplayName string
+
+	// Plan is the subscription plan.
+	Plan SubPlan
+
+	// PlanName is the display name of the plan.
+	PlanName string
+
+	// OriginID identifies the gift, the gifts of a SubMysteryGiftNotice share its OriginID.
+	OriginID string
+
+	// SenderCount is the total number of subscriptions the gifter has gifted in the channel, 0 when they do not
+	// share it.
+	SenderCount int
+}
+
+// SubMysteryGiftNotice is sent when a user gifts subscriptions to random users in the channel, a SubGiftNotice follows
+// for every gifted subscription
+type SubMysteryGiftNotice struct {
+	// Anonymous is whether the gifter is anonymous, UserNotice.User is then the AnAnonymousGifter account.
+	Anonymous bool
+
+	// Count is the number of gifted subscriptions.
+	Count int
+
+	// Plan is the subscription plan.
+	Plan SubPlan
+
+	// OriginID identifies the gift.
+	OriginID string
+
+	// SenderCount is the total number of subscriptions the gifter has gifted in the channel, 0 when they do not
+	// share it.
+	SenderCount int
+}
+
+// RaidNotice is sent when a channel raids the channel
+type RaidNotice struct {
+	// Login is the login of the raiding channel.
+	Login string
+
+	// DisplayName is the display name of the raiding channel.
+	DisplayName string
+
+	// ViewerCount is the number of viewers raiding.
+	ViewerCount int
+
+	// ProfileImageURL is the profile image URL of the raiding channel.
+	ProfileImageURL string
+}
+
+// UnraidNotice is sent when the channel cancels a raid
+type UnraidNotice struct{}
+
+// RitualNotice is sent when a ritual happens, such as a new chatter introducing themselves
+type RitualNotice struct {
+	// Name is the name of the ritual, such as new_chatter.
+	Name string
+}
+
+// BitsBadgeTierNotice is sent when a user earns a new bits badge tier
+type BitsBadgeTierNotice struct {
+	// Threshold is the tier of the bits badge earned, such as 10000.
+	Threshold int
+}
+
+// AnnouncementNotice is an announcement sent by a moderator or the broadcaster
+type AnnouncementNotice struct {
+	// Color is the highlight color of the announcement, PRIMARY, BLUE, GREEN, ORANGE or PURPLE.
+	Color string
+}
+
+// ViewerMilestoneNotice is sent when a viewer reaches a milestone, such as a watch streak
+type ViewerMilestoneNotice struct {
+	// Category is the kind of milestone, such as watch-streak.
+	Category string
+
+	// ID identifies the milestone.
+	ID string
+
+	// Value is the milestone reached, such as the number of consecutive streaks watched.
+	Value int
+
+	// Reward is the number of channel points rewarded for the milestone.
+	Reward int
+}
+
+// UnknownNotice is the event of a USERNOTICE with a msg-id that has no typed event
+type UnknownNotice struct {
+	// Params are the msg-param-* tags, keyed without the msg-param- prefix.
+	Params map[string]string
+}
+
+func (*SubNotice) userNoticeEvent()             {}
+func (*ResubNotice) userNoticeEvent()           {}
+func (*SubGiftNotice) userNoticeEvent()         {}
+func (*SubMysteryGiftNotice) userNoticeEvent()  {}
+func (*RaidNotice) userNoticeEvent()            {}
+func (*UnraidNotice) userNoticeEvent()          {}
+func (*RitualNotice) userNoticeEvent()          {}
+func (*BitsBadgeTierNotice) userNoticeEvent()   {}
+func (*AnnouncementNotice) userNoticeEvent()    {}
+func (*ViewerMilestoneNotice) userNoticeEvent() {}
+func (*UnknownNotice) userNoticeEvent()         {}
+
+// UserNotice returns the typed view of a USERNOTICE message.
+func (m *Message) UserNotice() (*UserNotice, error) {
+	if err := m.expect(CommandUserNotice); err != nil {
+		return nil, err
+	}
+
+	msg := &UserNotice{
+		ID:            m.Tags["id"],
+		Channel:       channelName(m.Param(0)),
+		RoomID:        m.Tags["room-id"],
+		User:          parseUser(m.Tags, m.Tags["login"]),
+		MsgID:         m.Tags["msg-id"],
+		SystemMessage: m.Tags["system-msg"],
+		Text:          m.Param(1),
+		Emotes:        ParseEmotes(m.Tags["emotes"]),
+		Time:          parseTimestamp(m.Tags["tmi-sent-ts"]),
+	}
+	msg.Event = parseUserNoticeEvent(msg.MsgID, m.Tags, msg.User.ID == anonymousGifterID)
+	return msg, nil
+}
+
+func parseUserNoticeEvent(msgID string, tags Tags, anonymous bool) UserNoticeEvent {
+	param := func(key string) string {
+		return tags[msgParamPrefix+key]
+	}
+	intParam := func(key string) int {
+		i, _ := strconv.Atoi(param(key))
+		return i
+	}
+	boolParam := func(key string) bool {
+		v := param(key)
+		return v == "1" || v == "true"
+	}
+
+	switch msgID {
+	case UserNoticeSub, UserNoticeResub:
+		sub := SubNotice{
+			CumulativeMonths:   intParam("cumulative-months"),
+			StreakMonths:       intParam("streak-months"),
+			ShouldShareStreak:  boolParam("should-share-streak"),
+			Plan:               SubPlan(param("sub-plan")),
+			PlanName:           param("sub-plan-name"),
+			MultiMonthDuration: intParam("multimonth-duration"),
+			MultiMonthTenure:   intParam("multimonth-tenure"),
+			WasGifted:          boolParam("was-gifted"),
+		}
+		if msgID == UserNoticeResub {
+			return &ResubNotice{SubNotice: sub}
+		}
+		return &sub
+
+	case UserNoticeSubGift, UserNoticeAnonSubGift:
+		return &SubGiftNotice{
+			Anonymous:            anonymous || msgID == UserNoticeAnonSubGift,
+			Months:               intParam("months"),
+			GiftMonths:           intParam("gift-months"),
+			RecipientID:          param("recipient-id"),
+			RecipientLogin:       param("recipient-user-name"),
+			RecipientDisplayName: param("recipient-display-name"),
+			Plan:                 SubPlan(param("sub-plan")),
+			PlanName:             param("sub-plan-name"),
+			OriginID:             param("origin-id"),
+			SenderCount:          intParam("sender-count"),
+		}
+
+	case UserNoticeSubMysteryGift, UserNoticeAnonSubMysteryGift:
+		return &SubMysteryGiftNotice{
+			Anonymous:   anonymous || msgID == UserNoticeAnonSubMysteryGift,
+			Count:       intParam("mass-gift-count"),
+			Plan:        SubPlan(param("sub-plan")),
+			OriginID:    param("origin-id"),
+			SenderCount: intParam("sender-count"),
+		}
+
+	case UserNoticeRaid:
+		return &RaidNotice{
+			Login:           param("login"),
+			DisplayName:     param("displayName"),
+			ViewerCount:     intParam("viewerCount"),
+			ProfileImageURL: param("profileImageURL"),
+		}
+
+	case UserNoticeUnraid:
+		return &UnraidNotice{}
+
+	case UserNoticeRitual:
+		return &RitualNotice{Name: param("ritual-name")}
+
+	case UserNoticeBitsBadgeTier:
+		return &BitsBadgeTierNotice{Threshold: intParam("threshold")}
+
+	case UserNoticeAnnouncement:
+		return &AnnouncementNotice{Color: param("color")}
+
+	case UserNoticeViewerMilestone:
+		return &ViewerMilestoneNotice{
+			Category: param("category"),
+			ID:       param("id"),
+			Value:    intParam("value"),
+			Reward:   intParam("copoReward"),
+		}
+	}
+
+	params := map[string]string{}
+	for key, value := range tags {
+		if strings.HasPrefix(key, msgParamPrefix) {
+			params[key[len(msgParamPrefix):]] = value
+		}
+	}
+	return &UnknownNotice{Params: params}
+}
@@ -0,0 +1,117 @@
+package irc
+
+import (
+	"testing"
+
+	"github.com/aidenwallis/go-twitch-client/internal/testutils/assert"
+)
+
+func userNotice(t *testing.T, line string) *UserNotice {
+	t.Helper()
+	msg, err := parse(t, line).UserNotice()
+	assert.NoError(t, err)
+	return msg
+}
+
+func TestUserNoticeResub(t *testing.T) {
+	msg := userNotice(t, `@badge-info=subscriber/13;badges=subscriber/12;color=#008000;display-name=Ronni;emotes=25:0-4;id=db25007f-7a18-43eb-9379-80131e44d633;login=ronni;mod=0;msg-id=resub;msg-param-cumulative-months=13;msg-param-should-share-streak=1;msg-param-streak-months=6;msg-param-sub-plan=Prime;msg-param-sub-plan-name=Prime;msg-param-was-gifted=false;room-id=12345678;subscriber=1;system-msg=ronni\shas\ssubscribed\sfor\s13\smonths!;tmi-sent-ts=1507246572675;user-id=87654321 :tmi.twitch.tv USERNOTICE #dallas :Kappa Great stream -- keep it up!`)
+	assert.Equal(t, "db25007f-7a18-43eb-9379-80131e44d633", msg.ID)
+	assert.Equal(t, "dallas", msg.Channel)
+	assert.Equal(t, "12345678", msg.RoomID)
+	assert.Equal(t, "ronni", msg.User.Login)
+	assert.Equal(t, "87654321", msg.User.ID)
+	assert.Equal(t, UserNoticeResub, msg.MsgID)
+	assert.Equal(t, "ronni has subscribed for 13 months!", msg.SystemMessage)
+	assert.Equal(t, "Kappa Great stream -- keep it up!", msg.Text)
+	assert.Equal(t, Emote{ID: "25", Start: 0, End: 4}, msg.Emotes[0])
+	assert.Equal(t, int64(1507246572675), msg.Time.UnixMilli())
+
+	resub, ok := msg.Event.(*ResubNotice)
+	assert.Equal(t, true, ok)
+	assert.Equal(t, 13, resub.CumulativeMonths)
+	assert.Equal(t, 6, resub.StreakMonths)
+	assert.Equal(t, true, resub.ShouldShareStreak)
+	assert.Equal(t, SubPlanPrime, resub.Plan)
+	assert.Equal(t, 1, resub.Plan.Tier())
+	assert.Equal(t, false, resub.WasGifted)
+
+	msg = userNotice(t, `@login=a;msg-id=sub;msg-param-cumulative-months=1;msg-param-sub-plan=3000;msg-param-multimonth-duration=6;msg-param-multimonth-tenure=1 :tmi.twitch.tv USERNOTICE #dallas`)
+	sub, ok := msg.Event.(*SubNotice)
+	assert.Equal(t, true, ok)
+	assert.Equal(t, 3, sub.Plan.Tier())
+	assert.Equal(t, 6, sub.MultiMonthDuration)
+	assert.Equal(t, 1, sub.MultiMonthTenure)
+	assert.Equal(t, "", msg.Text)
+}
+
+func TestUserNoticeGifts(t *testing.T) {
+	msg := userNotice(t, `@display-name=TWW2;login=tww2;msg-id=subgift;msg-param-months=1;msg-param-gift-months=3;msg-param-recipient-display-name=Mr_Woodchuck;msg-param-recipient-id=55554444;msg-param-recipient-user-name=mr_woodchuck;msg-param-sub-plan-name=House\sof\sNyoro~n;msg-param-sub-plan=1000;msg-param-origin-id=o1;msg-param-sender-count=42;room-id=19571752;user-id=13405587 :tmi.twitch.tv USERNOTICE #forstycup`)
+	gift, ok := msg.Event.(*SubGiftNotice)
+	assert.Equal(t, true, ok)
+	assert.Equal(t, false, gift.Anonymous)
+	assert.Equal(t, 3, gift.GiftMonths)
+	assert.Equal(t, "55554444", gift.RecipientID)
+	assert.Equal(t, "mr_woodchuck", gift.RecipientLogin)
+	assert.Equal(t, "Mr_Woodchuck", gift.RecipientDisplayName)
+	assert.Equal(t, "House of Nyoro~n", gift.PlanName)
+	assert.Equal(t, 1, gift.Plan.Tier())
+	assert.Equal(t, 42, gift.SenderCount)
+
+	msg = userNotice(t, `@login=ananonymousgifter;msg-id=subgift;msg-param-sub-plan=2000;user-id=274598607 :tmi.twitch.tv USERNOTICE #forstycup`)
+	gift = msg.Event.(*SubGiftNotice)
+	assert.Equal(t, true, gift.Anonymous)
+	assert.Equal(t, 2, gift.Plan.Tier())
+
+	msg = userNotice(t, `@login=a;msg-id=anonsubmysterygift;msg-param-mass-gift-count=5;msg-param-sub-plan=1000;msg-param-origin-id=o2 :tmi.twitch.tv USERNOTICE #forstycup`)
+	mystery, ok := msg.Event.(*SubMysteryGiftNotice)
+	assert.Equal(t, true, ok)
+	assert.Equal(t, true, mystery.Anonymous)
+	assert.Equal(t, 5, mystery.Count)
+	assert.Equal(t, "o2", mystery.OriginID)
+}
+
+func TestUserNoticeEvents(t *testing.T) {
+	msg := userNotice(t, `@display-name=TestChannel;login=testchannel;msg-id=raid;msg-param-displayName=TestChannel;msg-param-login=testchannel;msg-param-viewerCount=15;msg-param-profileImageURL=https://example.com/a.png;system-msg=15\sraiders\sfrom\sTestChannel\shave\sjoined!;user-id=123456 :tmi.twitch.tv USERNOTICE #othertestchannel`)
+	raid, ok := msg.Event.(*RaidNotice)
+	assert.Equal(t, true, ok)
+	assert.Equal(t, "testchannel", raid.Login)
+	assert.Equal(t, "TestChannel", raid.DisplayName)
+	assert.Equal(t, 15, raid.ViewerCount)
+	assert.Equal(t, "https://example.com/a.png", raid.ProfileImageURL)
+
+	_, ok = userNotice(t, `@msg-id=unraid :tmi.twitch.tv USERNOTICE #a`).Event.(*UnraidNotice)
+	assert.Equal(t, true, ok)
+
+	ritual := userNotice(t, `@msg-id=ritual;msg-param-ritual-name=new_chatter :tmi.twitch.tv USERNOTICE #a :HeyGuys`).Event.(*RitualNotice)
+	assert.Equal(t, "new_chatter", ritual.Name)
+
+	tier := userNotice(t, `@msg-id=bitsbadgetier;msg-param-threshold=10000 :tmi.twitch.tv USERNOTICE #a`).Event.(*BitsBadgeTierNotice)
+	assert.Equal(t, 10000, tier.Threshold)
+
+	msg = userNotice(t, `@msg-id=announcement;msg-param-color=PURPLE :tmi.twitch.tv USERNOTICE #a :Hello chat`)
+	assert.Equal(t, "PURPLE", msg.Event.(*AnnouncementNotice).Color)
+	assert.Equal(t, "Hello chat", msg.Text)
+
+	milestone := userNotice(t, `@msg-id=viewermilestone;msg-param-category=watch-streak;msg-param-copoReward=450;msg-param-id=m1;msg-param-value=3 :tmi.twitch.tv USERNOTICE #a`).Event.(*ViewerMilestoneNotice)
+	assert.Equal(t, "watch-streak", milestone.Category)
+	assert.Equal(t, "m1", milestone.ID)
+	assert.Equal(t, 3, milestone.Value)
+	assert.Equal(t, 450, milestone.Reward)
+}
+
+func TestUserNoticeUnknown(t *testing.T) {
+	msg := userNotice(t, `@msg-id=charitydonation;msg-param-charity-name=Example;msg-param-donation-amount=500;room-id=1 :tmi.twitch.tv USERNOTICE #a`)
+	unknown, ok := msg.Event.(*UnknownNotice)
+	assert.Equal(t, true, ok)
+	assert.Equal(t, 2, len(unknown.Params))
+	assert.Equal(t, "Example", unknown.Params["charity-name"])
+	assert.Equal(t, "500", unknown.Params["donation-amount"])
+
+	// malformed numbers are zero
+	sub := userNotice(t, `@msg-id=sub;msg-param-cumulative-months=x :tmi.twitch.tv USERNOTICE #a`).Event.(*SubNotice)
+	assert.Equal(t, 0, sub.CumulativeMonths)
+	assert.Equal(t, 0, SubPlan("").Tier())
+
+	_, err := parse(t, ":tmi.twitch.tv NOTICE #a :hi").UserNotice()
+	assert.ErrorIs(t, err, ErrUnexpectedCommand)
+}
@@ -0,0 +1,132 @@
+// Package oidc implements verification of Twitch OpenID Connect ID tokens, and the OIDC UserInfo endpoint.
+//
+// See: https://dev.twitch.tv/docs/authentication/getting-tokens-oidc
+package oidc
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/aidenwallis/go-twitch-client/internal/client"
+)
+
+const (
+	// DefaultIssuer is the issuer Twitch sets in the iss claim of every ID token.
+	DefaultIssuer = "https://id.twitch.tv/oauth2"
+
+	// DefaultJWKSURL is the URL Twitch publishes the public keys used to sign ID tokens on.
+	DefaultJWKSURL = "https://id.twitch.tv/oauth2/keys"
+
+	// DefaultUserInfoURL is the URL of the Twitch OIDC UserInfo endpoint.
+	DefaultUserInfoURL = "https://id.twitch.tv/oauth2/userinfo"
+
+	// defaultKeysCacheDuration is how long fetched keys are trusted before they are fetched again.
+	defaultKeysCacheDuration = time.Hour
+
+	// defaultLeeway is the default allowed clock skew when checking time based claims.
+	defaultLeeway = time.Minute
+)
+
+// Client defines the OIDC client
+type Client interface {
+	// VerifyIDToken verifies an id_token returned by Twitch, and returns the claims contained within it.
+	//
+	// The token's RS256 signature is checked against the JWKS, along with the issuer, audience, expiry and nonce.
+	VerifyIDToken(context.Context, *VerifyIDTokenRequest) (*IDTokenClaims, error)
+
+	// GetUserInfo implements https://dev.twitch.tv/docs/authentication/getting-tokens-oidc#getting-claims-information-from-an-access-token
+	//
+	// Gets the claims for the user that the given OAuth token belongs to.
+	GetUserInfo(context.Context, *GetUserInfoRequest) (*UserInfo, error)
+}
+
+// ClientOptions defines all options this client supports.
+type ClientOptions struct {
+	// ClientID is your third-party client ID. ID tokens must have been issued to this client ID, as it is
+	// checked against the aud claim.
+	ClientID string
+
+	// Issuer (optional) is the expected iss claim of the ID token. Defaults to DefaultIssuer.
+	Issuer string
+
+	// JWKSURL (optional) is the URL to fetch the token signing keys from. Defaults to DefaultJWKSURL.
+	JWKSURL string
+
+	// UserInfoURL (optional) is the URL of the UserInfo endpoint. Defaults to DefaultUserInfoURL.
+	UserInfoURL string
+
+	// KeysCacheDuration (optional) is how long the fetched keys are cached for before they are fetched again.
+	// Defaults to one hour. Keys are always refetched when a token references a key ID that isn't cached.
+	KeysCacheDuration time.Duration
+
+	// Leeway (optional) is the allowed clock skew when checking the exp and iat claims. Defaults to one minute.
+	Leeway time.Duration
+
+	// RequestTimeout defines a client-level request timeout duration for your client.
+	// You may also cancel individual requests by using context cancellation.
+	RequestTimeout time.Duration
+
+	// Transport defines a HTTP transport, useful for defining keep-alive settings,
+	// timeouts, or mocking client behaviour in tests.
+	Transport http.RoundTripper
+}
+
+type oidcClient struct {
+	*client.Client
+
+	clientID    string
+	issuer      string
+	userInfoURL string
+	leeway      time.Duration
+	keys        *keySet
+	now         func() time.Time
+}
+
+// NewClient creates a new instance of Client
+func NewClient(options *ClientOptions) Client {
+	if options == nil {
+		options = &ClientOptions{}
+	}
+
+	c := &oidcClient{
+		clientID:    options.ClientID,
+		issuer:      stringOrDefault(options.Issuer, DefaultIssuer),
+		userInfoURL: stringOrDefault(options.UserInfoURL, DefaultUserInfoURL),
+		leeway:      defaultLeeway,
+		now:         time.Now,
+		Client: client.NewClient(&client.Options{
+			RequestTimeout: options.RequestTimeout,
+			Transport:      options.Transport,
+		}),
+	}
+	if options.Leeway > 0 {
+		c.leeway = options.Leeway
+	}
+
+	cacheDuration := defaultKeysCacheDuration
+	if options.KeysCacheDuration > 0 {
+		cacheDuration = options.KeysCacheDuration
+	}
+	c.keys = newKeySet(c.Client, stringOrDefault(options.JWKSURL, DefaultJWKSURL), cacheDuration)
+
+	return c
+}
+
+func stringOrDefault(v, fallback string) string {
+	if v == "" {
+		return fallback
+	}
+	return v
+}
+
+func headers(token string) func(context.Context) (http.Header, error) {
+	return func(context.Context) (http.Header, error) {
+		h := http.Header{}
+		h.Set("Accept", "application/json")
+		if token != "" {
+			h.Set("Authorization", "Bearer "+token)
+		}
+		return h, nil
+	}
+}
@@ -0,0 +1,231 @@
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrMalformedToken is returned when the ID token isn't a well formed JWT.
+	ErrMalformedToken = errors.New("oidc: malformed id token")
+
+	// ErrUnsupportedAlgorithm is returned when the ID token isn't signed using RS256.
+	ErrUnsupportedAlgorithm = errors.New("oidc: unsupported signing algorithm")
+
+	// ErrUnknownKey is returned when the ID token was signed with a key that isn't in the JWKS.
+	ErrUnknownKey = errors.New("oidc: unknown signing key")
+
+	// ErrInvalidSignature is returned when the ID token signature doesn't match its contents.
+	ErrInvalidSignature = errors.New("oidc: invalid signature")
+
+	// ErrInvalidIssuer is returned when the iss claim doesn't match the configured issuer.
+	ErrInvalidIssuer = errors.New("oidc: invalid issuer")
+
+	// ErrInvalidAudience is returned when the aud claim doesn't contain the configured client ID.
+	ErrInvalidAudience = errors.New("oidc: invalid audience")
+
+	// ErrTokenExpired is returned when the exp claim is in the past.
+	ErrTokenExpired = errors.New("oidc: token expired")
+
+	// ErrTokenNotYetValid is returned when the iat claim is in the future.
+	ErrTokenNotYetValid = errors.New("oidc: token issued in the future")
+
+	// ErrInvalidNonce is returned when the nonce claim doesn't match the nonce in the request.
+	ErrInvalidNonce = errors.New("oidc: invalid nonce")
+)
+
+// VerifyIDTokenRequest defines the options passed to VerifyIDToken
+type VerifyIDTokenRequest struct {
+	// IDToken is the raw id_token JWT returned by Twitch.
+	IDToken string
+
+	// Nonce (optional) is the nonce you passed in the authorization request. If set, the nonce claim must match it.
+	Nonce string
+}
+
+// IDTokenClaims defines the claims contained within a Twitch ID token.
+//
+// Optional claims are only present if you requested them through the claims parameter of the authorization request.
+type IDTokenClaims struct {
+	// Issuer is the URI of the issuing authority.
+	Issuer string `json:"iss"`
+
+	// Subject is the ID of the user that authorized the app.
+	Subject string `json:"sub"`
+
+	// Audience contains the client ID of the application that requested the user’s authorization.
+	Audience Audience `json:"aud"`
+
+	// ExpiresAt is the time when the token expires.
+	ExpiresAt NumericDate `json:"exp"`
+
+	// IssuedAt is the time when the token was issued.
+	IssuedAt NumericDate `json:"iat"`
+
+	// Nonce is the value of the nonce parameter passed in the authorization request, if one was specified.
+	Nonce string `json:"nonce,omitempty"`
+
+	// AuthorizedParty is the client ID the token was issued to.
+	AuthorizedParty string `json:"azp,omitempty"`
+
+	// PreferredUsername is the user’s display name.
+	PreferredUsername string `json:"preferred_username,omitempty"`
+
+	// Email is the email address of the user that authorized the app.
+	Email string `json:"email,omitempty"`
+
+	// EmailVerified is whether Twitch has verified the user’s email address.
+	EmailVerified *bool `json:"email_verified,omitempty"`
+
+	// Picture is a URL to the user’s profile image if they included one; otherwise, a default image.
+	Picture string `json:"picture,omitempty"`
+
+	// UpdatedAt is the date and time that the user last updated their profile.
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+}
+
+// Audience is the aud claim, which may be encoded as either a single string or a list of strings.
+type Audience []string
+
+// UnmarshalJSON implements json.Unmarshaler
+func (a *Audience) UnmarshalJSON(bs []byte) error {
+	var single string
+	if err := json.Unmarshal(bs, &single); err == nil {
+		*a = Audience{single}
+		return nil
+	}
+
+	var multiple []string
+	if err := json.Unmarshal(bs, &multiple); err != nil {
+		return err
+	}
+	*a = multiple
+	return nil
+}
+
+// Contains returns whether the audience contains the given client ID
+func (a Audience) Contains(clientID string) bool {
+	for _, v := range a {
+		if v == clientID {
+			return true
+		}
+	}
+	return false
+}
+
+// NumericDate is a JWT timestamp, encoded as the number of seconds since the Unix epoch.
+type NumericDate struct {
+	time.Time
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (d *NumericDate) UnmarshalJSON(bs []byte) error {
+	var v json.Number
+	if err := json.Unmarshal(bs, &v); err != nil {
+		return err
+	}
+
+	f, err := v.Float64()
+	if err != nil {
+		return err
+	}
+
+	d.Time = time.Unix(int64(f), 0).UTC()
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler
+func (d NumericDate) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.Unix())
+}
+
+type tokenHeader struct {
+	Algorithm string `json:"alg"`
+	KeyID     string `json:"kid"`
+}
+
+// VerifyIDToken verifies an id_token returned by Twitch, and returns the claims contained within it.
+//
+// The token's RS256 signature is checked against the JWKS, along with the issuer, audience, expiry and nonce.
+func (c *oidcClient) VerifyIDToken(ctx context.Context, req *VerifyIDTokenRequest) (*IDTokenClaims, error) {
+	parts := strings.Split(req.IDToken, ".")
+	if len(parts) != 3 {
+		return nil, ErrMalformedToken
+	}
+
+	var header tokenHeader
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, err
+	}
+	if header.Algorithm != "RS256" {
+		return nil, ErrUnsupportedAlgorithm
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, ErrMalformedToken
+	}
+
+	key, err := c.keys.key(ctx, header.KeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature); err != nil {
+		return nil, ErrInvalidSignature
+	}
+
+	var claims IDTokenClaims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, err
+	}
+
+	if err := c.validateClaims(&claims, req.Nonce); err != nil {
+		return nil, err
+	}
+	return &claims, nil
+}
+
+func (c *oidcClient) validateClaims(claims *IDTokenClaims, nonce string) error {
+	if claims.Issuer != c.issuer {
+		return ErrInvalidIssuer
+	}
+
+	if !claims.Audience.Contains(c.clientID) {
+		return ErrInvalidAudience
+	}
+
+	now := c.now()
+	if claims.ExpiresAt.IsZero() || !now.Before(claims.ExpiresAt.Add(c.leeway)) {
+		return ErrTokenExpired
+	}
+	if !claims.IssuedAt.IsZero() && now.Add(c.leeway).Before(claims.IssuedAt.Time) {
+		return ErrTokenNotYetValid
+	}
+
+	if nonce != "" && claims.Nonce != nonce {
+		return ErrInvalidNonce
+	}
+
+	return nil
+}
+
+func decodeSegment(segment string, v interface{}) error {
+	bs, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return ErrMalformedToken
+	}
+
+	if err := json.Unmarshal(bs, v); err != nil {
+		return ErrMalformedToken
+	}
+	return nil
+}
@@ -0,0 +1,230 @@
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aidenwallis/go-twitch-client/internal/testutils/assert"
+)
+
+const (
+	fakeClientID = "clientID"
+	fakeKeyID    = "1"
+	fakeNonce    = "nonce"
+)
+
+// keyServer is a local stand-in for the Twitch JWKS endpoint
+type keyServer struct {
+	*httptest.Server
+	key      *rsa.PrivateKey
+	requests int32
+}
+
+func newKeyServer(t *testing.T) *keyServer {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	s := &keyServer{key: key}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&s.requests, 1)
+		_ = json.NewEncoder(w).Encode(&JSONWebKeySet{
+			Keys: []*JSONWebKey{
+				{
+					KeyType:   "RSA",
+					KeyID:     fakeKeyID,
+					Algorithm: "RS256",
+					Use:       "sig",
+					N:         base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+					E:         base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+				},
+			},
+		})
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *keyServer) client() Client {
+	return NewClient(&ClientOptions{
+		ClientID: fakeClientID,
+		JWKSURL:  s.URL,
+	})
+}
+
+func (s *keyServer) sign(t *testing.T, header map[string]string, claims interface{}) string {
+	encode := func(v interface{}) string {
+		bs, err := json.Marshal(v)
+		assert.NoError(t, err)
+		return base64.RawURLEncoding.EncodeToString(bs)
+	}
+
+	payload := encode(header) + "." + encode(claims)
+	digest := sha256.Sum256([]byte(payload))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, s.key, crypto.SHA256, digest[:])
+	assert.NoError(t, err)
+
+	return payload + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func validClaims() map[string]interface{} {
+	now := time.Now()
+	return map[string]interface{}{
+		"iss":                DefaultIssuer,
+		"sub":                "123",
+		"aud":                fakeClientID,
+		"exp":                now.Add(time.Hour).Unix(),
+		"iat":                now.Unix(),
+		"nonce":              fakeNonce,
+		"azp":                fakeClientID,
+		"preferred_username": "Forsen",
+		"picture":            "https://static-cdn.jtvnw.net/user.png",
+		"updated_at":         "2022-02-23T23:10:59Z",
+	}
+}
+
+func rs256Header() map[string]string {
+	return map[string]string{"alg": "RS256", "typ": "JWT", "kid": fakeKeyID}
+}
+
+func TestVerifyIDToken(t *testing.T) {
+	ctx := context.Background()
+	s := newKeyServer(t)
+	c := s.client()
+
+	t.Run("valid", func(t *testing.T) {
+		claims, err := c.VerifyIDToken(ctx, &VerifyIDTokenRequest{
+			IDToken: s.sign(t, rs256Header(), validClaims()),
+			Nonce:   fakeNonce,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "123", claims.Subject)
+		assert.Equal(t, "Forsen", claims.PreferredUsername)
+		assert.Equal(t, "https://static-cdn.jtvnw.net/user.png", claims.Picture)
+		assert.Equal(t, 2022, claims.UpdatedAt.Year())
+	})
+
+	t.Run("audience list", func(t *testing.T) {
+		in := validClaims()
+		in["aud"] = []string{"other", fakeClientID}
+
+		_, err := c.VerifyIDToken(ctx, &VerifyIDTokenRequest{IDToken: s.sign(t, rs256Header(), in)})
+		assert.NoError(t, err)
+	})
+
+	// keys are only fetched once, then served from cache
+	assert.Equal(t, int32(1), atomic.LoadInt32(&s.requests))
+
+	errorCases := map[string]struct {
+		token    func() string
+		nonce    string
+		expected error
+	}{
+		"malformed": {
+			token:    func() string { return "abc.def" },
+			expected: ErrMalformedToken,
+		},
+		"algorithm": {
+			token: func() string {
+				return s.sign(t, map[string]string{"alg": "HS256", "kid": fakeKeyID}, validClaims())
+			},
+			expected: ErrUnsupportedAlgorithm,
+		},
+		"unknown key": {
+			token: func() string {
+				return s.sign(t, map[string]string{"alg": "RS256", "kid": "unknown"}, validClaims())
+			},
+			expected: ErrUnknownKey,
+		},
+		"signature": {
+			token: func() string {
+				token := s.sign(t, rs256Header(), validClaims())
+				return token[:len(token)-4] + "AAAA"
+			},
+			expected: ErrInvalidSignature,
+		},
+		"issuer": {
+			token: func() string {
+				in := validClaims()
+				in["iss"] = "https://example.com"
+				return s.sign(t, rs256Header(), in)
+			},
+			expected: ErrInvalidIssuer,
+		},
+		"audience": {
+			token: func() string {
+				in := validClaims()
+				in["aud"] = "other"
+				return s.sign(t, rs256Header(), in)
+			},
+			expected: ErrInvalidAudience,
+		},
+		"expired": {
+			token: func() string {
+				in := validClaims()
+				in["exp"] = time.Now().Add(-time.Hour).Unix()
+				return s.sign(t, rs256Header(), in)
+			},
+			expected: ErrTokenExpired,
+		},
+		"issued in future": {
+			token: func() string {
+				in := validClaims()
+				in["iat"] = time.Now().Add(time.Hour).Unix()
+				return s.sign(t, rs256Header(), in)
+			},
+			expected: ErrTokenNotYetValid,
+		},
+		"nonce": {
+			token:    func() string { return s.sign(t, rs256Header(), validClaims()) },
+			nonce:    "other",
+			expected: ErrInvalidNonce,
+		},
+	}
+
+	for name, tc := range errorCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			_, err := c.VerifyIDToken(ctx, &VerifyIDTokenRequest{IDToken: tc.token(), Nonce: tc.nonce})
+			assert.ErrorIs(t, err, tc.expected)
+		})
+	}
+}
+
+func TestKeySetRefresh(t *testing.T) {
+	ctx := context.Background()
+	s := newKeyServer(t)
+	c := s.client().(*oidcClient)
+
+	now := time.Now()
+	c.keys.now = func() time.Time { return now }
+
+	_, err := c.keys.key(ctx, fakeKeyID)
+	assert.NoError(t, err)
+
+	// unknown keys don't cause a refetch within the minimum refresh interval
+	_, err = c.keys.key(ctx, "unknown")
+	assert.ErrorIs(t, err, ErrUnknownKey)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&s.requests))
+
+	now = now.Add(minKeysRefreshInterval)
+	_, err = c.keys.key(ctx, "unknown")
+	assert.ErrorIs(t, err, ErrUnknownKey)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&s.requests))
+
+	// cache expiry
+	now = now.Add(defaultKeysCacheDuration)
+	_, err = c.keys.key(ctx, fakeKeyID)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&s.requests))
+}
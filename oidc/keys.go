@@ -0,0 +1,141 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/aidenwallis/go-twitch-client/internal/client"
+)
+
+// minKeysRefreshInterval stops unknown key IDs from causing the JWKS to be refetched on every verification.
+const minKeysRefreshInterval = time.Minute
+
+var errInvalidKey = errors.New("oidc: invalid JSON web key")
+
+// JSONWebKeySet is the JWKS document Twitch publishes its token signing keys in.
+type JSONWebKeySet struct {
+	// Keys is a slice of JSONWebKey
+	Keys []*JSONWebKey `json:"keys"`
+}
+
+// JSONWebKey is a single public key in a JSONWebKeySet
+type JSONWebKey struct {
+	// KeyType is the cryptographic family of the key, Twitch only uses "RSA".
+	KeyType string `json:"kty"`
+
+	// KeyID identifies the key, it is referenced by the kid header of signed tokens.
+	KeyID string `json:"kid"`
+
+	// Algorithm is the algorithm the key is used with, for example "RS256".
+	Algorithm string `json:"alg"`
+
+	// Use is the intended use of the key, for example "sig".
+	Use string `json:"use"`
+
+	// N is the base64url encoded RSA modulus.
+	N string `json:"n"`
+
+	// E is the base64url encoded RSA public exponent.
+	E string `json:"e"`
+}
+
+// keySet fetches and caches the JWKS
+type keySet struct {
+	client        *client.Client
+	url           string
+	cacheDuration time.Duration
+	now           func() time.Time
+
+	mu        sync.Mutex
+	keys      map[string]*rsa.PublicKey
+	fetchedAt time.Time
+}
+
+func newKeySet(c *client.Client, url string, cacheDuration time.Duration) *keySet {
+	return &keySet{
+		client:        c,
+		url:           url,
+		cacheDuration: cacheDuration,
+		now:           time.Now,
+	}
+}
+
+// key returns the public key for the given key ID, fetching the key set when the cache has expired, or when
+// the key isn't known yet (as Twitch may have rotated keys).
+func (s *keySet) key(ctx context.Context, kid string) (*rsa.PublicKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	stale := s.keys == nil || now.Sub(s.fetchedAt) >= s.cacheDuration
+	if k, ok := s.keys[kid]; ok && !stale {
+		return k, nil
+	}
+
+	if stale || now.Sub(s.fetchedAt) >= minKeysRefreshInterval {
+		if err := s.refresh(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	if k, ok := s.keys[kid]; ok {
+		return k, nil
+	}
+	return nil, ErrUnknownKey
+}
+
+func (s *keySet) refresh(ctx context.Context) error {
+	resp, err := client.WithBody[JSONWebKeySet](s.client.Request(&client.RequestConfig{
+		Method:  http.MethodGet,
+		URL:     s.url,
+		Headers: headers(""),
+	}).Do(ctx))
+	if err != nil {
+		return err
+	}
+
+	keys := make(map[string]*rsa.PublicKey, len(resp.Keys))
+	for _, k := range resp.Keys {
+		if k.KeyType != "RSA" {
+			continue
+		}
+
+		pub, err := k.rsaPublicKey()
+		if err != nil {
+			continue
+		}
+		keys[k.KeyID] = pub
+	}
+
+	s.keys = keys
+	s.fetchedAt = s.now()
+	return nil
+}
+
+func (k *JSONWebKey) rsaPublicKey() (*rsa.PublicKey, error) {
+	n, err := base64.RawURLEncoding.DecodeString(k.N)
+	if err != nil {
+		return nil, err
+	}
+
+	e, err := base64.RawURLEncoding.DecodeString(k.E)
+	if err != nil {
+		return nil, err
+	}
+
+	exponent := new(big.Int).SetBytes(e)
+	if !exponent.IsInt64() || exponent.Int64() > 1<<31-1 || exponent.Int64() < 2 {
+		return nil, errInvalidKey
+	}
+
+	return &rsa.PublicKey{
+		N: new(big.Int).SetBytes(n),
+		E: int(exponent.Int64()),
+	}, nil
+}
@@ -0,0 +1,61 @@
+package oidc
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/aidenwallis/go-twitch-client/internal/client"
+)
+
+// GetUserInfoRequest defines the options passed to GetUserInfo
+type GetUserInfoRequest struct {
+	// Token is the user's OAuth access token, obtained through the OIDC authorization flow.
+	Token string
+}
+
+// UserInfo defines the claims returned by the UserInfo endpoint.
+//
+// Optional claims are only present if you requested them through the claims parameter of the authorization request.
+type UserInfo struct {
+	// Audience contains the client ID of the application that requested the user’s authorization.
+	Audience Audience `json:"aud"`
+
+	// ExpiresAt is the time when the token expires.
+	ExpiresAt NumericDate `json:"exp"`
+
+	// IssuedAt is the time when the token was issued.
+	IssuedAt NumericDate `json:"iat"`
+
+	// Issuer is the URI of the issuing authority.
+	Issuer string `json:"iss"`
+
+	// Subject is the ID of the user that authorized the app.
+	Subject string `json:"sub"`
+
+	// PreferredUsername is the user’s display name.
+	PreferredUsername string `json:"preferred_username,omitempty"`
+
+	// Email is the email address of the user that authorized the app.
+	Email string `json:"email,omitempty"`
+
+	// EmailVerified is whether Twitch has verified the user’s email address.
+	EmailVerified *bool `json:"email_verified,omitempty"`
+
+	// Picture is a URL to the user’s profile image if they included one; otherwise, a default image.
+	Picture string `json:"picture,omitempty"`
+
+	// UpdatedAt is the date and time that the user last updated their profile.
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+}
+
+// GetUserInfo implements https://dev.twitch.tv/docs/authentication/getting-tokens-oidc#getting-claims-information-from-an-access-token
+//
+// Gets the claims for the user that the given OAuth token belongs to.
+func (c *oidcClient) GetUserInfo(ctx context.Context, req *GetUserInfoRequest) (*UserInfo, error) {
+	return client.WithBody[UserInfo](c.Request(&client.RequestConfig{
+		Method:  http.MethodGet,
+		URL:     c.userInfoURL,
+		Headers: headers(req.Token),
+	}).Do(ctx))
+}
@@ -0,0 +1,38 @@
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/aidenwallis/go-twitch-client/internal/testutils"
+	"github.com/aidenwallis/go-twitch-client/internal/testutils/assert"
+)
+
+func TestGetUserInfo(t *testing.T) {
+	ctx := context.Background()
+	c := NewClient(&ClientOptions{
+		ClientID: fakeClientID,
+		Transport: testutils.Middleware(func(req *http.Request) *testutils.Response {
+			assert.Equal(t, http.MethodGet, req.Method)
+			assert.Equal(t, DefaultUserInfoURL, req.URL.String())
+			assert.Equal(t, "Bearer abc123", req.Header.Get("Authorization"))
+			return &testutils.Response{
+				Status: http.StatusOK,
+				Body:   []byte(`{"aud":"clientID","exp":1645837869,"iat":1645837869,"iss":"https://id.twitch.tv/oauth2","sub":"713936733","preferred_username":"scottwht","email_verified":true,"updated_at":"2022-02-23T23:10:59Z"}`),
+			}
+		}),
+	})
+
+	resp, err := c.GetUserInfo(ctx, &GetUserInfoRequest{Token: "abc123"})
+	assert.NoError(t, err)
+	assert.Equal(t, "713936733", resp.Subject)
+	assert.Equal(t, "scottwht", resp.PreferredUsername)
+	assert.Equal(t, true, *resp.EmailVerified)
+	assert.Equal(t, int64(1645837869), resp.ExpiresAt.Unix())
+
+	bs, err := json.Marshal(resp.ExpiresAt)
+	assert.NoError(t, err)
+	assert.Equal(t, "1645837869", string(bs))
+}